@@ -7,15 +7,14 @@ import (
 	"gas_wells/internal/pkg/database"
 	"gas_wells/internal/pkg/logger"
 	"gas_wells/internal/repository"
+	"gas_wells/internal/server"
 	"gas_wells/internal/service"
+	migration "gas_wells/migration"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
-
-	"github.com/go-chi/chi"
-	"github.com/go-chi/chi/middleware"
 )
 
 func main() {
@@ -28,6 +27,12 @@ func main() {
 	// Инициализация логгера
 	log := logger.New(cfg.App.Env)
 
+	// Вне разработки сервер не запускается с небезопасным ключом подписи токенов
+	if err := cfg.Validate(); err != nil {
+		log.Error("invalid configuration", "error", err, "env", cfg.App.Env)
+		os.Exit(1)
+	}
+
 	// Инициализация базы данных
 	db, err := database.NewPostgres(database.Config{
 		Host:     cfg.Database.Host,
@@ -44,22 +49,77 @@ func main() {
 	}
 	defer db.Close()
 
-	// Инициализация репозитория
+	// Миграции схемы БД
+	if err := migration.NewMigrator(db.Pool, log).Run(context.Background()); err != nil {
+		log.Error("failed to apply migrations", "error", err)
+		os.Exit(1)
+	}
+
+	// Инициализация репозиториев
 	wellRepo := repository.NewWellRepo(db.Pool, log)
+	wellTestRepo := repository.NewWellTestRepo(db.Pool, log)
+	measurementRepo := repository.NewMeasurementRepo(db.Pool, log)
+	productionRepo := repository.NewProductionRepo(db.Pool, log)
+	surveyRepo := repository.NewPressureSurveyRepo(db.Pool, log)
+	fieldRepo := repository.NewFieldRepo(db.Pool, log)
+	networkRepo := repository.NewNetworkRepo(db.Pool, log)
+	userRepo := repository.NewUserRepo(db.Pool, log)
+	refreshTokenRepo := repository.NewRefreshTokenRepo(db.Pool, log)
+	auditRepo := repository.NewAuditRepo(db.Pool, log)
+
+	// Инициализация сервисов
+	auditService := service.NewAuditService(auditRepo, log)
+	wellService := service.NewWellService(wellRepo, auditService, log)
+	wellTestService := service.NewWellTestService(wellTestRepo, wellService, log)
+	measurementService := service.NewMeasurementService(measurementRepo, wellService, log)
+	productionService := service.NewProductionService(productionRepo, wellService, log)
+	matBalService := service.NewMaterialBalanceService(surveyRepo, wellService, log)
+	fieldService := service.NewFieldService(fieldRepo, wellRepo, log)
+	networkService := service.NewNetworkService(networkRepo, fieldService, wellService, log)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, cfg.JWT, log)
+
+	if cfg.JWT.Secret == config.DefaultJWTSecret {
+		log.Warn("JWT_SECRET is not set, using the insecure default key")
+	}
+	// Учетная запись администратора для первого входа
+	if cfg.App.AdminEmail != "" {
+		if err := authService.EnsureAdmin(context.Background(), cfg.App.AdminEmail, cfg.App.AdminPassword); err != nil {
+			log.Error("failed to create admin user", "error", err)
+			os.Exit(1)
+		}
+	}
 
-	// Инициализация сервиса
-	wellService := service.NewWellService(wellRepo, log)
+	// Загрузка шаблонов
+	templates, err := handler.LoadTemplates(cfg.App.TemplatesDir)
+	if err != nil {
+		log.Error("failed to load templates", "error", err)
+		os.Exit(1)
+	}
 
-	// Инициализация обработчика
-	wellHandler := handler.NewWellHandler(wellService, log)
+	// Инициализация обработчиков
+	handlers := server.Handlers{
+		Well:        handler.NewWellHandler(wellService, fieldService, templates, log),
+		WellTest:    handler.NewWellTestHandler(wellTestService, templates, log),
+		Measurement: handler.NewMeasurementHandler(measurementService, templates, log),
+		Production:  handler.NewProductionHandler(productionService, templates, log),
+		MatBal:      handler.NewMaterialBalanceHandler(matBalService, templates, log),
+		Field:       handler.NewFieldHandler(fieldService, templates, log),
+		Network:     handler.NewNetworkHandler(networkService, fieldService, templates, log),
+		Docs:        handler.NewDocsHandler(templates, log),
+		Auth:        handler.NewAuthHandler(authService, templates, cfg.App.SecureCookies, log),
+		User:        handler.NewUserHandler(authService, templates, log),
+		Audit:       handler.NewAuditHandler(auditService, templates, log),
+	}
 
-	// Настройка сервера
-	router := setupRouter(wellHandler, log)
+	// Настройка маршрутов
+	srv := server.New(log)
+	srv.SetupRoutes(handlers)
+	srv.ServeStatic(cfg.App.StaticDir)
 
 	// HTTP сервер
-	server := &http.Server{
+	httpServer := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
-		Handler:      router,
+		Handler:      srv.Handler(),
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
@@ -68,7 +128,7 @@ func main() {
 	// Graceful shutdown
 	go func() {
 		log.Info("starting server", "port", cfg.Server.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Error("server error", "error", err)
 			os.Exit(1)
 		}
@@ -83,54 +143,9 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
+	if err := httpServer.Shutdown(ctx); err != nil {
 		log.Error("server shutdown error", "error", err)
 	}
 
 	log.Info("server stopped gracefully")
 }
-
-func setupRouter(wellHandler *handler.WellHandler, log logger.Logger) *chi.Mux {
-	r := chi.NewRouter()
-
-	// Middleware
-	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
-	r.Use(loggerMiddleware(log))
-	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
-
-	// Маршруты
-	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte("Welcome to Gas Wells API"))
-	})
-
-	r.Route("/wells", func(r chi.Router) {
-		r.Get("/", wellHandler.ListWells)
-		r.Post("/", wellHandler.CreateWell)
-		r.Get("/{id}", wellHandler.GetWell)
-		r.Put("/{id}", wellHandler.UpdateWell)
-		r.Delete("/{id}", wellHandler.DeleteWell)
-	})
-
-	return r
-}
-
-func loggerMiddleware(log logger.Logger) func(next http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			start := time.Now()
-			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
-
-			defer func() {
-				log.Info("request completed",
-					"method", r.Method,
-					"path", r.URL.Path,
-					"status", ww.Status(),
-					"duration", time.Since(start))
-			}()
-
-			next.ServeHTTP(ww, r)
-		})
-	}
-}
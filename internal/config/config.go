@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"time"
@@ -14,8 +16,15 @@ type Config struct {
 }
 
 type AppConfig struct {
-	Env      string
-	LogLevel string
+	Env          string
+	LogLevel     string
+	TemplatesDir string
+	StaticDir    string
+	// Администратор, создаваемый при запуске, если его еще нет
+	AdminEmail    string
+	AdminPassword string
+	// Cookie сессии только по HTTPS (COOKIE_SECURE); по умолчанию - вне разработки
+	SecureCookies bool
 }
 
 type ServerConfig struct {
@@ -43,11 +52,24 @@ type JWTConfig struct {
 	RefreshTTL time.Duration
 }
 
+// DefaultJWTSecret - ключ подписи токенов по умолчанию, только для разработки
+const DefaultJWTSecret = "default-secret-key"
+
+// MinJWTSecretLength - наименьшая длина ключа подписи токенов вне разработки, байт
+const MinJWTSecretLength = 32
+
+// EnvDevelopment - окружение разработки (APP_ENV)
+const EnvDevelopment = "development"
+
 func Load() (*Config, error) {
 	cfg := &Config{
 		App: AppConfig{
-			Env:      getEnv("APP_ENV", "development"),
-			LogLevel: getEnv("LOG_LEVEL", "debug"),
+			Env:           getEnv("APP_ENV", EnvDevelopment),
+			LogLevel:      getEnv("LOG_LEVEL", "debug"),
+			TemplatesDir:  getEnv("TEMPLATES_DIR", "cmd/server/web/templates"),
+			StaticDir:     getEnv("STATIC_DIR", "static"),
+			AdminEmail:    getEnv("ADMIN_EMAIL", ""),
+			AdminPassword: getEnv("ADMIN_PASSWORD", ""),
 		},
 		Server: ServerConfig{
 			Port:         getEnv("SERVER_PORT", "8080"),
@@ -67,15 +89,32 @@ func Load() (*Config, error) {
 			ConnMaxLifetime: parseDuration(getEnv("DB_CONN_MAX_LIFETIME", "1h")),
 		},
 		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", "default-secret-key"),
+			Secret:     getEnv("JWT_SECRET", DefaultJWTSecret),
 			AccessTTL:  parseDuration(getEnv("JWT_ACCESS_TTL", "15m")),
 			RefreshTTL: parseDuration(getEnv("JWT_REFRESH_TTL", "24h")),
 		},
 	}
 
+	cfg.App.SecureCookies = parseBool(getEnv("COOKIE_SECURE", ""), cfg.App.Env != EnvDevelopment)
+
 	return cfg, nil
 }
 
+// Validate проверяет настройки безопасности: вне разработки ключ подписи
+// токенов должен быть задан и не короче MinJWTSecretLength
+func (c *Config) Validate() error {
+	if c.App.Env == EnvDevelopment {
+		return nil
+	}
+	if c.JWT.Secret == DefaultJWTSecret {
+		return errors.New("JWT_SECRET must be set outside development")
+	}
+	if len(c.JWT.Secret) < MinJWTSecretLength {
+		return fmt.Errorf("JWT_SECRET must be at least %d bytes outside development", MinJWTSecretLength)
+	}
+	return nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -88,6 +127,15 @@ func parseInt(s string) int {
 	return v
 }
 
+// parseBool - логическое значение; пусто или ошибка - defaultValue
+func parseBool(s string, defaultValue bool) bool {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return defaultValue
+	}
+	return v
+}
+
 func parseDuration(s string) time.Duration {
 	d, err := time.ParseDuration(s)
 	if err != nil {
@@ -0,0 +1,33 @@
+// internal/config/config_test.go
+package config_test
+
+import (
+	"gas_wells/internal/config"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	strong := strings.Repeat("k", config.MinJWTSecretLength)
+	for _, tc := range []struct {
+		env, secret string
+		valid       bool
+	}{
+		{config.EnvDevelopment, config.DefaultJWTSecret, true},
+		{config.EnvDevelopment, "short", true},
+		{"production", config.DefaultJWTSecret, false},
+		{"production", strong[1:], false},
+		{"production", strong, true},
+		{"staging", "", false},
+	} {
+		cfg := &config.Config{App: config.AppConfig{Env: tc.env}, JWT: config.JWTConfig{Secret: tc.secret}}
+		err := cfg.Validate()
+		if tc.valid {
+			assert.NoError(t, err, "%s, %d bytes", tc.env, len(tc.secret))
+		} else {
+			assert.Error(t, err, "%s, %d bytes", tc.env, len(tc.secret))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// Действия, записываемые в журнал аудита
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditWell - тип объекта журнала аудита для скважин
+const AuditWell = "well"
+
+// FieldChange - изменение поля: значения до и после по JSON-имени поля.
+// При создании Old равно nil, при удалении - New.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// AuditEntry - запись журнала аудита: кто, когда и как изменил объект
+type AuditEntry struct {
+	ID         int64         `json:"id"`
+	EntityType string        `json:"entity_type"` // well
+	EntityID   int           `json:"entity_id"`
+	EntityName string        `json:"entity_name"` // Название объекта на момент изменения
+	Action     string        `json:"action"`      // create, update, delete
+	ActorID    *int          `json:"actor_id"`    // nil - изменение без входа пользователя (служебное)
+	Actor      string        `json:"actor"`       // Email пользователя на момент изменения
+	Created    time.Time     `json:"created"`
+	Changes    []FieldChange `json:"changes"`
+}
+
+// AuditFilter - условия выборки журнала аудита; нулевые значения не ограничивают выборку
+type AuditFilter struct {
+	EntityType string
+	EntityID   int
+	Action     string
+	Actor      string    // Часть email пользователя
+	From       time.Time // Начало интервала, включительно
+	To         time.Time // Конец интервала, не включительно
+	Limit      int
+	Offset     int
+}
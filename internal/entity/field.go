@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// Field - месторождение
+type Field struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Created     time.Time `json:"created"`
+}
+
+// Pad - куст скважин месторождения
+type Pad struct {
+	ID      int       `json:"id"`
+	FieldID int       `json:"field_id"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+}
+
+// GroupSummary - сводные показатели группы скважин (куста или месторождения)
+type GroupSummary struct {
+	ID       int            `json:"id"`
+	Name     string         `json:"name"`
+	Wells    int            `json:"wells"`     // Число скважин
+	TotalQ   float64        `json:"total_q"`   // Суммарный дебит, м³/сут
+	AvgPbuf  float64        `json:"avg_pbuf"`  // Среднее буферное давление, Па
+	ByStatus map[string]int `json:"by_status"` // Число скважин в каждом статусе
+}
@@ -0,0 +1,117 @@
+package entity
+
+import "time"
+
+// Состояния жизненного цикла скважины (Well.Status)
+const (
+	StatusDrilling  = "drilling"  // Бурение
+	StatusCompleted = "completed" // Закончена бурением, освоение
+	StatusProducing = "producing" // В добыче
+	StatusShutIn    = "shut-in"   // Остановлена
+	StatusWorkover  = "workover"  // Капитальный ремонт
+	StatusSuspended = "suspended" // Консервация
+	StatusAbandoned = "abandoned" // Ликвидирована
+)
+
+// WellStatuses - состояния в порядке жизненного цикла
+var WellStatuses = []string{
+	StatusDrilling, StatusCompleted, StatusProducing, StatusShutIn,
+	StatusWorkover, StatusSuspended, StatusAbandoned,
+}
+
+var wellStatusNames = map[string]string{
+	StatusDrilling:  "бурение",
+	StatusCompleted: "освоение",
+	StatusProducing: "в добыче",
+	StatusShutIn:    "остановлена",
+	StatusWorkover:  "в ремонте",
+	StatusSuspended: "в консервации",
+	StatusAbandoned: "ликвидирована",
+}
+
+// wellTransitions - допустимые переходы между состояниями.
+// Ликвидация необратима.
+var wellTransitions = map[string][]string{
+	StatusDrilling:  {StatusCompleted, StatusSuspended, StatusAbandoned},
+	StatusCompleted: {StatusProducing, StatusShutIn, StatusSuspended, StatusAbandoned},
+	StatusProducing: {StatusShutIn, StatusWorkover},
+	StatusShutIn:    {StatusProducing, StatusWorkover, StatusSuspended, StatusAbandoned},
+	StatusWorkover:  {StatusProducing, StatusShutIn, StatusSuspended, StatusAbandoned},
+	StatusSuspended: {StatusShutIn, StatusWorkover, StatusAbandoned},
+	StatusAbandoned: nil,
+}
+
+// ValidWellStatus - состояние известно
+func ValidWellStatus(status string) bool {
+	_, ok := wellTransitions[status]
+	return ok
+}
+
+// WellStatusName - название состояния для отображения
+func WellStatusName(status string) string {
+	if name, ok := wellStatusNames[status]; ok {
+		return name
+	}
+	return status
+}
+
+// NextStatuses - состояния, в которые допустим переход из from
+func NextStatuses(from string) []string {
+	return wellTransitions[from]
+}
+
+// CanTransition - переход из from в to допустим
+func CanTransition(from, to string) bool {
+	for _, next := range wellTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusChange - переход скважины в другое состояние. Первая запись
+// истории скважины - начальное состояние, у нее From пустой.
+type StatusChange struct {
+	ID      int       `json:"id"`
+	WellID  int       `json:"well_id"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Reason  string    `json:"reason"`
+	Date    time.Time `json:"date"`     // Дата перехода
+	ActorID *int      `json:"actor_id"` // nil - изменение без входа пользователя
+	Actor   string    `json:"actor"`    // Email пользователя на момент перехода
+	Created time.Time `json:"created"`  // Время записи перехода
+}
+
+// StatusDuration - время, проведенное скважиной в состоянии
+type StatusDuration struct {
+	Status string  `json:"status"`
+	Days   float64 `json:"days"` // Суммарно за все периоды в этом состоянии, сут
+}
+
+// TimeInStatus суммирует время в каждом состоянии по истории переходов
+// (в порядке дат); последнее состояние длится до момента now.
+// Состояния перечислены в порядке WellStatuses.
+func TimeInStatus(history []*StatusChange, now time.Time) []StatusDuration {
+	total := make(map[string]time.Duration)
+	for i, change := range history {
+		end := now
+		if i+1 < len(history) {
+			end = history[i+1].Date
+		}
+		d := end.Sub(change.Date)
+		if d < 0 {
+			d = 0
+		}
+		total[change.To] += d
+	}
+
+	durations := []StatusDuration{}
+	for _, status := range WellStatuses {
+		if d, ok := total[status]; ok {
+			durations = append(durations, StatusDuration{Status: status, Days: d.Hours() / 24})
+		}
+	}
+	return durations
+}
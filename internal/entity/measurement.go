@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// MeasurementResolution - шаг осреднения временного ряда замеров
+type MeasurementResolution string
+
+const (
+	ResolutionRaw  MeasurementResolution = ""     // Исходные замеры
+	ResolutionHour MeasurementResolution = "hour" // Средние за час
+	ResolutionDay  MeasurementResolution = "day"  // Средние за сутки
+)
+
+// Measurement - замер параметров работы скважины в момент времени.
+// Не измеренные параметры равны nil.
+type Measurement struct {
+	ID         int       `json:"id,omitempty"`
+	WellID     int       `json:"well_id"`
+	MeasuredAt time.Time `json:"measured_at"`
+	Pbuf       *float64  `json:"pbuf,omitempty"`    // Буферное давление, Па
+	Ptb        *float64  `json:"ptb,omitempty"`     // Затрубное давление, Па
+	Q          *float64  `json:"q,omitempty"`       // Дебит газа, м³/сут
+	TempUst    *float64  `json:"tempust,omitempty"` // Температура на устье, К
+	Samples    int       `json:"samples,omitempty"` // Число осредненных замеров (для hour/day)
+	Created    time.Time `json:"created,omitempty"`
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// NetworkNodeKind - тип узла сети сбора
+type NetworkNodeKind string
+
+const (
+	NodeWell     NetworkNodeKind = "well"     // Устье скважины
+	NodeJunction NetworkNodeKind = "junction" // Тройник, точка врезки шлейфа
+	NodeHeader   NetworkNodeKind = "header"   // Коллектор куста с заданным давлением
+)
+
+// ParseNetworkNodeKind возвращает тип узла по имени; пустая строка - тройник
+func ParseNetworkNodeKind(s string) (NetworkNodeKind, error) {
+	switch k := NetworkNodeKind(s); k {
+	case "":
+		return NodeJunction, nil
+	case NodeWell, NodeJunction, NodeHeader:
+		return k, nil
+	}
+	return "", fmt.Errorf("unknown network node kind %q", s)
+}
+
+// NetworkNode - узел сети сбора куста
+type NetworkNode struct {
+	ID      int             `json:"id"`
+	PadID   int             `json:"pad_id"`
+	Name    string          `json:"name"`
+	Kind    NetworkNodeKind `json:"kind"`
+	WellID  *int            `json:"well_id"` // Только для узлов типа well
+	Created time.Time       `json:"created"`
+}
+
+// PipeSegment - участок трубопровода между узлами; газ течет из FromNodeID в ToNodeID
+type PipeSegment struct {
+	ID         int       `json:"id"`
+	PadID      int       `json:"pad_id"`
+	FromNodeID int       `json:"from_node_id"`
+	ToNodeID   int       `json:"to_node_id"`
+	Length     float64   `json:"length"`    // Длина, м
+	Diameter   float64   `json:"diameter"`  // Внутренний диаметр, м
+	Roughness  float64   `json:"roughness"` // Абсолютная шероховатость, м
+	Elevation  float64   `json:"elevation"` // Превышение конца участка над началом, м
+	Created    time.Time `json:"created"`
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// ProductionRecord - среднесуточный дебит скважины за дату (история добычи)
+type ProductionRecord struct {
+	ID      int       `json:"id,omitempty"`
+	WellID  int       `json:"well_id"`
+	Date    time.Time `json:"date"`
+	Q       float64   `json:"q"` // Дебит газа, м³/сут
+	Created time.Time `json:"created,omitempty"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// PressureSurvey - замер пластового давления с накопленной добычей на дату замера
+type PressureSurvey struct {
+	ID         int       `json:"id,omitempty"`
+	WellID     int       `json:"well_id"`
+	SurveyDate time.Time `json:"survey_date"`
+	Ppl        float64   `json:"ppl"` // Пластовое давление, Па
+	Gp         float64   `json:"gp"`  // Накопленная добыча газа, м³
+	Created    time.Time `json:"created,omitempty"`
+}
@@ -1,7 +1,83 @@
 package entity
 
+import "time"
+
+// Роли пользователей
+const (
+	RoleViewer   = "viewer"   // Просмотр
+	RoleEngineer = "engineer" // Ввод данных и расчеты
+	RoleAdmin    = "admin"    // Полный доступ
+)
+
+// Roles - роли в порядке расширения прав
+var Roles = []string{RoleViewer, RoleEngineer, RoleAdmin}
+
+// Permission - право на группу операций
+type Permission string
+
+const (
+	// PermRead - просмотр скважин, месторождений и исходных данных
+	PermRead Permission = "read"
+	// PermEdit - создание и изменение скважин, ввод исследований, замеров и истории
+	PermEdit Permission = "edit"
+	// PermCalculate - технологические расчеты и выгрузка их результатов
+	PermCalculate Permission = "calculate"
+	// PermDelete - удаление скважин, месторождений и кустов
+	PermDelete Permission = "delete"
+	// PermManageUsers - управление пользователями и ролями
+	PermManageUsers Permission = "manage_users"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleViewer:   {PermRead},
+	RoleEngineer: {PermRead, PermEdit, PermCalculate},
+	RoleAdmin:    {PermRead, PermEdit, PermCalculate, PermDelete, PermManageUsers},
+}
+
+// ValidRole - роль известна
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
 type User struct {
-	ID       int    `json:"id"`
-	Username string `json:"username"`
-	Role     string `json:"role"` // "admin" или "user"
+	ID           int       `json:"id"`
+	Email        string    `json:"email"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"` // viewer, engineer или admin
+	Created      time.Time `json:"created"`
+}
+
+// RolesWith - роли, которым предоставлено право p
+func RolesWith(p Permission) []string {
+	var roles []string
+	for _, role := range Roles {
+		if (&User{Role: role}).Can(p) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// Can - у пользователя есть право p; для nil (вход не выполнен) - false
+func (u *User) Can(p Permission) bool {
+	if u == nil {
+		return false
+	}
+	for _, granted := range rolePermissions[u.Role] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// RefreshToken - выданный refresh-токен; отозванный токен не принимается
+type RefreshToken struct {
+	ID        string // jti токена
+	UserID    int
+	Expires   time.Time
+	RevokedAt *time.Time // Время отзыва (выход или обмен на новый токен)
+	RotatedAt *time.Time // Время обмена на новый токен; nil - не обменивался
 }
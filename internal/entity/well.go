@@ -17,15 +17,42 @@ type Well struct {
 	Q         float64   `json:"q"`         // Дебит газа, м³/сут
 	Roughness float64   `json:"roughness"` // Шероховатость трубы, м
 	Diameter  float64   `json:"diameter"`  // Диаметр НКТ, м
-	A         float64   `json:"a"`         // пластовый коэф-т
-	B         float64   `json:"b"`         // пластовый коэф-т
+	A         float64   `json:"a"`         // Коэф-т фильтрационного сопротивления, МПа²·сут/тыс.м³
+	B         float64   `json:"b"`         // Коэф-т фильтрационного сопротивления, (МПа·сут/тыс.м³)²
 	Mu        float64   `json:"mu"`        // вязкость газа мПа*с
-	WGF       float64   `json:"wgf"`       // водогазовый фактор
+	WGF       float64   `json:"wgf"`       // Водогазовый фактор, см³/м³
 	Rog       float64   `json:"rog"`       //Плотность воды, кг/м3
 	Hw        float64   `json:"hw"`        // Высота столба ГЖС, м
-	Qmin      float64   `json:"qmin"`      // м3/сут
-	Pmax      float64   `json:"pmax"`
-	Status    string    `json:"status"` //
+	Qmin      float64   `json:"qmin"`      // Критический дебит выноса жидкости, м3/сут
+	Pmax      float64   `json:"pmax"`      // Максимально допустимое давление, Па; вводится, не рассчитывается
+	Status    string    `json:"status"`    // Состояние жизненного цикла (StatusProducing и др.)
 	Created   time.Time `json:"created"`
 	Updated   time.Time `json:"updated"`
+
+	QminModel     string `json:"qmin_model"`     // Модель расчета Qmin: turner, coleman, li
+	LiquidLoading bool   `json:"liquid_loading"` // Риск самозадавливания: Q < Qmin
+	MuManual      bool   `json:"mu_manual"`      // Вязкость задана вручную, не пересчитывается
+	PzManual      bool   `json:"pz_manual"`      // Забойное давление замерено, не пересчитывается
+	PadID         *int   `json:"pad_id"`         // Куст; nil - скважина не привязана к кусту
+
+	Choke float64 `json:"choke"` // Диаметр штуцера, м (0 - штуцер не установлен)
+	Pline float64 `json:"pline"` // Давление в шлейфе после штуцера, Па
+
+	// Дата перехода в текущее состояние; заполняется из истории состояний
+	StatusSince *time.Time `json:"status_since,omitempty"`
+
+	// Расчетные признаки, не хранятся в БД
+	Warnings    []string `json:"warnings,omitempty"`
+	HydrateRisk string   `json:"hydrate_risk,omitempty"` // none, warning, high
+}
+
+// WellRevision - неизменяемая версия параметров скважины.
+// Каждое сохранение скважины создает новую версию.
+type WellRevision struct {
+	WellID   int       `json:"well_id"`
+	Revision int       `json:"revision"` // Номер версии, начиная с 1
+	Well     *Well     `json:"well"`     // Параметры скважины в этой версии
+	ActorID  *int      `json:"actor_id"` // nil - сохранение без входа пользователя
+	Actor    string    `json:"actor"`    // Email пользователя на момент сохранения
+	Created  time.Time `json:"created"`  // Начало действия версии
 }
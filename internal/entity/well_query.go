@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// WellSortKeys - ключи сортировки списка скважин
+var WellSortKeys = []string{
+	"name", "location", "status", "status_since", "pbuf", "q", "depth", "qmin", "created", "updated",
+}
+
+// ValidWellSortKey - ключ сортировки допустим
+func ValidWellSortKey(key string) bool {
+	for _, k := range WellSortKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Range - числовой диапазон, границы включаются; nil не ограничивает выборку
+type Range struct {
+	Min *float64
+	Max *float64
+}
+
+// WellQuery - условия выборки списка скважин; нулевые значения не ограничивают выборку
+type WellQuery struct {
+	Search string // Подстрока названия или местоположения, без учета регистра
+	Status string
+	Q      Range  // Дебит, м³/сут
+	Pbuf   Range  // Буферное давление, Па
+	Depth  Range  // Глубина, м
+	Sort   string // Ключ из WellSortKeys; пусто - name
+	Desc   bool
+	After  *WellCursor // Страница после позиции курсора
+	Before *WellCursor // Страница перед позицией курсора
+	Limit  int         // 0 - все скважины без разбиения на страницы
+}
+
+// WellPage - страница списка скважин
+type WellPage struct {
+	Wells []*Well `json:"wells"`
+	Total int     `json:"total"`          // Скважин по условиям выборки на всех страницах
+	Next  string  `json:"next,omitempty"` // Курсор следующей страницы (параметр after)
+	Prev  string  `json:"prev,omitempty"` // Курсор предыдущей страницы (параметр before)
+}
+
+// WellCursor - позиция в списке скважин: значение столбца сортировки
+// в текстовом виде СУБД и ID скважины
+type WellCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+// ErrInvalidCursor - курсор поврежден или получен при другой сортировке
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// Encode - курсор в виде строки для параметра запроса
+func (c WellCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeWellCursor разбирает курсор, полученный при сортировке sort.
+// Значение проверяется по типу столбца сортировки, чтобы измененный
+// курсор не доходил до СУБД.
+func DecodeWellCursor(s, sort string) (*WellCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c WellCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != sort || c.ID <= 0 || !validCursorValue(sort, c.Value) {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// validCursorValue - значение столбца сортировки sort в текстовом виде СУБД
+func validCursorValue(sort, value string) bool {
+	switch sort {
+	case "pbuf", "q", "depth", "qmin":
+		// double precision; шестнадцатеричную запись СУБД не принимает
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil && !strings.ContainsAny(value, "xX_")
+	case "status_since":
+		return value == "-infinity" || validTime("2006-01-02", value)
+	case "created", "updated":
+		return value == "-infinity" || validTime("2006-01-02 15:04:05.999999", value)
+	default:
+		return utf8.ValidString(value) && !strings.ContainsRune(value, 0)
+	}
+}
+
+func validTime(layout, value string) bool {
+	_, err := time.Parse(layout, value)
+	return err == nil
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// WellTestType - вид газодинамического исследования
+type WellTestType string
+
+const (
+	WellTestBackPressure WellTestType = "back-pressure" // Метод установившихся отборов
+	WellTestIsochronal   WellTestType = "isochronal"    // Изохронный метод
+)
+
+// WellTest - исследование скважины на нескольких режимах
+type WellTest struct {
+	ID       int             `json:"id"`
+	WellID   int             `json:"well_id"`
+	TestDate time.Time       `json:"test_date"`
+	Type     WellTestType    `json:"type"`
+	Points   []WellTestPoint `json:"points"`
+	Created  time.Time       `json:"created"`
+}
+
+// WellTestPoint - замер на одном режиме работы
+type WellTestPoint struct {
+	Q   float64 `json:"q"`   // Дебит газа, м³/сут
+	Pz  float64 `json:"pz"`  // Забойное давление, Па
+	Ppl float64 `json:"ppl"` // Пластовое давление перед режимом, Па
+}
@@ -0,0 +1,133 @@
+// internal/handler/audit_handler.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// auditPageSize - записей журнала на странице
+const auditPageSize = 50
+
+type AuditHandler struct {
+	baseHandler
+	service *service.AuditService
+}
+
+func NewAuditHandler(service *service.AuditService, templates Templates, log logger.Logger) *AuditHandler {
+	return &AuditHandler{
+		baseHandler: newBaseHandler(templates, log),
+		service:     service,
+	}
+}
+
+// ListAudit - журнал изменений с фильтрами. Параметры: entity_type, entity_id,
+// action (create, update, delete), actor (часть email), from, to (RFC 3339 или
+// ГГГГ-ММ-ДД; дата в to включается целиком), limit, offset.
+// Ответ в JSON для JSON-клиентов, иначе страница журнала.
+func (h *AuditHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	filter, err := auditFilter(r.URL.Query())
+	if err != nil {
+		h.respondFailure(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.service.List(r.Context(), filter)
+	if errors.Is(err, service.ErrInvalidAuditFilter) {
+		h.respondFailure(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to list audit log", "error", err)
+		h.respondFailure(w, r, "Failed to load audit log", http.StatusInternalServerError)
+		return
+	}
+	h.renderAudit(w, r, filter, entries)
+}
+
+func (h *AuditHandler) renderAudit(w http.ResponseWriter, r *http.Request, filter entity.AuditFilter, entries []*entity.AuditEntry) {
+	if wantsJSON(r) {
+		if entries == nil {
+			entries = []*entity.AuditEntry{}
+		}
+		h.respondJSON(w, entries, http.StatusOK)
+		return
+	}
+
+	// Ссылки на соседние страницы сохраняют фильтры запроса
+	page := func(offset int) string {
+		query := r.URL.Query()
+		query.Set("offset", strconv.Itoa(offset))
+		return "/audit?" + query.Encode()
+	}
+	data := map[string]interface{}{
+		"Title":   "Журнал изменений",
+		"Entries": entries,
+		"Query":   r.URL.Query(),
+	}
+	if filter.Offset > 0 {
+		data["PrevPage"] = page(max(filter.Offset-filter.Limit, 0))
+	}
+	if len(entries) == filter.Limit {
+		data["NextPage"] = page(filter.Offset + filter.Limit)
+	}
+	h.renderTemplate(w, r, "audit/list.html", data)
+}
+
+// WellHistory - изменения скважины в JSON, новые первыми.
+// История доступна и для удаленной скважины.
+func (h *AuditHandler) WellHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.service.WellHistory(r.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to load well history", "well_id", id, "error", err)
+		h.respondError(w, "Failed to load well history", http.StatusInternalServerError)
+		return
+	}
+	if entries == nil {
+		entries = []*entity.AuditEntry{}
+	}
+	h.respondJSON(w, entries, http.StatusOK)
+}
+
+// auditFilter разбирает параметры фильтра журнала
+func auditFilter(query url.Values) (entity.AuditFilter, error) {
+	filter := entity.AuditFilter{
+		EntityType: query.Get("entity_type"),
+		Action:     query.Get("action"),
+		Actor:      strings.TrimSpace(query.Get("actor")),
+		Limit:      auditPageSize,
+	}
+	var err error
+	for name, dst := range map[string]*int{"entity_id": &filter.EntityID, "limit": &filter.Limit, "offset": &filter.Offset} {
+		if v := query.Get(name); v != "" {
+			if *dst, err = strconv.Atoi(v); err != nil {
+				return filter, fmt.Errorf("invalid %s %q", name, v)
+			}
+		}
+	}
+	if filter.From, err = parseTime(query.Get("from")); err != nil {
+		return filter, err
+	}
+	to := query.Get("to")
+	if filter.To, err = parseTime(to); err != nil {
+		return filter, err
+	}
+	// Дата без времени в to включает весь день
+	if len(to) == len("2006-01-02") {
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+	return filter, nil
+}
@@ -1 +1,217 @@
+// internal/handler/aut_handler.go
 package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"gas_wells/internal/pkg/auth"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Cookie с токенами для браузерных сессий
+const (
+	accessCookie  = "access_token"
+	refreshCookie = "refresh_token"
+)
+
+type AuthHandler struct {
+	baseHandler
+	service       *service.AuthService
+	secureCookies bool
+}
+
+// NewAuthHandler создает обработчик входа. При secureCookies = true cookie
+// сессии передаются только по HTTPS, в том числе когда TLS завершается
+// на прокси перед сервером; иначе - только для запросов, принятых по TLS.
+func NewAuthHandler(service *service.AuthService, templates Templates, secureCookies bool, log logger.Logger) *AuthHandler {
+	return &AuthHandler{
+		baseHandler:   newBaseHandler(templates, log),
+		service:       service,
+		secureCookies: secureCookies,
+	}
+}
+
+// credentials - тело JSON-запроса входа
+type credentials struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// refreshRequest - тело JSON-запроса обмена и отзыва refresh-токена
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LoginForm - страница входа
+func (h *AuthHandler) LoginForm(w http.ResponseWriter, r *http.Request) {
+	h.renderLogin(w, r, safeRedirect(r.URL.Query().Get("next")), "", http.StatusOK)
+}
+
+// Login - вход по email и паролю. JSON-клиент получает токены в теле ответа,
+// браузер - в HttpOnly cookie с переходом на запрошенную страницу.
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var creds credentials
+	asJSON := wantsJSON(r)
+	if asJSON {
+		if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+			h.respondError(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			h.renderError(w, r, "Invalid form data", http.StatusBadRequest)
+			return
+		}
+		creds = credentials{Email: r.FormValue("email"), Password: r.FormValue("password")}
+	}
+	next := safeRedirect(r.FormValue("next"))
+
+	session, err := h.service.Login(r.Context(), creds.Email, creds.Password)
+	if err != nil {
+		status, message := http.StatusInternalServerError, "Failed to sign in"
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			status, message = http.StatusUnauthorized, "Invalid email or password"
+		} else {
+			h.logger.Error("failed to sign in", "error", err)
+		}
+		if asJSON {
+			h.respondError(w, message, status)
+		} else {
+			h.renderLogin(w, r, next, message, status)
+		}
+		return
+	}
+
+	h.logger.Info("user signed in", "user_id", session.User.ID)
+	if asJSON {
+		h.respondJSON(w, session, http.StatusOK)
+		return
+	}
+	h.setSessionCookies(w, r, session.Tokens)
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
+
+// Refresh - обмен refresh-токена на новую пару. Токен берется из тела
+// JSON-запроса, а при его отсутствии - из cookie.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	token, fromBody, err := refreshTokenFrom(r)
+	if err != nil {
+		h.respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.service.Refresh(r.Context(), token)
+	if errors.Is(err, service.ErrInvalidToken) {
+		h.clearSessionCookies(w, r)
+		unauthorized(w, "Invalid or expired refresh token")
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to refresh token", "error", err)
+		h.respondError(w, "Failed to refresh token", http.StatusInternalServerError)
+		return
+	}
+
+	if !fromBody {
+		h.setSessionCookies(w, r, session.Tokens)
+	}
+	h.respondJSON(w, session, http.StatusOK)
+}
+
+// Logout - выход: refresh-токен отзывается, cookie сессии удаляются
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	token, _, err := refreshTokenFrom(r)
+	if err != nil {
+		h.respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Logout(r.Context(), token); err != nil {
+		h.logger.Error("failed to revoke refresh token", "error", err)
+	}
+	h.clearSessionCookies(w, r)
+
+	if wantsJSON(r) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	http.Redirect(w, r, "/auth/login", http.StatusSeeOther)
+}
+
+// Me - текущий пользователь
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, auth.UserFromContext(r.Context()), http.StatusOK)
+}
+
+func (h *AuthHandler) renderLogin(w http.ResponseWriter, r *http.Request, next, message string, statusCode int) {
+	if statusCode != http.StatusOK {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(statusCode)
+	}
+	h.renderTemplate(w, r, "auth/login.html", map[string]interface{}{
+		"Title": "Вход",
+		"Next":  next,
+		"Error": message,
+	})
+}
+
+// refreshTokenFrom - refresh-токен из тела JSON-запроса или из cookie;
+// fromBody сообщает, что токен передан в теле
+func refreshTokenFrom(r *http.Request) (token string, fromBody bool, err error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") && r.ContentLength != 0 {
+		var req refreshRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return "", false, err
+		}
+		if req.RefreshToken != "" {
+			return req.RefreshToken, true, nil
+		}
+	}
+	if cookie, err := r.Cookie(refreshCookie); err == nil {
+		return cookie.Value, false, nil
+	}
+	return "", false, nil
+}
+
+// safeRedirect допускает переход только на локальный путь,
+// чтобы параметр next нельзя было использовать для перехода на чужой сайт
+func safeRedirect(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/wells"
+	}
+	return next
+}
+
+func (h *AuthHandler) setSessionCookies(w http.ResponseWriter, r *http.Request, tokens service.TokenPair) {
+	http.SetCookie(w, h.sessionCookie(r, accessCookie, tokens.AccessToken, tokens.AccessExpires))
+	http.SetCookie(w, h.sessionCookie(r, refreshCookie, tokens.RefreshToken, tokens.RefreshExpires))
+}
+
+func (h *AuthHandler) clearSessionCookies(w http.ResponseWriter, r *http.Request) {
+	for _, name := range []string{accessCookie, refreshCookie} {
+		cookie := h.sessionCookie(r, name, "", time.Unix(0, 0))
+		cookie.MaxAge = -1
+		http.SetCookie(w, cookie)
+	}
+}
+
+func (h *AuthHandler) sessionCookie(r *http.Request, name, value string, expires time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   h.secureCookies || r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// loginURL - страница входа с возвратом на текущий адрес
+func loginURL(r *http.Request) string {
+	return "/auth/login?next=" + url.QueryEscape(r.URL.RequestURI())
+}
@@ -0,0 +1,291 @@
+// internal/handler/aut_handler_test.go
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"gas_wells/internal/config"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/handler"
+	"gas_wells/internal/pkg/auth"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUserRepo - пользователи в памяти
+type memoryUserRepo struct {
+	users []*entity.User
+}
+
+func (r *memoryUserRepo) Create(_ context.Context, user *entity.User) error {
+	user.ID = len(r.users) + 1
+	clone := *user
+	r.users = append(r.users, &clone)
+	return nil
+}
+
+func (r *memoryUserRepo) GetByEmail(_ context.Context, email string) (*entity.User, error) {
+	for _, u := range r.users {
+		if strings.EqualFold(u.Email, email) {
+			clone := *u
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepo) GetByID(_ context.Context, id int) (*entity.User, error) {
+	if id < 1 || id > len(r.users) {
+		return nil, nil
+	}
+	clone := *r.users[id-1]
+	return &clone, nil
+}
+
+func (r *memoryUserRepo) List(_ context.Context) ([]*entity.User, error) {
+	users := make([]*entity.User, 0, len(r.users))
+	for _, u := range r.users {
+		clone := *u
+		users = append(users, &clone)
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepo) UpdateRole(_ context.Context, id int, role string) error {
+	r.users[id-1].Role = role
+	return nil
+}
+
+// memoryTokenRepo - refresh-токены в памяти
+type memoryTokenRepo struct {
+	tokens map[string]*entity.RefreshToken
+}
+
+func (r *memoryTokenRepo) Create(_ context.Context, token *entity.RefreshToken) error {
+	clone := *token
+	r.tokens[token.ID] = &clone
+	return nil
+}
+
+func (r *memoryTokenRepo) Get(_ context.Context, id string) (*entity.RefreshToken, error) {
+	return r.tokens[id], nil
+}
+
+func (r *memoryTokenRepo) Revoke(_ context.Context, id string) (bool, error) {
+	token, ok := r.tokens[id]
+	if !ok || token.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.RevokedAt = &now
+	return true, nil
+}
+
+func (r *memoryTokenRepo) Rotate(ctx context.Context, id string) (bool, error) {
+	revoked, err := r.Revoke(ctx, id)
+	if revoked {
+		r.tokens[id].RotatedAt = r.tokens[id].RevokedAt
+	}
+	return revoked, err
+}
+
+func (r *memoryTokenRepo) RevokeAll(ctx context.Context, _ int) error {
+	for id, token := range r.tokens {
+		_, _ = r.Revoke(ctx, id)
+		token.RotatedAt = nil
+	}
+	return nil
+}
+
+// newAuthRouter - маршруты входа и защищенные страница и API;
+// пользователи admin@example.com и viewer@example.com с паролем Secret#123
+func newAuthRouter(t *testing.T) http.Handler {
+	t.Helper()
+	return newAuthRouterWith(t, false)
+}
+
+// newAuthRouterWith - маршруты newAuthRouter с настройкой Secure для cookie сессии
+func newAuthRouterWith(t *testing.T, secureCookies bool) http.Handler {
+	t.Helper()
+	log := logger.New("test")
+	svc := service.NewAuthService(&memoryUserRepo{}, &memoryTokenRepo{tokens: map[string]*entity.RefreshToken{}}, config.JWTConfig{
+		Secret: "test-secret", AccessTTL: 15 * time.Minute, RefreshTTL: time.Hour,
+	}, log)
+	require.NoError(t, svc.EnsureAdmin(context.Background(), "admin@example.com", "Secret#123"))
+	_, err := svc.CreateUser(context.Background(),
+		&entity.User{Email: "viewer@example.com", Username: "viewer", Role: entity.RoleViewer}, "Secret#123")
+	require.NoError(t, err)
+
+	h := handler.NewAuthHandler(svc, nil, secureCookies, log)
+	whoami := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(auth.UserFromContext(r.Context()).Email))
+	}
+	r := chi.NewRouter()
+	r.Post("/auth/login", h.Login)
+	r.Post("/auth/refresh", h.Refresh)
+	r.Post("/auth/logout", h.Logout)
+	r.Group(func(r chi.Router) {
+		r.Use(h.RequireAuth)
+		r.Get("/wells", whoami)
+		r.Get("/api/v1/wells", whoami)
+		r.With(h.RequireByMethod).Delete("/api/v1/wells/{id}", whoami)
+		r.With(h.Require(entity.PermEdit)).Post("/api/v1/wells", whoami)
+	})
+	return r
+}
+
+func serve(router http.Handler, req *http.Request, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func formLogin(router http.Handler, next string) *httptest.ResponseRecorder {
+	form := url.Values{"email": {"admin@example.com"}, "password": {"Secret#123"}, "next": {next}}
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return serve(router, req)
+}
+
+func cookieNamed(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, c := range cookies {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestRequireAuth(t *testing.T) {
+	router := newAuthRouter(t)
+
+	rec := serve(router, httptest.NewRequest(http.MethodGet, "/wells?page=2", nil))
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/auth/login?next=%2Fwells%3Fpage%3D2", rec.Header().Get("Location"))
+
+	rec = serve(router, httptest.NewRequest(http.MethodGet, "/api/v1/wells", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+
+	// Вход JSON-клиента и запросы с заголовком Authorization
+	rec = apiRequest(t, router, http.MethodPost, "/auth/login", map[string]string{"email": "admin@example.com", "password": "wrong"})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = apiRequest(t, router, http.MethodPost, "/auth/login", map[string]string{"email": "admin@example.com", "password": "Secret#123"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var session service.Session
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &session))
+	assert.Equal(t, entity.RoleAdmin, session.User.Role)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/wells", nil)
+	req.Header.Set("Authorization", "Bearer "+session.Tokens.AccessToken)
+	rec = serve(router, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "admin@example.com", rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/wells", nil)
+	req.Header.Set("Authorization", "Bearer "+session.Tokens.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, serve(router, req).Code)
+
+	rec = apiRequest(t, router, http.MethodPost, "/auth/refresh", map[string]string{"refresh_token": session.Tokens.RefreshToken})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = apiRequest(t, router, http.MethodPost, "/auth/logout", map[string]string{"refresh_token": session.Tokens.RefreshToken})
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+}
+
+func TestRequireAuthCookies(t *testing.T) {
+	router := newAuthRouter(t)
+
+	assert.Equal(t, "/wells", formLogin(router, "//evil.example").Header().Get("Location"))
+	rec := formLogin(router, "/wells?page=2")
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/wells?page=2", rec.Header().Get("Location"))
+	access := cookieNamed(rec.Result().Cookies(), "access_token")
+	refresh := cookieNamed(rec.Result().Cookies(), "refresh_token")
+	require.NotNil(t, access)
+	require.NotNil(t, refresh)
+	assert.True(t, access.HttpOnly)
+	assert.False(t, access.Secure)
+
+	rec = serve(router, httptest.NewRequest(http.MethodGet, "/wells", nil), access)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Без access-токена сессия продлевается по refresh-токену
+	rec = serve(router, httptest.NewRequest(http.MethodGet, "/wells", nil), refresh)
+	require.Equal(t, http.StatusOK, rec.Code)
+	renewed := cookieNamed(rec.Result().Cookies(), "refresh_token")
+	require.NotNil(t, renewed)
+	assert.NotEqual(t, refresh.Value, renewed.Value)
+
+	// Другая вкладка, открытая одновременно, продлевает сессию тем же
+	// cookie и не завершает сессию первой
+	rec = serve(router, httptest.NewRequest(http.MethodGet, "/wells", nil), refresh)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, cookieNamed(rec.Result().Cookies(), "refresh_token"))
+	rec = serve(router, httptest.NewRequest(http.MethodGet, "/wells", nil), renewed)
+	require.Equal(t, http.StatusOK, rec.Code)
+	renewed = cookieNamed(rec.Result().Cookies(), "refresh_token")
+	require.NotNil(t, renewed)
+
+	rec = serve(router, httptest.NewRequest(http.MethodPost, "/auth/logout", nil), renewed)
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/auth/login", rec.Header().Get("Location"))
+	assert.Negative(t, cookieNamed(rec.Result().Cookies(), "refresh_token").MaxAge)
+
+	rec = serve(router, httptest.NewRequest(http.MethodGet, "/wells", nil), renewed)
+	assert.Equal(t, http.StatusSeeOther, rec.Code, "revoked session redirects to login")
+}
+
+// accessToken - вход JSON-клиента, access-токен пользователя email
+func accessToken(t *testing.T, router http.Handler, email string) string {
+	t.Helper()
+	rec := apiRequest(t, router, http.MethodPost, "/auth/login", map[string]string{"email": email, "password": "Secret#123"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var session service.Session
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &session))
+	return session.Tokens.AccessToken
+}
+
+func TestRequirePermission(t *testing.T) {
+	router := newAuthRouter(t)
+	viewer := accessToken(t, router, "viewer@example.com")
+	admin := accessToken(t, router, "admin@example.com")
+
+	request := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return serve(router, req)
+	}
+
+	assert.Equal(t, http.StatusOK, request(http.MethodGet, "/api/v1/wells", viewer).Code)
+	rec := request(http.MethodDelete, "/api/v1/wells/1", viewer)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"error"`)
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/api/v1/wells", viewer).Code)
+
+	assert.Equal(t, http.StatusOK, request(http.MethodDelete, "/api/v1/wells/1", admin).Code)
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, "/api/v1/wells", admin).Code)
+}
+
+func TestSecureSessionCookies(t *testing.T) {
+	// TLS завершается на прокси: запрос к серверу идет без TLS
+	rec := formLogin(newAuthRouterWith(t, true), "/wells")
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	for _, name := range []string{"access_token", "refresh_token"} {
+		cookie := cookieNamed(rec.Result().Cookies(), name)
+		require.NotNil(t, cookie, name)
+		assert.True(t, cookie.Secure, name)
+	}
+}
@@ -0,0 +1,133 @@
+// internal/handler/base.go
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gas_wells/internal/pkg/auth"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/pkg/logger"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// baseHandler - общие для всех обработчиков методы вывода HTML и JSON
+type baseHandler struct {
+	logger    logger.Logger
+	templates Templates
+}
+
+func newBaseHandler(templates Templates, log logger.Logger) baseHandler {
+	return baseHandler{
+		logger:    log.With("layer", "handler"),
+		templates: templates,
+	}
+}
+
+// renderTemplate выводит страницу; в данные шаблона добавляется текущий
+// пользователь CurrentUser, по правам которого скрываются недоступные кнопки
+func (h *baseHandler) renderTemplate(w http.ResponseWriter, r *http.Request, tmpl string, data map[string]interface{}) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["CurrentUser"] = auth.UserFromContext(r.Context())
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := h.templates.Render(w, tmpl, data)
+	if err != nil {
+		h.logger.Error("failed to render template", "template", tmpl, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func (h *baseHandler) renderError(w http.ResponseWriter, r *http.Request, message string, statusCode int) {
+	data := map[string]interface{}{
+		"Error":      message,
+		"StatusCode": statusCode,
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(statusCode)
+	h.renderTemplate(w, r, "error.html", data)
+}
+
+func (h *baseHandler) respondJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// apiError - тело ответа с ошибкой; Fields - ошибки проверки по полям
+type apiError struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+func (h *baseHandler) respondError(w http.ResponseWriter, message string, statusCode int) {
+	h.respondJSON(w, apiError{Error: message}, statusCode)
+}
+
+// respondFailure - ошибка в JSON для JSON-клиента, иначе страница ошибки
+func (h *baseHandler) respondFailure(w http.ResponseWriter, r *http.Request, message string, statusCode int) {
+	if wantsJSON(r) {
+		h.respondError(w, message, statusCode)
+	} else {
+		h.renderError(w, r, message, statusCode)
+	}
+}
+
+// respondValidationErrors - ошибки проверки входных данных с привязкой к полям
+func (h *baseHandler) respondValidationErrors(w http.ResponseWriter, fields map[string]string) {
+	h.respondJSON(w, apiError{Error: "validation failed", Fields: fields}, http.StatusUnprocessableEntity)
+}
+
+// wantsJSON - клиент передает или ожидает JSON вместо HTML-формы и страницы
+func wantsJSON(r *http.Request) bool {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return true
+	}
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "application/json") && !strings.Contains(accept, "text/html")
+}
+
+// urlParamInt - целочисленный параметр маршрута chi
+func urlParamInt(r *http.Request, name string) (int, error) {
+	return strconv.Atoi(chi.URLParam(r, name))
+}
+
+// parsePoints разбирает число точек расчетной кривой; пустое значение - 0
+// (число точек по умолчанию)
+func parsePoints(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	points, err := strconv.Atoi(s)
+	if err != nil || points < 0 || points > calculations.MaxCurvePoints {
+		return 0, fmt.Errorf("points must be an integer from 0 to %d", calculations.MaxCurvePoints)
+	}
+	return points, nil
+}
+
+// decodeOneOrMany разбирает тело JSON-запроса: один объект или массив объектов
+func decodeOneOrMany[T any](body io.Reader) ([]*T, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	var items []*T
+	if bytes.HasPrefix(data, []byte("[")) {
+		err = json.Unmarshal(data, &items)
+	} else {
+		item := new(T)
+		err = json.Unmarshal(data, item)
+		items = append(items, item)
+	}
+	return items, err
+}
@@ -0,0 +1,194 @@
+// internal/handler/field_handler.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"net/http"
+)
+
+type FieldHandler struct {
+	baseHandler
+	service *service.FieldService
+}
+
+func NewFieldHandler(service *service.FieldService, templates Templates, log logger.Logger) *FieldHandler {
+	return &FieldHandler{
+		baseHandler: newBaseHandler(templates, log),
+		service:     service,
+	}
+}
+
+// ListFields - месторождения со сводными показателями
+func (h *FieldHandler) ListFields(w http.ResponseWriter, r *http.Request) {
+	fields, err := h.service.Overview(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list fields", "error", err)
+		h.renderError(w, r, "Failed to load fields", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderTemplate(w, r, "fields/list.html", map[string]interface{}{
+		"Title":  "Месторождения",
+		"Fields": fields,
+	})
+}
+
+// CreateField - добавление месторождения (форма)
+func (h *FieldHandler) CreateField(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	field, err := h.service.CreateField(r.Context(), &entity.Field{
+		Name:        r.FormValue("name"),
+		Description: r.FormValue("description"),
+	})
+	if errors.Is(err, service.ErrFieldExists) {
+		h.renderError(w, r, "Field with this name already exists", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidField) {
+		h.renderError(w, r, "Failed to create field: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to create field", "error", err)
+		h.renderError(w, r, "Failed to create field", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/fields/%d", field.ID), http.StatusSeeOther)
+}
+
+// GetField - месторождение со сводкой по кустам
+func (h *FieldHandler) GetField(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.renderError(w, r, "Invalid field ID", http.StatusBadRequest)
+		return
+	}
+
+	overview, err := h.service.FieldOverview(r.Context(), id)
+	if errors.Is(err, service.ErrFieldNotFound) {
+		h.renderError(w, r, "Field not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get field", "id", id, "error", err)
+		h.renderError(w, r, "Failed to load field", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderTemplate(w, r, "fields/view.html", map[string]interface{}{
+		"Title":    "Месторождение " + overview.Field.Name,
+		"Overview": overview,
+	})
+}
+
+// DeleteField - удаление месторождения
+func (h *FieldHandler) DeleteField(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid field ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.DeleteField(r.Context(), id)
+	if errors.Is(err, service.ErrFieldNotFound) {
+		h.respondError(w, "Field not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to delete field", "id", id, "error", err)
+		h.respondError(w, "Failed to delete field", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreatePad - добавление куста на месторождение (форма)
+func (h *FieldHandler) CreatePad(w http.ResponseWriter, r *http.Request) {
+	fieldID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.renderError(w, r, "Invalid field ID", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.service.CreatePad(r.Context(), &entity.Pad{FieldID: fieldID, Name: r.FormValue("name")})
+	if errors.Is(err, service.ErrFieldNotFound) {
+		h.renderError(w, r, "Field not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrPadExists) {
+		h.renderError(w, r, "Pad with this name already exists", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidField) {
+		h.renderError(w, r, "Failed to create pad: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to create pad", "field_id", fieldID, "error", err)
+		h.renderError(w, r, "Failed to create pad", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/fields/%d", fieldID), http.StatusSeeOther)
+}
+
+// GetPad - куст со сводкой и списком скважин
+func (h *FieldHandler) GetPad(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.renderError(w, r, "Invalid pad ID", http.StatusBadRequest)
+		return
+	}
+
+	overview, err := h.service.PadOverview(r.Context(), id)
+	if errors.Is(err, service.ErrPadNotFound) {
+		h.renderError(w, r, "Pad not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get pad", "id", id, "error", err)
+		h.renderError(w, r, "Failed to load pad", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderTemplate(w, r, "pads/view.html", map[string]interface{}{
+		"Title":    "Куст " + overview.Pad.Name,
+		"Overview": overview,
+	})
+}
+
+// DeletePad - удаление куста
+func (h *FieldHandler) DeletePad(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid pad ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.DeletePad(r.Context(), id)
+	if errors.Is(err, service.ErrPadNotFound) {
+		h.respondError(w, "Pad not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to delete pad", "id", id, "error", err)
+		h.respondError(w, "Failed to delete pad", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
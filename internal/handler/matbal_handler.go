@@ -0,0 +1,150 @@
+// internal/handler/matbal_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"net/http"
+	"strings"
+)
+
+type MaterialBalanceHandler struct {
+	baseHandler
+	service *service.MaterialBalanceService
+}
+
+func NewMaterialBalanceHandler(service *service.MaterialBalanceService, templates Templates, log logger.Logger) *MaterialBalanceHandler {
+	return &MaterialBalanceHandler{
+		baseHandler: newBaseHandler(templates, log),
+		service:     service,
+	}
+}
+
+// ListSurveys - замеры пластового давления скважины (JSON)
+func (h *MaterialBalanceHandler) ListSurveys(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	surveys, err := h.service.ListSurveys(r.Context(), wellID)
+	if err != nil {
+		h.logger.Error("failed to list pressure surveys", "well_id", wellID, "error", err)
+		h.respondError(w, "Failed to load pressure surveys", http.StatusInternalServerError)
+		return
+	}
+	if surveys == nil {
+		surveys = []*entity.PressureSurvey{}
+	}
+
+	h.respondJSON(w, surveys, http.StatusOK)
+}
+
+// AddSurvey - добавление замера пластового давления: JSON или форма
+// (survey_date, ppl в Па, gp в м³) с переходом на страницу скважины
+func (h *MaterialBalanceHandler) AddSurvey(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	isJSON := strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+	fail := func(msg string, status int) {
+		if isJSON {
+			h.respondError(w, msg, status)
+		} else {
+			h.renderError(w, r, msg, status)
+		}
+	}
+
+	survey := &entity.PressureSurvey{}
+	if isJSON {
+		err = json.NewDecoder(r.Body).Decode(survey)
+	} else if err = r.ParseForm(); err == nil {
+		survey.SurveyDate, err = parseDate(r.FormValue("survey_date"))
+		survey.Ppl = parseFloat(r.FormValue("ppl"))
+		survey.Gp = parseFloat(r.FormValue("gp"))
+	}
+	if err != nil {
+		fail("Invalid pressure survey: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	survey.WellID = wellID
+
+	created, err := h.service.AddSurvey(r.Context(), survey)
+	if errors.Is(err, service.ErrWellNotFound) {
+		fail("Well not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidSurvey) {
+		fail(err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to add pressure survey", "well_id", wellID, "error", err)
+		fail("Failed to save pressure survey", http.StatusInternalServerError)
+		return
+	}
+
+	if isJSON {
+		h.respondJSON(w, created, http.StatusCreated)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/wells/%d", wellID), http.StatusSeeOther)
+}
+
+// DeleteSurvey - удаление замера пластового давления
+func (h *MaterialBalanceHandler) DeleteSurvey(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+	id, err := urlParamInt(r, "surveyID")
+	if err != nil {
+		h.respondError(w, "Invalid survey ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteSurvey(r.Context(), wellID, id); err != nil {
+		h.logger.Error("failed to delete pressure survey", "id", id, "error", err)
+		h.respondError(w, "Failed to delete pressure survey", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MaterialBalance - запасы газа по методу падения давления (JSON).
+// Параметр pab - давление забрасывания, Па.
+func (h *MaterialBalanceHandler) MaterialBalance(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.service.MaterialBalance(r.Context(), wellID, parseFloat(r.URL.Query().Get("pab")))
+	if errors.Is(err, service.ErrWellNotFound) {
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidSurvey) || errors.Is(err, service.ErrInvalidWell) ||
+		errors.Is(err, service.ErrCalculation) {
+		h.respondError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to run material balance", "well_id", wellID, "error", err)
+		h.respondError(w, "Failed to run material balance", http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, res, http.StatusOK)
+}
@@ -0,0 +1,167 @@
+// internal/handler/measurement_handler.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"net/http"
+	"time"
+)
+
+type MeasurementHandler struct {
+	baseHandler
+	service *service.MeasurementService
+}
+
+func NewMeasurementHandler(service *service.MeasurementService, templates Templates, log logger.Logger) *MeasurementHandler {
+	return &MeasurementHandler{
+		baseHandler: newBaseHandler(templates, log),
+		service:     service,
+	}
+}
+
+// ListMeasurements - временной ряд замеров скважины (JSON).
+// Параметры: from, to (RFC 3339 или ГГГГ-ММ-ДД), resolution (hour, day; пусто - исходные замеры).
+func (h *MeasurementHandler) ListMeasurements(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := parseTime(query.Get("from"))
+	if err != nil {
+		h.respondError(w, "Invalid from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := parseTime(query.Get("to"))
+	if err != nil {
+		h.respondError(w, "Invalid to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	measurements, err := h.service.Measurements(r.Context(), wellID, service.MeasurementQuery{
+		From:       from,
+		To:         to,
+		Resolution: entity.MeasurementResolution(query.Get("resolution")),
+	})
+	if errors.Is(err, service.ErrWellNotFound) {
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidMeasurementQuery) {
+		h.respondError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to list measurements", "well_id", wellID, "error", err)
+		h.respondError(w, "Failed to load measurements", http.StatusInternalServerError)
+		return
+	}
+	if measurements == nil {
+		measurements = []*entity.Measurement{}
+	}
+
+	h.respondJSON(w, measurements, http.StatusOK)
+}
+
+// AddMeasurements - добавление замера или массива замеров (JSON)
+func (h *MeasurementHandler) AddMeasurements(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	measurements, err := decodeOneOrMany[entity.Measurement](r.Body)
+	if err != nil {
+		h.respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.service.AddMeasurements(r.Context(), wellID, measurements)
+	if errors.Is(err, service.ErrWellNotFound) {
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	}
+	// Новые текущие значения могут сделать расчет по скважине невозможным
+	if errors.Is(err, service.ErrInvalidMeasurement) || errors.Is(err, service.ErrInvalidWell) ||
+		errors.Is(err, service.ErrCalculation) {
+		h.respondError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to add measurements", "well_id", wellID, "error", err)
+		h.respondError(w, "Failed to save measurements", http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, measurements, http.StatusCreated)
+}
+
+// CurrentMeasurement - последние известные значения параметров скважины (JSON)
+func (h *MeasurementHandler) CurrentMeasurement(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.service.Current(r.Context(), wellID)
+	if err != nil {
+		h.logger.Error("failed to get current measurement", "well_id", wellID, "error", err)
+		h.respondError(w, "Failed to load measurements", http.StatusInternalServerError)
+		return
+	}
+	if current == nil {
+		h.respondError(w, "No measurements", http.StatusNotFound)
+		return
+	}
+
+	h.respondJSON(w, current, http.StatusOK)
+}
+
+// DeleteMeasurement - удаление замера
+func (h *MeasurementHandler) DeleteMeasurement(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+	id, err := urlParamInt(r, "measurementID")
+	if err != nil {
+		h.respondError(w, "Invalid measurement ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.DeleteMeasurement(r.Context(), wellID, id)
+	if errors.Is(err, service.ErrWellNotFound) {
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to delete measurement", "id", id, "error", err)
+		h.respondError(w, "Failed to delete measurement", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseTime разбирает время в формате RFC 3339 или дату ГГГГ-ММ-ДД;
+// пустая строка - нулевое время
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported time format %q", s)
+}
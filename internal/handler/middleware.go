@@ -1,15 +1,156 @@
 package handler
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/auth"
+	"gas_wells/internal/service"
+	"mime"
+	"net/http"
+	"strings"
+)
+
 // internal/handler/middleware.go
-/*func AuthMiddleware(next http.Handler) http.Handler {
+
+// JSONOnly - согласование формата для JSON API: клиент должен принимать
+// application/json (406), а тело запроса должно быть в JSON (415)
+func JSONOnly(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token := r.Header.Get("Authorization")
-		claims, err := service.ValidateToken(token)
-		if err != nil {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		if !acceptsJSON(r.Header.Get("Accept")) {
+			writeAPIError(w, "only application/json responses are supported", http.StatusNotAcceptable)
+			return
+		}
+		if r.ContentLength != 0 && r.Method != http.MethodGet && r.Method != http.MethodDelete {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || mediaType != "application/json" {
+				writeAPIError(w, "request body must be application/json", http.StatusUnsupportedMediaType)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acceptsJSON - заголовок Accept допускает ответ в JSON
+func acceptsJSON(accept string) bool {
+	if strings.TrimSpace(accept) == "" {
+		return true
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+		switch mediaType {
+		case "application/json", "application/*", "*/*":
+			return true
+		}
+	}
+	return false
+}
+
+func writeAPIError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(apiError{Error: message})
+}
+
+// RequireAuth - проверка входа пользователя. Access-токен берется из заголовка
+// Authorization: Bearer или из cookie; истекший токен в cookie обновляется
+// по refresh-токену без повторного входа. Пользователь помещается в контекст
+// запроса. Без входа API отвечает 401, а страницы перенаправляют на форму входа.
+func (h *AuthHandler) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if header := r.Header.Get("Authorization"); header != "" {
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			user, err := h.service.Authenticate(ctx, strings.TrimSpace(token))
+			if !ok || err != nil {
+				if err != nil && !errors.Is(err, service.ErrInvalidToken) {
+					h.logger.Error("failed to authenticate", "error", err)
+				}
+				unauthorized(w, "Invalid or expired access token")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithUser(ctx, user)))
 			return
 		}
-		ctx := context.WithValue(r.Context(), "user_id", claims.UserID)
-		next.ServeHTTP(w, r.WithContext(ctx))
+
+		if cookie, err := r.Cookie(accessCookie); err == nil {
+			if user, err := h.service.Authenticate(ctx, cookie.Value); err == nil {
+				next.ServeHTTP(w, r.WithContext(auth.WithUser(ctx, user)))
+				return
+			}
+		}
+		if cookie, err := r.Cookie(refreshCookie); err == nil {
+			session, err := h.service.Refresh(ctx, cookie.Value)
+			if err == nil {
+				h.setSessionCookies(w, r, session.Tokens)
+				next.ServeHTTP(w, r.WithContext(auth.WithUser(ctx, session.User)))
+				return
+			}
+			if !errors.Is(err, service.ErrInvalidToken) {
+				h.logger.Error("failed to refresh session", "error", err)
+			}
+			h.clearSessionCookies(w, r)
+		}
+
+		if wantsJSON(r) || strings.HasPrefix(r.URL.Path, "/api/") {
+			unauthorized(w, "Authentication required")
+			return
+		}
+		http.Redirect(w, r, loginURL(r), http.StatusSeeOther)
 	})
-}*/
+}
+
+// Require - проверка права p у пользователя, выполнившего вход (RequireAuth).
+// При отсутствии права API отвечает 403 в JSON, страницы - страницей ошибки.
+func (h *AuthHandler) Require(p entity.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := auth.UserFromContext(r.Context())
+			if user.Can(p) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if user == nil {
+				unauthorized(w, "Authentication required")
+				return
+			}
+
+			h.logger.Warn("permission denied", "user_id", user.ID, "role", user.Role, "permission", p,
+				"method", r.Method, "path", r.URL.Path)
+			message := fmt.Sprintf("Role %q does not permit this operation", user.Role)
+			if wantsJSON(r) || strings.HasPrefix(r.URL.Path, "/api/") {
+				writeAPIError(w, message, http.StatusForbidden)
+				return
+			}
+			h.renderError(w, r, message, http.StatusForbidden)
+		})
+	}
+}
+
+// RequireByMethod - права JSON API по методу запроса: чтение для GET,
+// удаление для DELETE, изменение для остальных методов
+func (h *AuthHandler) RequireByMethod(next http.Handler) http.Handler {
+	read, edit, remove := h.Require(entity.PermRead)(next), h.Require(entity.PermEdit)(next), h.Require(entity.PermDelete)(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			read.ServeHTTP(w, r)
+		case http.MethodDelete:
+			remove.ServeHTTP(w, r)
+		default:
+			edit.ServeHTTP(w, r)
+		}
+	})
+}
+
+// unauthorized - ответ 401 с указанием схемы аутентификации
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="gas_wells"`)
+	writeAPIError(w, message, http.StatusUnauthorized)
+}
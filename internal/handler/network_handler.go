@@ -0,0 +1,285 @@
+// internal/handler/network_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type NetworkHandler struct {
+	baseHandler
+	service *service.NetworkService
+	fields  *service.FieldService
+}
+
+func NewNetworkHandler(service *service.NetworkService, fields *service.FieldService, templates Templates, log logger.Logger) *NetworkHandler {
+	return &NetworkHandler{
+		baseHandler: newBaseHandler(templates, log),
+		service:     service,
+		fields:      fields,
+	}
+}
+
+// GetNetwork - страница сети сбора куста
+func (h *NetworkHandler) GetNetwork(w http.ResponseWriter, r *http.Request) {
+	padID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.renderError(w, r, "Invalid pad ID", http.StatusBadRequest)
+		return
+	}
+
+	overview, err := h.fields.PadOverview(r.Context(), padID)
+	if errors.Is(err, service.ErrPadNotFound) {
+		h.renderError(w, r, "Pad not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get pad", "id", padID, "error", err)
+		h.renderError(w, r, "Failed to load pad", http.StatusInternalServerError)
+		return
+	}
+	network, err := h.service.Network(r.Context(), padID)
+	if err != nil {
+		h.logger.Error("failed to load pad network", "pad_id", padID, "error", err)
+		h.renderError(w, r, "Failed to load pad network", http.StatusInternalServerError)
+		return
+	}
+
+	nodeNames := make(map[int]string, len(network.Nodes))
+	for _, n := range network.Nodes {
+		nodeNames[n.ID] = n.Name
+	}
+	h.renderTemplate(w, r, "pads/network.html", map[string]interface{}{
+		"Title":     "Сеть сбора куста " + overview.Pad.Name,
+		"Overview":  overview,
+		"Network":   network,
+		"NodeNames": nodeNames,
+	})
+}
+
+// AddNode - добавление узла сети: JSON или форма (name, kind, well_id)
+// с переходом на страницу сети
+func (h *NetworkHandler) AddNode(w http.ResponseWriter, r *http.Request) {
+	padID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid pad ID", http.StatusBadRequest)
+		return
+	}
+
+	isJSON := strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+	fail := func(msg string, status int) {
+		if isJSON {
+			h.respondError(w, msg, status)
+		} else {
+			h.renderError(w, r, msg, status)
+		}
+	}
+
+	node := &entity.NetworkNode{}
+	if isJSON {
+		err = json.NewDecoder(r.Body).Decode(node)
+	} else if err = r.ParseForm(); err == nil {
+		node.Name = r.FormValue("name")
+		node.Kind = entity.NetworkNodeKind(r.FormValue("kind"))
+		node.WellID = parseOptionalID(r.FormValue("well_id"))
+	}
+	if err != nil {
+		fail("Invalid network node: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	node.PadID = padID
+
+	created, err := h.service.AddNode(r.Context(), node)
+	switch {
+	case errors.Is(err, service.ErrPadNotFound):
+		fail("Pad not found", http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrWellNotFound):
+		fail("Well not found", http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrInvalidNetwork):
+		fail(err.Error(), http.StatusUnprocessableEntity)
+		return
+	case err != nil:
+		h.logger.Error("failed to add network node", "pad_id", padID, "error", err)
+		fail("Failed to add network node", http.StatusInternalServerError)
+		return
+	}
+
+	if isJSON {
+		h.respondJSON(w, created, http.StatusCreated)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/pads/%d/network", padID), http.StatusSeeOther)
+}
+
+// DeleteNode - удаление узла сети вместе с примыкающими участками
+func (h *NetworkHandler) DeleteNode(w http.ResponseWriter, r *http.Request) {
+	padID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid pad ID", http.StatusBadRequest)
+		return
+	}
+	id, err := urlParamInt(r, "nodeID")
+	if err != nil {
+		h.respondError(w, "Invalid node ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteNode(r.Context(), padID, id); err != nil {
+		h.logger.Error("failed to delete network node", "id", id, "error", err)
+		h.respondError(w, "Failed to delete network node", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddSegment - добавление участка трубопровода: JSON или форма
+// (from_node_id, to_node_id, length, diameter, roughness, elevation в м)
+func (h *NetworkHandler) AddSegment(w http.ResponseWriter, r *http.Request) {
+	padID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid pad ID", http.StatusBadRequest)
+		return
+	}
+
+	isJSON := strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+	fail := func(msg string, status int) {
+		if isJSON {
+			h.respondError(w, msg, status)
+		} else {
+			h.renderError(w, r, msg, status)
+		}
+	}
+
+	seg := &entity.PipeSegment{}
+	if isJSON {
+		err = json.NewDecoder(r.Body).Decode(seg)
+	} else if err = r.ParseForm(); err == nil {
+		seg.FromNodeID, _ = strconv.Atoi(r.FormValue("from_node_id"))
+		seg.ToNodeID, _ = strconv.Atoi(r.FormValue("to_node_id"))
+		seg.Length = parseFloat(r.FormValue("length"))
+		seg.Diameter = parseFloat(r.FormValue("diameter"))
+		seg.Roughness = parseFloat(r.FormValue("roughness"))
+		seg.Elevation = parseFloat(r.FormValue("elevation"))
+	}
+	if err != nil {
+		fail("Invalid pipe segment: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	seg.PadID = padID
+
+	created, err := h.service.AddSegment(r.Context(), seg)
+	if errors.Is(err, service.ErrPadNotFound) {
+		fail("Pad not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidNetwork) {
+		fail(err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to add pipe segment", "pad_id", padID, "error", err)
+		fail("Failed to add pipe segment", http.StatusInternalServerError)
+		return
+	}
+
+	if isJSON {
+		h.respondJSON(w, created, http.StatusCreated)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/pads/%d/network", padID), http.StatusSeeOther)
+}
+
+// DeleteSegment - удаление участка трубопровода
+func (h *NetworkHandler) DeleteSegment(w http.ResponseWriter, r *http.Request) {
+	padID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid pad ID", http.StatusBadRequest)
+		return
+	}
+	id, err := urlParamInt(r, "segmentID")
+	if err != nil {
+		h.respondError(w, "Invalid segment ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteSegment(r.Context(), padID, id); err != nil {
+		h.logger.Error("failed to delete pipe segment", "id", id, "error", err)
+		h.respondError(w, "Failed to delete pipe segment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SolveNetwork - установившийся режим сети куста (JSON).
+// Параметры: header - давление в коллекторе, Па; rates - дебиты скважин,
+// ограниченные штуцером, в виде "ID скважины:дебит", например "12:150000,14:80000".
+func (h *NetworkHandler) SolveNetwork(w http.ResponseWriter, r *http.Request) {
+	padID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid pad ID", http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	rates, err := parseRates(query.Get("rates"))
+	if err != nil {
+		h.respondError(w, "Invalid rates: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.service.Solve(r.Context(), padID, service.NetworkQuery{
+		HeaderPressure: parseFloat(query.Get("header")),
+		Rates:          rates,
+	})
+	switch {
+	case errors.Is(err, service.ErrPadNotFound):
+		h.respondError(w, "Pad not found", http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrWellNotFound):
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrInvalidNetwork), errors.Is(err, service.ErrCalculation):
+		h.respondError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	case err != nil:
+		h.logger.Error("failed to solve pad network", "pad_id", padID, "error", err)
+		h.respondError(w, "Failed to solve pad network", http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, res, http.StatusOK)
+}
+
+// parseRates разбирает дебиты скважин "ID:дебит" через запятую
+func parseRates(s string) (map[int]float64, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	rates := make(map[int]float64)
+	for _, part := range strings.Split(s, ",") {
+		id, rate, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("expected well:rate, got %q", part)
+		}
+		wellID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid well ID %q", id)
+		}
+		q, err := strconv.ParseFloat(rate, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate %q", rate)
+		}
+		rates[wellID] = q
+	}
+	return rates, nil
+}
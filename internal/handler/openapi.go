@@ -0,0 +1,341 @@
+// internal/handler/openapi.go
+package handler
+
+import (
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Документ OpenAPI 3.0 в объеме, необходимом для описания API сервера.
+// Схемы ответов строятся по Go-типам через reflect, поэтому не расходятся
+// с тем, что фактически кодирует respondJSON.
+
+type openAPIDocument struct {
+	OpenAPI    string                   `json:"openapi"`
+	Info       openAPIInfo              `json:"info"`
+	Tags       []openAPITag             `json:"tags"`
+	Paths      map[string]openAPIPath   `json:"paths"`
+	Components openAPIComponents        `json:"components"`
+	Security   []openAPISecurity        `json:"security,omitempty"`
+	operations []*openAPIOperationEntry // Операции в порядке описания, для страницы документации
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+type openAPITag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// openAPIPath - операции пути; ключ - HTTP-метод в нижнем регистре
+type openAPIPath map[string]*openAPIOperation
+
+type openAPIOperation struct {
+	Tags        []string                   `json:"tags"`
+	Summary     string                     `json:"summary"`
+	Description string                     `json:"description,omitempty"`
+	OperationID string                     `json:"operationId"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	Security    *[]openAPISecurity         `json:"security,omitempty"` // Пустой список - операция без входа
+	Permission  entity.Permission          `json:"x-permission,omitempty"`
+}
+
+// openAPISecurity - требование аутентификации: схема -> области доступа
+type openAPISecurity map[string][]string
+
+type openAPISecurityScheme struct {
+	Type         string `json:"type"` // http, apiKey
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"` // path, query
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                    `json:"required"`
+	Content  map[string]openAPIMedia `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                   `json:"description"`
+	Headers     map[string]openAPIHeader `json:"headers,omitempty"`
+	Content     map[string]openAPIMedia  `json:"content,omitempty"`
+}
+
+type openAPIHeader struct {
+	Description string         `json:"description"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIMedia struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Unit                 string                    `json:"x-unit,omitempty"` // Единица измерения (СИ, как в entity)
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	OneOf                []*openAPISchema          `json:"oneOf,omitempty"`
+}
+
+// openAPIOperationEntry - операция с методом и путем для страницы документации
+type openAPIOperationEntry struct {
+	Method string
+	Path   string
+	Op     *openAPIOperation
+}
+
+// Codes возвращает коды ответов операции по возрастанию
+func (e *openAPIOperationEntry) Codes() []string {
+	codes := make([]string, 0, len(e.Op.Responses))
+	for code := range e.Op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// fieldDoc - описание поля схемы и его единица измерения
+type fieldDoc struct {
+	Description string
+	Unit        string
+}
+
+// wellFieldDocs - описание полей entity.Well. Давления в Па, температуры
+// в К, дебиты в м³/сут при стандартных условиях, длины в м.
+var wellFieldDocs = map[string]fieldDoc{
+	"id":             {"Идентификатор скважины", ""},
+	"name":           {"Название (номер) скважины", ""},
+	"location":       {"Местоположение", ""},
+	"gamma":          {"Относительная плотность газа по воздуху", ""},
+	"temp":           {"Температура пласта", "К"},
+	"tempust":        {"Температура на устье", "К"},
+	"depth":          {"Глубина скважины", "м"},
+	"pbuf":           {"Буферное давление", "Па"},
+	"ptb":            {"Затрубное давление", "Па"},
+	"ppl":            {"Пластовое давление", "Па"},
+	"pz":             {"Забойное давление; рассчитывается по Pбуф, если pz_manual = false", "Па"},
+	"q":              {"Дебит газа при стандартных условиях", "м³/сут"},
+	"roughness":      {"Шероховатость НКТ", "м"},
+	"diameter":       {"Внутренний диаметр НКТ", "м"},
+	"a":              {"Коэффициент фильтрационного сопротивления A", "МПа²·сут/тыс.м³"},
+	"b":              {"Коэффициент фильтрационного сопротивления B", "(МПа·сут/тыс.м³)²"},
+	"mu":             {"Вязкость газа; рассчитывается, если mu_manual = false", "мПа·с"},
+	"wgf":            {"Водогазовый фактор", "см³/м³"},
+	"rog":            {"Плотность воды", "кг/м³"},
+	"hw":             {"Высота столба газожидкостной смеси", "м"},
+	"qmin":           {"Критический дебит выноса жидкости (расчетный)", "м³/сут"},
+	"pmax":           {"Максимально допустимое давление; задается пользователем, не рассчитывается", "Па"},
+	"status":         {"Состояние жизненного цикла: " + strings.Join(entity.WellStatuses, ", ") + "; меняется только переходом POST /wells/{id}/status", ""},
+	"status_since":   {"Дата перехода в текущее состояние (из истории состояний)", ""},
+	"created":        {"Время создания записи", ""},
+	"updated":        {"Время последнего изменения", ""},
+	"qmin_model":     {"Модель расчета Qmin: turner, coleman, li (пусто - turner)", ""},
+	"liquid_loading": {"Риск самозадавливания: Q < Qmin (расчетный)", ""},
+	"mu_manual":      {"Вязкость задана вручную и не пересчитывается", ""},
+	"pz_manual":      {"Забойное давление замерено и не пересчитывается; расхождение с расчетом дает предупреждение", ""},
+	"pad_id":         {"ID куста; null - скважина не привязана к кусту", ""},
+	"choke":          {"Диаметр штуцера; 0 - штуцер не установлен", "м"},
+	"pline":          {"Давление в шлейфе после штуцера", "Па"},
+	"warnings":       {"Предупреждения расчета, не хранятся", ""},
+	"hydrate_risk":   {"Риск гидратообразования: none, warning, high (расчетный)", ""},
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// specBuilder собирает документ и компоненты схем
+type specBuilder struct {
+	doc   *openAPIDocument
+	names map[reflect.Type]string
+	docs  map[reflect.Type]map[string]fieldDoc
+}
+
+// ref возвращает ссылку на схему Go-значения v; схема добавляется в компоненты
+func (b *specBuilder) ref(v interface{}) *openAPISchema {
+	return b.schemaFor(reflect.TypeOf(v))
+}
+
+// define регистрирует схему типа v под именем name
+func (b *specBuilder) define(name string, v interface{}) *openAPISchema {
+	b.names[reflect.TypeOf(v)] = name
+	return b.ref(v)
+}
+
+// arrayOf - схема массива значений v
+func (b *specBuilder) arrayOf(v interface{}) *openAPISchema {
+	return &openAPISchema{Type: "array", Items: b.ref(v)}
+}
+
+func (b *specBuilder) schemaFor(t reflect.Type) *openAPISchema {
+	if t == timeType {
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := b.schemaFor(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &openAPISchema{Type: "array", Items: b.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: b.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name, ok := b.names[t]
+		if !ok {
+			name = t.Name()
+			if pkg := t.PkgPath(); pkg != "" {
+				name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+			}
+			b.names[t] = name
+		}
+		if _, ok := b.doc.Components.Schemas[name]; !ok {
+			// Заглушка до построения схемы - защита от рекурсивных типов
+			b.doc.Components.Schemas[name] = &openAPISchema{}
+			*b.doc.Components.Schemas[name] = *b.structSchema(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + name}
+	default:
+		return &openAPISchema{}
+	}
+}
+
+// structSchema строит схему объекта по полям структуры с учетом тегов json
+func (b *specBuilder) structSchema(t reflect.Type) *openAPISchema {
+	s := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+	docs := b.docs[t]
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded := b.structSchema(f.Type)
+			for k, v := range embedded.Properties {
+				s.Properties[k] = v
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := b.schemaFor(f.Type)
+		if d, ok := docs[name]; ok {
+			if prop.Ref != "" {
+				prop = &openAPISchema{OneOf: []*openAPISchema{prop}}
+			}
+			prop.Description, prop.Unit = d.Description, d.Unit
+			if prop.Unit != "" {
+				prop.Description += ", " + prop.Unit
+			}
+		}
+		s.Properties[name] = prop
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPISpec *openAPIDocument
+)
+
+// apiSpec возвращает документ OpenAPI; строится один раз при первом обращении
+func apiSpec() *openAPIDocument {
+	openAPIOnce.Do(func() {
+		openAPISpec = buildOpenAPI()
+	})
+	return openAPISpec
+}
+
+// DocsHandler отдает описание API: документ OpenAPI и страницу документации
+type DocsHandler struct {
+	baseHandler
+}
+
+func NewDocsHandler(templates Templates, log logger.Logger) *DocsHandler {
+	return &DocsHandler{baseHandler: newBaseHandler(templates, log)}
+}
+
+// OpenAPI - документ OpenAPI 3 (JSON)
+func (h *DocsHandler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, apiSpec(), http.StatusOK)
+}
+
+// Docs - страница документации API, построенная по тому же документу
+func (h *DocsHandler) Docs(w http.ResponseWriter, r *http.Request) {
+	spec := apiSpec()
+	byTag := make(map[string][]*openAPIOperationEntry)
+	for _, op := range spec.operations {
+		byTag[op.Op.Tags[0]] = append(byTag[op.Op.Tags[0]], op)
+	}
+	type tagSection struct {
+		Name        string
+		Description string
+		Operations  []*openAPIOperationEntry
+	}
+	sections := make([]tagSection, 0, len(spec.Tags))
+	for _, tag := range spec.Tags {
+		sections = append(sections, tagSection{Name: tag.Name, Description: tag.Description, Operations: byTag[tag.Name]})
+	}
+
+	h.renderTemplate(w, r, "api/docs.html", map[string]interface{}{
+		"Title":    "Документация API",
+		"Info":     spec.Info,
+		"Sections": sections,
+	})
+}
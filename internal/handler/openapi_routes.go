@@ -0,0 +1,1028 @@
+// internal/handler/openapi_routes.go
+package handler
+
+import (
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/service"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// errorFormat - формат ответа с ошибкой
+type errorFormat int
+
+const (
+	errorsJSON  errorFormat = iota // JSON {"error": ...}
+	errorsHTML                     // HTML-страница error.html
+	errorsMixed                    // JSON для JSON-запросов, иначе HTML
+)
+
+// apiRoute - описание маршрута сервера для документа OpenAPI.
+// Маршруты перечислены в том же порядке, что и в server.SetupRoutes.
+type apiRoute struct {
+	Method       string
+	Path         string // Шаблон пути chi, например /wells/{id}
+	Tag          string
+	Summary      string
+	Description  string
+	Query        []openAPIParameter
+	Body         map[string]*openAPISchema // Тип содержимого -> схема тела
+	OptionalBody bool
+	Responses    map[int]openAPIResponse // Успешные ответы
+	Errors       []int
+	ErrorFormat  errorFormat
+	Public       bool              // Доступен без входа
+	Permission   entity.Permission // Требуемое право; пусто - просмотр, доступный всем ролям
+}
+
+const (
+	mimeJSON      = "application/json"
+	mimeHTML      = "text/html"
+	mimeForm      = "application/x-www-form-urlencoded"
+	mimeMultipart = "multipart/form-data"
+	mimeXLSX      = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	mimeCSV       = "text/csv"
+)
+
+var errorDescriptions = map[int]string{
+	http.StatusBadRequest:           "Некорректный ID, параметр или тело запроса",
+	http.StatusUnauthorized:         "Требуется вход: нет действующего access-токена",
+	http.StatusForbidden:            "Роль пользователя не дает права на операцию",
+	http.StatusNotFound:             "Объект не найден",
+	http.StatusNotAcceptable:        "Клиент не принимает application/json",
+	http.StatusConflict:             "Переход недопустим из текущего состояния",
+	http.StatusUnsupportedMediaType: "Тело запроса не в формате application/json",
+	http.StatusUnprocessableEntity:  "Ошибка проверки данных или расчета",
+	http.StatusInternalServerError:  "Внутренняя ошибка сервера",
+}
+
+// pathParamDocs - описание параметров пути; для id описание зависит от ресурса
+var pathParamDocs = map[string]string{
+	"testID":        "ID исследования скважины",
+	"measurementID": "ID замера",
+	"recordID":      "ID записи истории добычи",
+	"surveyID":      "ID замера пластового давления",
+	"nodeID":        "ID узла сети сбора",
+	"segmentID":     "ID участка трубопровода",
+	"revision":      "Номер версии скважины",
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+func queryParam(name, typ, description string) openAPIParameter {
+	return openAPIParameter{Name: name, In: "query", Description: description, Schema: &openAPISchema{Type: typ}}
+}
+
+func requiredQueryParam(name, typ, description string) openAPIParameter {
+	p := queryParam(name, typ, description)
+	p.Required = true
+	return p
+}
+
+func jsonResponse(description string, schema *openAPISchema) openAPIResponse {
+	return openAPIResponse{Description: description, Content: map[string]openAPIMedia{mimeJSON: {Schema: schema}}}
+}
+
+func htmlResponse(description string) openAPIResponse {
+	return openAPIResponse{Description: description, Content: map[string]openAPIMedia{mimeHTML: {Schema: &openAPISchema{Type: "string"}}}}
+}
+
+func fileResponse(mime, description string) openAPIResponse {
+	return openAPIResponse{
+		Description: description,
+		Headers: map[string]openAPIHeader{
+			"Content-Disposition": {Description: "Имя выгружаемого файла", Schema: &openAPISchema{Type: "string"}},
+		},
+		Content: map[string]openAPIMedia{mime: {Schema: &openAPISchema{Type: "string", Format: "binary"}}},
+	}
+}
+
+func redirectResponse(description string) openAPIResponse {
+	return openAPIResponse{
+		Description: description,
+		Headers: map[string]openAPIHeader{
+			"Location": {Description: "Адрес страницы перехода", Schema: &openAPISchema{Type: "string"}},
+		},
+	}
+}
+
+func noContent(description string) openAPIResponse {
+	return openAPIResponse{Description: description}
+}
+
+// formSchema - схема формы из строковых полей с описаниями
+func formSchema(required []string, fields ...string) *openAPISchema {
+	s := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}, Required: required}
+	for i := 0; i+1 < len(fields); i += 2 {
+		s.Properties[fields[i]] = &openAPISchema{Type: "string", Description: fields[i+1]}
+	}
+	return s
+}
+
+// wellFormSchema - схема HTML-формы скважины: поля совпадают с JSON-именами
+// entity.Well, кроме gammag (gamma в JSON)
+func (b *specBuilder) wellFormSchema() *openAPISchema {
+	b.ref(entity.Well{})
+	well := b.doc.Components.Schemas[b.names[reflect.TypeOf(entity.Well{})]]
+	s := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}, Required: []string{"name"}}
+	for _, name := range []string{
+		"name", "location", "gammag", "temp", "tempust", "depth", "pbuf", "ptb", "ppl", "pz", "q",
+		"roughness", "diameter", "a", "b", "mu", "wgf", "rog", "qmin", "pmax", "status",
+		"qmin_model", "mu_manual", "pz_manual", "pad_id", "choke", "pline",
+	} {
+		key := name
+		if name == "gammag" {
+			key = "gamma"
+		}
+		prop := *well.Properties[key]
+		prop.Nullable = false
+		s.Properties[name] = &prop
+	}
+	s.Properties["mu_manual"] = &openAPISchema{Type: "string", Description: "Флажок: непустое значение - вязкость задана вручную"}
+	s.Properties["pz_manual"] = &openAPISchema{Type: "string", Description: "Флажок: непустое значение - забойное давление по замеру"}
+	return s
+}
+
+func buildOpenAPI() *openAPIDocument {
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:   "Gas Wells",
+			Version: "1.0.0",
+			Description: "Учет газовых скважин и технологические расчеты. Величины в СИ: давление - Па, " +
+				"температура - К, длина - м, дебит - м³/сут при стандартных условиях.",
+		},
+		Tags: []openAPITag{
+			{Name: "api", Description: "JSON API скважин /api/v1"},
+			{Name: "wells", Description: "Скважины: страницы и технологические расчеты"},
+			{Name: "well-tests", Description: "Газодинамические исследования"},
+			{Name: "measurements", Description: "Замеры параметров работы"},
+			{Name: "production", Description: "История добычи и падение дебита"},
+			{Name: "surveys", Description: "Замеры пластового давления и материальный баланс"},
+			{Name: "fields", Description: "Месторождения и кусты"},
+			{Name: "network", Description: "Сеть сбора куста"},
+			{Name: "docs", Description: "Описание API"},
+			{Name: "auth", Description: "Вход, выход и обновление токенов"},
+			{Name: "users", Description: "Пользователи и роли (только для администраторов)"},
+			{Name: "audit", Description: "Журнал изменений скважин"},
+		},
+		Paths: make(map[string]openAPIPath),
+		Components: openAPIComponents{
+			Schemas: make(map[string]*openAPISchema),
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"bearer": {
+					Type: "http", Scheme: "bearer", BearerFormat: "JWT",
+					Description: "Access-токен из ответа POST /auth/login",
+				},
+				"cookie": {
+					Type: "apiKey", In: "cookie", Name: accessCookie,
+					Description: "Access-токен браузерной сессии; при истечении обновляется по cookie " + refreshCookie,
+				},
+			},
+		},
+		Security: []openAPISecurity{{"bearer": {}}, {"cookie": {}}},
+	}
+	b := &specBuilder{
+		doc:   doc,
+		names: make(map[reflect.Type]string),
+		docs: map[reflect.Type]map[string]fieldDoc{
+			reflect.TypeOf(entity.Well{}): wellFieldDocs,
+			reflect.TypeOf(apiError{}): {
+				"error":  {"Текст ошибки", ""},
+				"fields": {"Ошибки проверки по полям: ключ - JSON-имя поля", ""},
+			},
+		},
+	}
+	errSchema := b.define("Error", apiError{})
+	b.define("WellList", wellList{})
+
+	for _, route := range b.routes() {
+		b.add(route, errSchema)
+	}
+	return doc
+}
+
+// add добавляет маршрут в документ
+func (b *specBuilder) add(route apiRoute, errSchema *openAPISchema) {
+	op := &openAPIOperation{
+		Tags:        []string{route.Tag},
+		Summary:     route.Summary,
+		Description: route.Description,
+		OperationID: operationID(route.Method, route.Path),
+		Responses:   make(map[string]openAPIResponse),
+	}
+	for _, m := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+		op.Parameters = append(op.Parameters, openAPIParameter{
+			Name:        m[1],
+			In:          "path",
+			Description: pathParamDoc(route.Path, m[1]),
+			Required:    true,
+			Schema:      &openAPISchema{Type: "integer"},
+		})
+	}
+	op.Parameters = append(op.Parameters, route.Query...)
+
+	if len(route.Body) > 0 {
+		op.RequestBody = &openAPIRequestBody{Required: !route.OptionalBody, Content: make(map[string]openAPIMedia)}
+		for mime, schema := range route.Body {
+			op.RequestBody.Content[mime] = openAPIMedia{Schema: schema}
+		}
+	}
+	for code, resp := range route.Responses {
+		op.Responses[strconv.Itoa(code)] = resp
+	}
+
+	errorContent := make(map[string]openAPIMedia)
+	if route.ErrorFormat != errorsHTML {
+		errorContent[mimeJSON] = openAPIMedia{Schema: errSchema}
+	}
+	if route.ErrorFormat != errorsJSON {
+		errorContent[mimeHTML] = openAPIMedia{Schema: &openAPISchema{Type: "string"}}
+	}
+	codes := append([]int{http.StatusInternalServerError}, route.Errors...)
+	if route.Public {
+		op.Security = &[]openAPISecurity{}
+	} else {
+		codes = append(codes, http.StatusUnauthorized)
+	}
+	if route.Permission != "" && route.Permission != entity.PermRead {
+		op.Permission = route.Permission
+		roles := strings.Join(entity.RolesWith(route.Permission), ", ")
+		op.Description = strings.TrimSpace(op.Description + " Требуемое право: " +
+			string(route.Permission) + " (роли: " + roles + ").")
+		codes = append(codes, http.StatusForbidden)
+	}
+	for _, code := range codes {
+		op.Responses[strconv.Itoa(code)] = openAPIResponse{Description: errorDescriptions[code], Content: errorContent}
+	}
+
+	path, ok := b.doc.Paths[route.Path]
+	if !ok {
+		path = make(openAPIPath)
+		b.doc.Paths[route.Path] = path
+	}
+	path[strings.ToLower(route.Method)] = op
+	b.doc.operations = append(b.doc.operations, &openAPIOperationEntry{Method: route.Method, Path: route.Path, Op: op})
+}
+
+// operationID строит идентификатор операции из метода и пути:
+// GET /wells/{id}/ipr -> getWellsByIdIpr
+func operationID(method, path string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '.' || r == '-' || r == '_'
+	}) {
+		if strings.HasPrefix(part, "{") {
+			sb.WriteString("By")
+			part = strings.Trim(part, "{}")
+		}
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if path == "/" {
+		sb.WriteString("Root")
+	}
+	return sb.String()
+}
+
+// pathParamDoc - описание параметра пути name в маршруте path
+func pathParamDoc(path, name string) string {
+	if d, ok := pathParamDocs[name]; ok {
+		return d
+	}
+	switch {
+	case strings.HasPrefix(path, "/fields/"):
+		return "ID месторождения"
+	case strings.HasPrefix(path, "/pads/"):
+		return "ID куста"
+	case strings.HasPrefix(path, "/users/"):
+		return "ID пользователя"
+	default:
+		return "ID скважины"
+	}
+}
+
+// routes - все маршруты server.SetupRoutes
+func (b *specBuilder) routes() []apiRoute {
+	well := b.ref(entity.Well{})
+	list := b.ref(wellList{})
+	wellForm := b.wellFormSchema()
+	negotiated := "Ответ в JSON, если запрос в JSON или Accept: application/json; иначе HTML."
+
+	idErrors := []int{http.StatusBadRequest, http.StatusNotFound}
+	calcErrors := []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity}
+	apiErrors := []int{http.StatusBadRequest, http.StatusNotFound, http.StatusNotAcceptable}
+	apiBodyErrors := []int{
+		http.StatusBadRequest, http.StatusNotFound, http.StatusNotAcceptable,
+		http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity,
+	}
+
+	wellListQuery := []openAPIParameter{
+		queryParam("search", "string", "Часть названия или местоположения, без учета регистра"),
+		queryParam("status", "string", "Состояние скважины"),
+		queryParam("q_min", "number", "Дебит не меньше, м³/сут"),
+		queryParam("q_max", "number", "Дебит не больше, м³/сут"),
+		queryParam("pbuf_min", "number", "Буферное давление не меньше, Па"),
+		queryParam("pbuf_max", "number", "Буферное давление не больше, Па"),
+		queryParam("depth_min", "number", "Глубина не меньше, м"),
+		queryParam("depth_max", "number", "Глубина не больше, м"),
+		queryParam("sort", "string", "Сортировка: "+strings.Join(entity.WellSortKeys, ", ")+" (по умолчанию name)"),
+		queryParam("order", "string", "Порядок: asc или desc"),
+		queryParam("after", "string", "Курсор next предыдущего ответа: следующая страница"),
+		queryParam("before", "string", "Курсор prev предыдущего ответа: предыдущая страница"),
+		queryParam("limit", "integer", fmt.Sprintf("Число скважин, 1..%d (по умолчанию %d, на странице HTML - %d)",
+			maxAPILimit, defaultAPILimit, wellPageSize)),
+	}
+	var sweepNames []string
+	for _, p := range service.SweepParams() {
+		sweepNames = append(sweepNames, p.Name)
+	}
+	sweepQuery := []openAPIParameter{
+		queryParam("x", "string", "Варьируемый параметр - JSON-имя входного поля скважины: "+strings.Join(sweepNames, ", ")),
+		queryParam("x_from", "number", "Начальное значение x в единицах entity.Well"),
+		queryParam("x_to", "number", "Конечное значение x"),
+		queryParam("x_steps", "integer", "Число значений x, включая границы"),
+		queryParam("y", "string", "Второй варьируемый параметр (необязательно)"),
+		queryParam("y_from", "number", "Начальное значение y"),
+		queryParam("y_to", "number", "Конечное значение y"),
+		queryParam("y_steps", "integer", "Число значений y"),
+	}
+	sweep := b.ref(service.SweepResult{})
+	session := b.ref(service.Session{})
+	oneOrMany := func(v interface{}) *openAPISchema {
+		return &openAPISchema{OneOf: []*openAPISchema{b.ref(v), b.arrayOf(v)}}
+	}
+
+	return []apiRoute{
+		{
+			Method: http.MethodGet, Path: "/", Tag: "wells", Summary: "Переход к списку скважин",
+			Public:    true,
+			Responses: map[int]openAPIResponse{http.StatusFound: redirectResponse("Переход на /wells")},
+		},
+
+		// Скважины
+		{
+			Method: http.MethodGet, Path: "/wells", Tag: "wells", Summary: "Список скважин",
+			Description: "Поиск, фильтры и сортировка; страницы по курсору. " + negotiated, Query: wellListQuery,
+			Responses: map[int]openAPIResponse{http.StatusOK: {
+				Description: "Список скважин",
+				Content:     map[string]openAPIMedia{mimeHTML: {Schema: &openAPISchema{Type: "string"}}, mimeJSON: {Schema: list}},
+			}},
+			Errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity}, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/export.xlsx", Tag: "wells", Summary: "Выгрузка списка скважин в Excel",
+			Description: "Скважины по условиям списка (параметры страниц after, before и limit не учитываются). " +
+				"Листы: поля скважин в единицах СИ, расчетные результаты, сведения о выгрузке. " +
+				"Больше " + strconv.Itoa(service.MaxExportWells) + " скважин не выгружается (400).",
+			Permission: entity.PermCalculate,
+			Query:      wellListQuery[:10], // Без курсоров и limit
+			Responses:  map[int]openAPIResponse{http.StatusOK: fileResponse(mimeXLSX, "Файл Excel")},
+			Errors:     []int{http.StatusBadRequest}, ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/create", Tag: "wells", Summary: "Форма новой скважины",
+			Permission:  entity.PermEdit,
+			Responses:   map[int]openAPIResponse{http.StatusOK: htmlResponse("Страница формы")},
+			ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodPost, Path: "/wells", Tag: "wells", Summary: "Создание скважины",
+			Permission:  entity.PermEdit,
+			Description: negotiated,
+			Body:        map[string]*openAPISchema{mimeForm: wellForm, mimeJSON: well},
+			Responses: map[int]openAPIResponse{
+				http.StatusCreated:  jsonResponse("Скважина создана (JSON)", well),
+				http.StatusSeeOther: redirectResponse("Переход на страницу скважины (форма)"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity}, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}", Tag: "wells", Summary: "Скважина с расчетами",
+			Description: negotiated,
+			Query: []openAPIParameter{
+				queryParam("hydrate_method", "string", "Метод оценки гидратообразования: katz, motiee"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: {
+				Description: "Скважина",
+				Content:     map[string]openAPIMedia{mimeHTML: {Schema: &openAPISchema{Type: "string"}}, mimeJSON: {Schema: well}},
+			}},
+			Errors: idErrors, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/edit", Tag: "wells", Summary: "Форма редактирования скважины",
+			Permission: entity.PermEdit,
+			Responses:  map[int]openAPIResponse{http.StatusOK: htmlResponse("Страница формы")},
+			Errors:     idErrors, ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodPut, Path: "/wells/{id}", Tag: "wells", Summary: "Изменение скважины",
+			Permission:  entity.PermEdit,
+			Description: negotiated,
+			Body:        map[string]*openAPISchema{mimeForm: wellForm, mimeJSON: well},
+			Responses: map[int]openAPIResponse{
+				http.StatusOK:       jsonResponse("Скважина изменена (JSON)", well),
+				http.StatusSeeOther: redirectResponse("Переход на страницу скважины (форма)"),
+			},
+			Errors: calcErrors, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodPost, Path: "/wells/{id}", Tag: "wells", Summary: "Изменение скважины из HTML-формы",
+			Permission:  entity.PermEdit,
+			Description: "HTML-формы не поддерживают PUT. " + negotiated,
+			Body:        map[string]*openAPISchema{mimeForm: wellForm, mimeJSON: well},
+			Responses: map[int]openAPIResponse{
+				http.StatusOK:       jsonResponse("Скважина изменена (JSON)", well),
+				http.StatusSeeOther: redirectResponse("Переход на страницу скважины"),
+			},
+			Errors: calcErrors, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodDelete, Path: "/wells/{id}", Tag: "wells", Summary: "Удаление скважины",
+			Permission:  entity.PermDelete,
+			Description: negotiated,
+			Responses: map[int]openAPIResponse{
+				http.StatusNoContent: noContent("Скважина удалена (JSON)"),
+				http.StatusSeeOther:  redirectResponse("Переход к списку скважин"),
+			},
+			Errors: idErrors, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/ipr", Tag: "wells", Summary: "Индикаторная кривая",
+			Permission: entity.PermCalculate,
+			Query: []openAPIParameter{
+				queryParam("pmin", "number", "Минимальное забойное давление, Па"),
+				queryParam("pmax", "number", "Максимальное забойное давление, Па"),
+				queryParam("points", "integer", "Число точек кривой, не более 500"),
+				queryParam("drawdown", "number", "Допустимая депрессия, Па"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Кривая притока", b.ref(service.IPRResult{}))},
+			Errors:    calcErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/properties", Tag: "wells", Summary: "Свойства газа на устье и забое",
+			Permission: entity.PermCalculate,
+			Responses:  map[int]openAPIResponse{http.StatusOK: jsonResponse("Свойства газа", b.ref(service.WellGasPropertiesResult{}))},
+			Errors:     calcErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/nodal", Tag: "wells", Summary: "Узловой анализ",
+			Permission: entity.PermCalculate,
+			Query: []openAPIParameter{
+				queryParam("pbuf", "number", "Буферное давление сценария, Па (по умолчанию - скважины)"),
+				queryParam("points", "integer", "Число точек кривых, не более 500"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Кривые притока и лифта, рабочая точка", b.ref(calculations.NodalResult{}))},
+			Errors:    calcErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/choke", Tag: "wells", Summary: "Расход через штуцер и подбор штуцера",
+			Permission: entity.PermCalculate,
+			Query: []openAPIParameter{
+				queryParam("d", "number", "Диаметр штуцера, м (по умолчанию - скважины)"),
+				queryParam("pline", "number", "Давление после штуцера, Па (по умолчанию - скважины)"),
+				queryParam("q", "number", "Целевой дебит для подбора штуцера, м³/сут (по умолчанию - Q скважины)"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Расчет штуцера", b.ref(service.ChokeAnalysis{}))},
+			Errors:    calcErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/tubing", Tag: "wells", Summary: "Чувствительность к диаметру НКТ",
+			Permission: entity.PermCalculate,
+			Query: []openAPIParameter{
+				queryParam("diameters", "string", "Внутренние диаметры НКТ через запятую, м"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Режимы по диаметрам", b.ref(service.TubingSensitivityResult{}))},
+			Errors:    calcErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/tubing.xlsx", Tag: "wells", Summary: "Выгрузка чувствительности к диаметру НКТ",
+			Permission: entity.PermCalculate,
+			Query: []openAPIParameter{
+				queryParam("diameters", "string", "Внутренние диаметры НКТ через запятую, м"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: fileResponse(mimeXLSX, "Файл Excel")},
+			Errors:    calcErrors, ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/sweep", Tag: "wells", Summary: "Варьирование параметров скважины",
+			Permission: entity.PermCalculate,
+			Query:      sweepQuery,
+			Responses:  map[int]openAPIResponse{http.StatusOK: jsonResponse("Результаты по сетке значений", sweep)},
+			Errors:     calcErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/sweep.csv", Tag: "wells", Summary: "Выгрузка варьирования в CSV",
+			Permission: entity.PermCalculate,
+			Query:      sweepQuery,
+			Responses:  map[int]openAPIResponse{http.StatusOK: fileResponse(mimeCSV, "Файл CSV")},
+			Errors:     calcErrors, ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/sweep.xlsx", Tag: "wells", Summary: "Выгрузка варьирования в Excel",
+			Permission: entity.PermCalculate,
+			Query:      sweepQuery,
+			Responses:  map[int]openAPIResponse{http.StatusOK: fileResponse(mimeXLSX, "Файл Excel; для двух параметров - листы-матрицы")},
+			Errors:     calcErrors, ErrorFormat: errorsHTML,
+		},
+
+		// Жизненный цикл скважины
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/lifecycle", Tag: "wells", Summary: "Жизненный цикл скважины",
+			Description: "Текущее состояние, допустимые переходы, история переходов и суммарное время в каждом состоянии.",
+			Responses:   map[int]openAPIResponse{http.StatusOK: jsonResponse("Жизненный цикл", b.ref(service.WellLifecycle{}))},
+			Errors:      idErrors,
+		},
+		{
+			Method: http.MethodPost, Path: "/wells/{id}/status", Tag: "wells", Summary: "Смена состояния скважины",
+			Description: "Переход допускается только по графу состояний, с причиной и датой не позже сегодняшней " +
+				"и не раньше предыдущего перехода. После формы - переход на страницу скважины.",
+			Permission: entity.PermEdit,
+			Body: map[string]*openAPISchema{
+				mimeJSON: formSchema([]string{"status", "reason", "date"},
+					"status", "Новое состояние", "reason", "Причина перехода", "date", "Дата перехода: ГГГГ-ММ-ДД"),
+				mimeForm: formSchema([]string{"status", "reason", "date"},
+					"status", "Новое состояние", "reason", "Причина перехода", "date", "Дата перехода: ГГГГ-ММ-ДД"),
+			},
+			Responses: map[int]openAPIResponse{
+				http.StatusOK:       jsonResponse("Скважина в новом состоянии (JSON)", well),
+				http.StatusSeeOther: redirectResponse("Переход на страницу скважины"),
+			},
+			Errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity}, ErrorFormat: errorsMixed,
+		},
+
+		// Версии скважины
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/revisions", Tag: "wells", Summary: "Версии скважины",
+			Description: "Каждое сохранение скважины создает новую версию; версии новые первыми. " + negotiated,
+			Query: []openAPIParameter{
+				queryParam("at", "string", "Страница: состояние на момент (RFC 3339 или ГГГГ-ММ-ДД - на конец дня)"),
+				queryParam("from", "integer", "Страница: номер первой сравниваемой версии"),
+				queryParam("to", "integer", "Страница: номер второй сравниваемой версии"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: {
+				Description: "Версии скважины",
+				Content:     map[string]openAPIMedia{mimeHTML: {Schema: &openAPISchema{Type: "string"}}, mimeJSON: {Schema: b.arrayOf(entity.WellRevision{})}},
+			}},
+			Errors: idErrors, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/as-of", Tag: "wells", Summary: "Параметры скважины на момент времени",
+			Query: []openAPIParameter{
+				requiredQueryParam("at", "string", "Момент: RFC 3339 или ГГГГ-ММ-ДД (состояние на конец дня)"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Скважина в версии, действовавшей в этот момент", well)},
+			Errors:    idErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/revisions/diff", Tag: "wells", Summary: "Сравнение версий скважины",
+			Query: []openAPIParameter{
+				requiredQueryParam("from", "integer", "Номер первой версии"),
+				requiredQueryParam("to", "integer", "Номер второй версии"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Различающиеся поля", b.ref(service.RevisionDiff{}))},
+			Errors:    idErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/revisions/{revision}", Tag: "wells", Summary: "Версия скважины",
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Версия", b.ref(entity.WellRevision{}))},
+			Errors:    idErrors,
+		},
+		{
+			Method: http.MethodPost, Path: "/wells/{id}/revisions/{revision}/restore", Tag: "wells", Summary: "Восстановление версии скважины",
+			Description: "Параметры версии сохраняются как новая версия; расчетные параметры пересчитываются. " + negotiated,
+			Permission:  entity.PermEdit,
+			Responses: map[int]openAPIResponse{
+				http.StatusOK:       jsonResponse("Скважина после восстановления (JSON)", well),
+				http.StatusSeeOther: redirectResponse("Переход на страницу скважины"),
+			},
+			Errors: calcErrors, ErrorFormat: errorsMixed,
+		},
+
+		// Исследования
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/tests", Tag: "well-tests", Summary: "Исследования скважины",
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Исследования с коэффициентами A и B",
+				b.arrayOf(service.WellTestResult{}))},
+			Errors: []int{http.StatusBadRequest},
+		},
+		{
+			Method: http.MethodPost, Path: "/wells/{id}/tests", Tag: "well-tests", Summary: "Добавление исследования",
+			Permission: entity.PermEdit,
+			Body:       map[string]*openAPISchema{mimeJSON: b.ref(entity.WellTest{})},
+			Responses:  map[int]openAPIResponse{http.StatusCreated: jsonResponse("Исследование добавлено", b.ref(entity.WellTest{}))},
+			Errors:     calcErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/tests/{testID}", Tag: "well-tests", Summary: "Исследование с обработкой",
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Исследование", b.ref(service.WellTestResult{}))},
+			Errors:    idErrors,
+		},
+		{
+			Method: http.MethodDelete, Path: "/wells/{id}/tests/{testID}", Tag: "well-tests", Summary: "Удаление исследования",
+			Permission: entity.PermEdit,
+			Responses:  map[int]openAPIResponse{http.StatusNoContent: noContent("Исследование удалено")},
+			Errors:     []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			Method: http.MethodPost, Path: "/wells/{id}/tests/{testID}/apply", Tag: "well-tests", Summary: "Перенос A и B в карточку скважины",
+			Permission: entity.PermEdit,
+			Responses:  map[int]openAPIResponse{http.StatusSeeOther: redirectResponse("Переход на страницу скважины")},
+			Errors:     calcErrors, ErrorFormat: errorsHTML,
+		},
+
+		// Замеры
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/measurements", Tag: "measurements", Summary: "Замеры за период",
+			Query: []openAPIParameter{
+				queryParam("from", "string", "Начало периода: RFC 3339 или ГГГГ-ММ-ДД"),
+				queryParam("to", "string", "Конец периода: RFC 3339 или ГГГГ-ММ-ДД"),
+				queryParam("resolution", "string", "Осреднение: пусто - исходные замеры, hour, day"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Замеры", b.arrayOf(entity.Measurement{}))},
+			Errors:    calcErrors,
+		},
+		{
+			Method: http.MethodPost, Path: "/wells/{id}/measurements", Tag: "measurements", Summary: "Добавление замера или массива замеров",
+			Permission: entity.PermEdit,
+			Body:       map[string]*openAPISchema{mimeJSON: oneOrMany(entity.Measurement{})},
+			Responses:  map[int]openAPIResponse{http.StatusCreated: jsonResponse("Замеры добавлены", b.arrayOf(entity.Measurement{}))},
+			Errors:     calcErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/measurements/latest", Tag: "measurements", Summary: "Последние значения параметров",
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Последний замер", b.ref(entity.Measurement{}))},
+			Errors:    idErrors,
+		},
+		{
+			Method: http.MethodDelete, Path: "/wells/{id}/measurements/{measurementID}", Tag: "measurements", Summary: "Удаление замера",
+			Permission: entity.PermEdit,
+			Responses:  map[int]openAPIResponse{http.StatusNoContent: noContent("Замер удален")},
+			Errors:     idErrors,
+		},
+
+		// Добыча
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/production", Tag: "production", Summary: "История добычи",
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Записи истории", b.arrayOf(entity.ProductionRecord{}))},
+			Errors:    []int{http.StatusBadRequest},
+		},
+		{
+			Method: http.MethodPost, Path: "/wells/{id}/production", Tag: "production", Summary: "Добавление истории добычи",
+			Permission: entity.PermEdit,
+			Description: "JSON (объект или массив), форма с полями date и q или CSV-файл в поле file " +
+				"(столбцы: дата, дебит м³/сут). После формы - переход на страницу скважины.",
+			Body: map[string]*openAPISchema{
+				mimeJSON: oneOrMany(entity.ProductionRecord{}),
+				mimeForm: formSchema([]string{"date", "q"},
+					"date", "Дата: ГГГГ-ММ-ДД или ДД.ММ.ГГГГ", "q", "Дебит газа, м³/сут"),
+				mimeMultipart: {Type: "object", Properties: map[string]*openAPISchema{
+					"file": {Type: "string", Format: "binary", Description: "CSV: дата, дебит м³/сут"},
+				}},
+			},
+			Responses: map[int]openAPIResponse{
+				http.StatusCreated:  jsonResponse("Записи добавлены (JSON)", b.arrayOf(entity.ProductionRecord{})),
+				http.StatusSeeOther: redirectResponse("Переход на страницу скважины"),
+			},
+			Errors: calcErrors, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodDelete, Path: "/wells/{id}/production/{recordID}", Tag: "production", Summary: "Удаление записи истории",
+			Permission: entity.PermEdit,
+			Responses:  map[int]openAPIResponse{http.StatusNoContent: noContent("Запись удалена")},
+			Errors:     idErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/decline", Tag: "production", Summary: "Анализ падения дебита (Арпс) и прогноз",
+			Permission: entity.PermCalculate,
+			Query: []openAPIParameter{
+				queryParam("model", "string", "Модель: exponential, hyperbolic, harmonic; пусто - лучшая"),
+				queryParam("limit", "number", "Экономический предел, м³/сут (по умолчанию Qmin скважины)"),
+				queryParam("points", "integer", "Число точек прогноза, не более 500"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Модель и прогноз", b.ref(service.DeclineResult{}))},
+			Errors:    calcErrors,
+		},
+
+		// Пластовое давление
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/surveys", Tag: "surveys", Summary: "Замеры пластового давления",
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Замеры", b.arrayOf(entity.PressureSurvey{}))},
+			Errors:    []int{http.StatusBadRequest},
+		},
+		{
+			Method: http.MethodPost, Path: "/wells/{id}/surveys", Tag: "surveys", Summary: "Добавление замера пластового давления",
+			Permission: entity.PermEdit,
+			Body: map[string]*openAPISchema{
+				mimeJSON: b.ref(entity.PressureSurvey{}),
+				mimeForm: formSchema([]string{"survey_date", "ppl"},
+					"survey_date", "Дата замера: ГГГГ-ММ-ДД или ДД.ММ.ГГГГ",
+					"ppl", "Пластовое давление, Па", "gp", "Накопленная добыча, м³"),
+			},
+			Responses: map[int]openAPIResponse{
+				http.StatusCreated:  jsonResponse("Замер добавлен (JSON)", b.ref(entity.PressureSurvey{})),
+				http.StatusSeeOther: redirectResponse("Переход на страницу скважины"),
+			},
+			Errors: calcErrors, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodDelete, Path: "/wells/{id}/surveys/{surveyID}", Tag: "surveys", Summary: "Удаление замера пластового давления",
+			Permission: entity.PermEdit,
+			Responses:  map[int]openAPIResponse{http.StatusNoContent: noContent("Замер удален")},
+			Errors:     idErrors,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/material-balance", Tag: "surveys", Summary: "Запасы по методу P/Z",
+			Permission: entity.PermCalculate,
+			Query: []openAPIParameter{
+				queryParam("pab", "number", "Давление забрасывания, Па"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Материальный баланс", b.ref(calculations.MaterialBalanceResult{}))},
+			Errors:    calcErrors,
+		},
+
+		// Журнал изменений
+		{
+			Method: http.MethodGet, Path: "/audit", Tag: "audit", Summary: "Журнал изменений",
+			Description: "Записи новые первыми. " + negotiated,
+			Query: []openAPIParameter{
+				queryParam("entity_type", "string", "Тип объекта: well"),
+				queryParam("entity_id", "integer", "ID объекта"),
+				queryParam("action", "string", "Действие: create, update или delete"),
+				queryParam("actor", "string", "Часть email пользователя"),
+				queryParam("from", "string", "Начало интервала: RFC 3339 или ГГГГ-ММ-ДД"),
+				queryParam("to", "string", "Конец интервала: RFC 3339 или ГГГГ-ММ-ДД (дата включается целиком)"),
+				queryParam("limit", "integer", "Записей на странице, по умолчанию 50, не более 1000"),
+				queryParam("offset", "integer", "Смещение от начала выборки"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: {
+				Description: "Записи журнала",
+				Content:     map[string]openAPIMedia{mimeHTML: {Schema: &openAPISchema{Type: "string"}}, mimeJSON: {Schema: b.arrayOf(entity.AuditEntry{})}},
+			}},
+			Errors: []int{http.StatusBadRequest, http.StatusUnprocessableEntity}, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodGet, Path: "/wells/{id}/history", Tag: "audit", Summary: "История изменений скважины",
+			Description: "Записи журнала скважины, новые первыми; доступна и после удаления скважины.",
+			Responses:   map[int]openAPIResponse{http.StatusOK: jsonResponse("Записи журнала", b.arrayOf(entity.AuditEntry{}))},
+			Errors:      []int{http.StatusBadRequest},
+		},
+
+		// Месторождения и кусты
+		{
+			Method: http.MethodGet, Path: "/fields", Tag: "fields", Summary: "Месторождения с итогами",
+			Responses:   map[int]openAPIResponse{http.StatusOK: htmlResponse("Страница месторождений")},
+			ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodPost, Path: "/fields", Tag: "fields", Summary: "Создание месторождения",
+			Permission: entity.PermEdit,
+			Body: map[string]*openAPISchema{mimeForm: formSchema([]string{"name"},
+				"name", "Название", "description", "Описание")},
+			Responses: map[int]openAPIResponse{http.StatusSeeOther: redirectResponse("Переход на страницу месторождения")},
+			Errors:    []int{http.StatusBadRequest, http.StatusUnprocessableEntity}, ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodGet, Path: "/fields/{id}", Tag: "fields", Summary: "Месторождение: кусты и итоги",
+			Responses: map[int]openAPIResponse{http.StatusOK: htmlResponse("Страница месторождения")},
+			Errors:    idErrors, ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodDelete, Path: "/fields/{id}", Tag: "fields", Summary: "Удаление месторождения",
+			Permission: entity.PermDelete,
+			Responses:  map[int]openAPIResponse{http.StatusNoContent: noContent("Месторождение удалено")},
+			Errors:     idErrors,
+		},
+		{
+			Method: http.MethodPost, Path: "/fields/{id}/pads", Tag: "fields", Summary: "Создание куста",
+			Permission: entity.PermEdit,
+			Body:       map[string]*openAPISchema{mimeForm: formSchema([]string{"name"}, "name", "Название куста")},
+			Responses:  map[int]openAPIResponse{http.StatusSeeOther: redirectResponse("Переход на страницу месторождения")},
+			Errors:     calcErrors, ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodGet, Path: "/pads/{id}", Tag: "fields", Summary: "Куст: скважины и итоги",
+			Responses: map[int]openAPIResponse{http.StatusOK: htmlResponse("Страница куста")},
+			Errors:    idErrors, ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodDelete, Path: "/pads/{id}", Tag: "fields", Summary: "Удаление куста",
+			Permission: entity.PermDelete,
+			Responses:  map[int]openAPIResponse{http.StatusNoContent: noContent("Куст удален")},
+			Errors:     idErrors,
+		},
+
+		// Сеть сбора
+		{
+			Method: http.MethodGet, Path: "/pads/{id}/network", Tag: "network", Summary: "Сеть сбора куста",
+			Responses: map[int]openAPIResponse{http.StatusOK: htmlResponse("Страница сети")},
+			Errors:    idErrors, ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodGet, Path: "/pads/{id}/network/solve", Tag: "network", Summary: "Установившийся режим сети",
+			Permission: entity.PermCalculate,
+			Query: []openAPIParameter{
+				queryParam("header", "number", "Давление в коллекторе, Па"),
+				queryParam("rates", "string", "Дебиты скважин, ограниченные штуцером: \"ID:дебит\" через запятую, м³/сут"),
+			},
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Режим без ограничений и по сценарию",
+				b.ref(service.NetworkSolution{}))},
+			Errors: calcErrors,
+		},
+		{
+			Method: http.MethodPost, Path: "/pads/{id}/network/nodes", Tag: "network", Summary: "Добавление узла сети",
+			Permission: entity.PermEdit,
+			Body: map[string]*openAPISchema{
+				mimeJSON: b.ref(entity.NetworkNode{}),
+				mimeForm: formSchema([]string{"name", "kind"},
+					"name", "Название узла", "kind", "Тип узла: well, junction, header", "well_id", "ID скважины для узла well"),
+			},
+			Responses: map[int]openAPIResponse{
+				http.StatusCreated:  jsonResponse("Узел добавлен (JSON)", b.ref(entity.NetworkNode{})),
+				http.StatusSeeOther: redirectResponse("Переход на страницу сети"),
+			},
+			Errors: calcErrors, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodDelete, Path: "/pads/{id}/network/nodes/{nodeID}", Tag: "network", Summary: "Удаление узла и его участков",
+			Permission: entity.PermEdit,
+			Responses:  map[int]openAPIResponse{http.StatusNoContent: noContent("Узел удален")},
+			Errors:     []int{http.StatusBadRequest},
+		},
+		{
+			Method: http.MethodPost, Path: "/pads/{id}/network/segments", Tag: "network", Summary: "Добавление участка трубопровода",
+			Permission: entity.PermEdit,
+			Body: map[string]*openAPISchema{
+				mimeJSON: b.ref(entity.PipeSegment{}),
+				mimeForm: formSchema([]string{"from_node_id", "to_node_id", "length", "diameter"},
+					"from_node_id", "ID начального узла", "to_node_id", "ID конечного узла",
+					"length", "Длина, м", "diameter", "Внутренний диаметр, м",
+					"roughness", "Шероховатость, м", "elevation", "Перепад высот, м"),
+			},
+			Responses: map[int]openAPIResponse{
+				http.StatusCreated:  jsonResponse("Участок добавлен (JSON)", b.ref(entity.PipeSegment{})),
+				http.StatusSeeOther: redirectResponse("Переход на страницу сети"),
+			},
+			Errors: calcErrors, ErrorFormat: errorsMixed,
+		},
+		{
+			Method: http.MethodDelete, Path: "/pads/{id}/network/segments/{segmentID}", Tag: "network", Summary: "Удаление участка",
+			Permission: entity.PermEdit,
+			Responses:  map[int]openAPIResponse{http.StatusNoContent: noContent("Участок удален")},
+			Errors:     []int{http.StatusBadRequest},
+		},
+
+		// JSON API
+		{
+			Method: http.MethodGet, Path: "/api/v1/wells", Tag: "api", Summary: "Список скважин",
+			Query:     wellListQuery,
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Страница списка", list)},
+			Errors:    []int{http.StatusNotAcceptable, http.StatusUnprocessableEntity},
+		},
+		{
+			Method: http.MethodPost, Path: "/api/v1/wells", Tag: "api", Summary: "Создание скважины",
+			Permission: entity.PermEdit,
+			Body:       map[string]*openAPISchema{mimeJSON: well},
+			Responses: map[int]openAPIResponse{http.StatusCreated: {
+				Description: "Скважина создана",
+				Headers: map[string]openAPIHeader{
+					"Location": {Description: "Адрес созданной скважины", Schema: &openAPISchema{Type: "string"}},
+				},
+				Content: map[string]openAPIMedia{mimeJSON: {Schema: well}},
+			}},
+			Errors: []int{
+				http.StatusBadRequest, http.StatusNotAcceptable,
+				http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity,
+			},
+		},
+		{
+			Method: http.MethodGet, Path: "/api/v1/wells/{id}", Tag: "api", Summary: "Скважина",
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Скважина", well)},
+			Errors:    apiErrors,
+		},
+		{
+			Method: http.MethodPut, Path: "/api/v1/wells/{id}", Tag: "api", Summary: "Полная замена данных скважины",
+			Permission: entity.PermEdit,
+			Body:       map[string]*openAPISchema{mimeJSON: well},
+			Responses:  map[int]openAPIResponse{http.StatusOK: jsonResponse("Скважина изменена", well)},
+			Errors:     apiBodyErrors,
+		},
+		{
+			Method: http.MethodPatch, Path: "/api/v1/wells/{id}", Tag: "api", Summary: "Частичное изменение скважины",
+			Permission:  entity.PermEdit,
+			Description: "Меняются только переданные поля.",
+			Body: map[string]*openAPISchema{mimeJSON: {
+				Type:        "object",
+				Description: "Изменяемые поля схемы entity.Well",
+			}},
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Скважина изменена", well)},
+			Errors:    apiBodyErrors,
+		},
+		{
+			Method: http.MethodDelete, Path: "/api/v1/wells/{id}", Tag: "api", Summary: "Удаление скважины",
+			Permission: entity.PermDelete,
+			Responses:  map[int]openAPIResponse{http.StatusNoContent: noContent("Скважина удалена")},
+			Errors:     apiErrors,
+		},
+
+		// Описание API
+		{
+			Method: http.MethodGet, Path: "/api/openapi.json", Tag: "docs", Summary: "Документ OpenAPI 3",
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Этот документ", &openAPISchema{Type: "object"})},
+			Public:    true,
+		},
+		{
+			Method: http.MethodGet, Path: "/api/docs", Tag: "docs", Summary: "Страница документации API",
+			Responses:   map[int]openAPIResponse{http.StatusOK: htmlResponse("Страница документации")},
+			ErrorFormat: errorsHTML,
+			Public:      true,
+		},
+
+		// Аутентификация
+		{
+			Method: http.MethodGet, Path: "/auth/login", Tag: "auth", Summary: "Страница входа",
+			Query:       []openAPIParameter{queryParam("next", "string", "Локальный адрес перехода после входа")},
+			Responses:   map[int]openAPIResponse{http.StatusOK: htmlResponse("Форма входа")},
+			ErrorFormat: errorsHTML,
+			Public:      true,
+		},
+		{
+			Method: http.MethodPost, Path: "/auth/login", Tag: "auth", Summary: "Вход по email и паролю",
+			Description: "JSON-запрос получает токены в теле ответа. Форма получает токены в HttpOnly cookie " +
+				accessCookie + " и " + refreshCookie + " и переходит на адрес next.",
+			Body: map[string]*openAPISchema{
+				mimeJSON: b.ref(credentials{}),
+				mimeForm: formSchema([]string{"email", "password"},
+					"email", "Email пользователя", "password", "Пароль", "next", "Локальный адрес перехода после входа"),
+			},
+			Responses: map[int]openAPIResponse{
+				http.StatusOK:       jsonResponse("Пользователь и токены", session),
+				http.StatusSeeOther: redirectResponse("Вход выполнен, cookie установлены"),
+			},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized},
+			ErrorFormat: errorsMixed,
+			Public:      true,
+		},
+		{
+			Method: http.MethodPost, Path: "/auth/refresh", Tag: "auth", Summary: "Обмен refresh-токена на новую пару",
+			Description: "Токен берется из тела запроса или из cookie " + refreshCookie + ". Использованный токен " +
+				"отзывается; повторное предъявление отозванного токена отзывает все токены пользователя.",
+			Body:         map[string]*openAPISchema{mimeJSON: b.ref(refreshRequest{})},
+			OptionalBody: true,
+			Responses:    map[int]openAPIResponse{http.StatusOK: jsonResponse("Пользователь и новые токены", session)},
+			Errors:       []int{http.StatusBadRequest, http.StatusUnauthorized},
+			Public:       true,
+		},
+		{
+			Method: http.MethodPost, Path: "/auth/logout", Tag: "auth", Summary: "Выход",
+			Description:  "Отзывает refresh-токен из тела запроса или из cookie и удаляет cookie сессии.",
+			Body:         map[string]*openAPISchema{mimeJSON: b.ref(refreshRequest{})},
+			OptionalBody: true,
+			Responses: map[int]openAPIResponse{
+				http.StatusNoContent: noContent("Выход выполнен (JSON-запрос)"),
+				http.StatusSeeOther:  redirectResponse("Переход на страницу входа"),
+			},
+			Errors:      []int{http.StatusBadRequest},
+			ErrorFormat: errorsJSON,
+			Public:      true,
+		},
+		{
+			Method: http.MethodGet, Path: "/auth/me", Tag: "auth", Summary: "Текущий пользователь",
+			Responses: map[int]openAPIResponse{http.StatusOK: jsonResponse("Пользователь", b.ref(entity.User{}))},
+		},
+
+		// Пользователи
+		{
+			Method: http.MethodGet, Path: "/users", Tag: "users", Summary: "Пользователи и роли",
+			Permission:  entity.PermManageUsers,
+			Responses:   map[int]openAPIResponse{http.StatusOK: htmlResponse("Страница пользователей")},
+			ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodPost, Path: "/users", Tag: "users", Summary: "Добавление пользователя",
+			Permission: entity.PermManageUsers,
+			Body: map[string]*openAPISchema{mimeForm: formSchema([]string{"email", "username", "password"},
+				"email", "Email", "username", "Имя пользователя", "password",
+				"Пароль: не короче 8 символов, строчные и прописные буквы, цифра и символ",
+				"role", "Роль: "+strings.Join(entity.Roles, ", ")+" (по умолчанию viewer)")},
+			Responses:   map[int]openAPIResponse{http.StatusSeeOther: redirectResponse("Переход к списку пользователей")},
+			Errors:      []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+			ErrorFormat: errorsHTML,
+		},
+		{
+			Method: http.MethodPost, Path: "/users/{id}/role", Tag: "users", Summary: "Смена роли пользователя",
+			Description: "Нельзя лишить прав последнего администратора.",
+			Permission:  entity.PermManageUsers,
+			Body: map[string]*openAPISchema{mimeForm: formSchema([]string{"role"},
+				"role", "Роль: "+strings.Join(entity.Roles, ", "))},
+			Responses:   map[int]openAPIResponse{http.StatusSeeOther: redirectResponse("Переход к списку пользователей")},
+			Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+			ErrorFormat: errorsHTML,
+		},
+	}
+}
@@ -0,0 +1,263 @@
+// internal/handler/production_handler.go
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Максимальный размер загружаемого CSV-файла
+const maxUploadSize = 10 << 20
+
+type ProductionHandler struct {
+	baseHandler
+	service *service.ProductionService
+}
+
+func NewProductionHandler(service *service.ProductionService, templates Templates, log logger.Logger) *ProductionHandler {
+	return &ProductionHandler{
+		baseHandler: newBaseHandler(templates, log),
+		service:     service,
+	}
+}
+
+// ListRecords - история добычи скважины (JSON)
+func (h *ProductionHandler) ListRecords(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	records, err := h.service.ListRecords(r.Context(), wellID)
+	if err != nil {
+		h.logger.Error("failed to list production records", "well_id", wellID, "error", err)
+		h.respondError(w, "Failed to load production history", http.StatusInternalServerError)
+		return
+	}
+	if records == nil {
+		records = []*entity.ProductionRecord{}
+	}
+
+	h.respondJSON(w, records, http.StatusOK)
+}
+
+// AddRecords - добавление истории добычи. Принимает JSON (объект или массив),
+// форму с полями date и q или CSV-файл в поле file (столбцы: дата, дебит м³/сут).
+// После отправки формы выполняется переход на страницу скважины.
+func (h *ProductionHandler) AddRecords(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	isJSON := strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+	fail := func(msg string, status int) {
+		if isJSON {
+			h.respondError(w, msg, status)
+		} else {
+			h.renderError(w, r, msg, status)
+		}
+	}
+
+	var records []*entity.ProductionRecord
+	switch {
+	case isJSON:
+		records, err = decodeOneOrMany[entity.ProductionRecord](r.Body)
+	case strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data"):
+		records, err = h.uploadedRecords(r)
+	default:
+		records, err = productionFormRecord(r)
+	}
+	if err != nil {
+		fail("Invalid production data: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.AddRecords(r.Context(), wellID, records)
+	if errors.Is(err, service.ErrWellNotFound) {
+		fail("Well not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidProduction) {
+		fail(err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to add production records", "well_id", wellID, "error", err)
+		fail("Failed to save production records", http.StatusInternalServerError)
+		return
+	}
+
+	if isJSON {
+		h.respondJSON(w, records, http.StatusCreated)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/wells/%d", wellID), http.StatusSeeOther)
+}
+
+// DeleteRecord - удаление записи истории
+func (h *ProductionHandler) DeleteRecord(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+	id, err := urlParamInt(r, "recordID")
+	if err != nil {
+		h.respondError(w, "Invalid record ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteRecord(r.Context(), wellID, id); err != nil {
+		h.logger.Error("failed to delete production record", "id", id, "error", err)
+		h.respondError(w, "Failed to delete production record", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeclineAnalysis - кривая падения Арпса и прогноз (JSON).
+// Параметры: model (exponential, hyperbolic, harmonic; пусто - лучшая),
+// limit - экономический предел, м³/сут (по умолчанию Qmin скважины).
+func (h *ProductionHandler) DeclineAnalysis(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	model, err := calculations.ParseDeclineModel(query.Get("model"))
+	if err != nil {
+		h.respondError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	points, err := parsePoints(query.Get("points"))
+	if err != nil {
+		h.respondError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.service.DeclineAnalysis(r.Context(), wellID, service.DeclineQuery{
+		Model:         model,
+		EconomicLimit: parseFloat(query.Get("limit")),
+		Points:        points,
+	})
+	if errors.Is(err, service.ErrWellNotFound) {
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidProduction) || errors.Is(err, service.ErrCalculation) {
+		h.respondError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to run decline analysis", "well_id", wellID, "error", err)
+		h.respondError(w, "Failed to run decline analysis", http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, res, http.StatusOK)
+}
+
+// uploadedRecords читает историю добычи из загруженного CSV-файла
+func (h *ProductionHandler) uploadedRecords(r *http.Request) ([]*entity.ProductionRecord, error) {
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		return nil, err
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseProductionCSV(file)
+}
+
+func productionFormRecord(r *http.Request) ([]*entity.ProductionRecord, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	date, err := parseDate(r.FormValue("date"))
+	if err != nil {
+		return nil, err
+	}
+	q, err := parseDecimal(r.FormValue("q"))
+	if err != nil {
+		return nil, err
+	}
+	return []*entity.ProductionRecord{{Date: date, Q: q}}, nil
+}
+
+// parseProductionCSV разбирает CSV со столбцами "дата, дебит". Разделитель -
+// запятая или точка с запятой (выгрузка Excel), строка заголовка пропускается.
+func parseProductionCSV(src io.Reader) ([]*entity.ProductionRecord, error) {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff")
+
+	reader := csv.NewReader(strings.NewReader(text))
+	firstLine, _, _ := strings.Cut(text, "\n")
+	if strings.Contains(firstLine, ";") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*entity.ProductionRecord
+	for i, row := range rows {
+		if len(row) < 2 || strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		date, dateErr := parseDate(row[0])
+		q, qErr := parseDecimal(row[1])
+		if dateErr != nil || qErr != nil {
+			if i == 0 {
+				continue // Заголовок
+			}
+			return nil, fmt.Errorf("line %d: %w", i+1, errors.Join(dateErr, qErr))
+		}
+		records = append(records, &entity.ProductionRecord{Date: date, Q: q})
+	}
+	return records, nil
+}
+
+// parseDate разбирает дату в формате ГГГГ-ММ-ДД или ДД.ММ.ГГГГ
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.DateOnly, "02.01.2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// parseDecimal разбирает число с точкой или запятой в качестве разделителя
+func parseDecimal(s string) (float64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	f, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return f, nil
+}
@@ -0,0 +1,89 @@
+// internal/handler/templates.go
+package handler
+
+import (
+	"fmt"
+	"gas_wells/internal/entity"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Templates - набор страниц, каждая собрана вместе с base.html и partials,
+// чтобы блоки "title" и "content" разных страниц не перекрывали друг друга
+type Templates map[string]*template.Template
+
+// LoadTemplates загружает шаблоны из каталога dir.
+// Ключ страницы - путь относительно dir, например "wells/view.html".
+func LoadTemplates(dir string) (Templates, error) {
+	layout, err := template.New("").Funcs(templateFuncs).ParseFiles(filepath.Join(dir, "base.html"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base template: %w", err)
+	}
+	if _, err := layout.ParseGlob(filepath.Join(dir, "partials", "*.html")); err != nil {
+		return nil, fmt.Errorf("failed to parse partials: %w", err)
+	}
+
+	pages, err := filepath.Glob(filepath.Join(dir, "*", "*.html"))
+	if err != nil {
+		return nil, err
+	}
+	pages = append(pages, filepath.Join(dir, "error.html"))
+
+	templates := make(Templates, len(pages))
+	for _, page := range pages {
+		name, _ := filepath.Rel(dir, page)
+		name = filepath.ToSlash(name)
+		if strings.HasPrefix(name, "partials/") {
+			continue
+		}
+
+		tmpl, err := layout.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tmpl.ParseFiles(page); err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+		}
+		templates[name] = tmpl
+	}
+
+	return templates, nil
+}
+
+// Render выполняет страницу name в общем макете base.html
+func (t Templates) Render(w http.ResponseWriter, name string, data map[string]interface{}) error {
+	tmpl, ok := t[name]
+	if !ok {
+		return fmt.Errorf("template %s not found", name)
+	}
+	return tmpl.ExecuteTemplate(w, "base.html", data)
+}
+
+// Функции, доступные в шаблонах
+var templateFuncs = template.FuncMap{
+	// mpa переводит давление из Па в МПа для отображения
+	"mpa": func(p float64) string { return fmt.Sprintf("%.3f", p/1e6) },
+	// celsius переводит температуру из К в °C для отображения
+	"celsius": func(t float64) string { return fmt.Sprintf("%.1f", t-273.15) },
+	"mul":     func(a, b float64) float64 { return a * b },
+	"list":    func(v ...string) []string { return v },
+	// statusName - название состояния скважины
+	"statusName": entity.WellStatusName,
+	// days - число полных суток, прошедших с даты since
+	"days": func(since *time.Time) int {
+		if since == nil {
+			return 0
+		}
+		return int(time.Since(*since).Hours() / 24)
+	},
+	// deref возвращает значение необязательного ID или 0
+	"deref": func(p *int) int {
+		if p == nil {
+			return 0
+		}
+		return *p
+	},
+}
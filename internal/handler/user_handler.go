@@ -0,0 +1,86 @@
+// internal/handler/user_handler.go
+package handler
+
+import (
+	"errors"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"net/http"
+)
+
+// UserHandler - управление пользователями (только для администраторов)
+type UserHandler struct {
+	baseHandler
+	service *service.AuthService
+}
+
+func NewUserHandler(service *service.AuthService, templates Templates, log logger.Logger) *UserHandler {
+	return &UserHandler{
+		baseHandler: newBaseHandler(templates, log),
+		service:     service,
+	}
+}
+
+// ListUsers - пользователи и их роли
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.service.ListUsers(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list users", "error", err)
+		h.renderError(w, r, "Failed to load users", http.StatusInternalServerError)
+		return
+	}
+
+	h.renderTemplate(w, r, "users/list.html", map[string]interface{}{
+		"Title": "Пользователи",
+		"Users": users,
+		"Roles": entity.Roles,
+	})
+}
+
+// CreateUser - добавление пользователя (форма)
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	_, err := h.service.CreateUser(r.Context(), &entity.User{
+		Email:    r.FormValue("email"),
+		Username: r.FormValue("username"),
+		Role:     r.FormValue("role"),
+	}, r.FormValue("password"))
+	if err != nil {
+		h.logger.Error("failed to create user", "error", err)
+		h.renderError(w, r, "Failed to create user: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
+
+// SetRole - смена роли пользователя (форма)
+func (h *UserHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.renderError(w, r, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, r, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.service.SetRole(r.Context(), id, r.FormValue("role"))
+	if errors.Is(err, service.ErrUserNotFound) {
+		h.renderError(w, r, "User not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to change role", "id", id, "error", err)
+		h.renderError(w, r, "Failed to change role: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	http.Redirect(w, r, "/users", http.StatusSeeOther)
+}
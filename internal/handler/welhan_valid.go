@@ -10,7 +10,7 @@ package handler
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		h.renderError(w, "Invalid request body", http.StatusBadRequest)
+		h.renderError(w, r, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
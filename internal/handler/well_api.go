@@ -0,0 +1,326 @@
+// internal/handler/well_api.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/pkg/validation"
+	"gas_wells/internal/service"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultAPILimit = 100
+	maxAPILimit     = 1000
+)
+
+// RegisterAPIRoutes регистрирует маршруты JSON API скважин; r - подроутер /api/v1
+func (h *WellHandler) RegisterAPIRoutes(r chi.Router) {
+	r.Get("/wells", h.ListWellsJSON)
+	r.Post("/wells", h.CreateWellJSON)
+	r.Get("/wells/{id}", h.GetWellJSON)
+	r.Put("/wells/{id}", h.UpdateWellJSON)
+	r.Patch("/wells/{id}", h.PatchWellJSON)
+	r.Delete("/wells/{id}", h.DeleteWellJSON)
+}
+
+// wellList - страница списка скважин в JSON API
+type wellList struct {
+	Wells []*entity.Well `json:"wells"`
+	Total int            `json:"total"` // Скважин по условиям выборки на всех страницах
+	Limit int            `json:"limit"`
+	Next  string         `json:"next,omitempty"` // Курсор следующей страницы: параметр after
+	Prev  string         `json:"prev,omitempty"` // Курсор предыдущей страницы: параметр before
+}
+
+// ListWellsJSON - список скважин (JSON). Параметры выборки - см. wellQuery.
+func (h *WellHandler) ListWellsJSON(w http.ResponseWriter, r *http.Request) {
+	q, v := wellQuery(r.URL.Query(), defaultAPILimit)
+	if v.Errors["after"] != "" || v.Errors["before"] != "" {
+		// Курсор не вводится пользователем: поврежденный курсор - неверный запрос
+		h.respondJSON(w, apiError{Error: "invalid page cursor", Fields: v.Errors}, http.StatusBadRequest)
+		return
+	}
+	if !v.Valid() {
+		h.respondValidationErrors(w, v.Errors)
+		return
+	}
+
+	page, err := h.service.ListWells(r.Context(), q)
+	if err != nil {
+		h.logger.Error("failed to list wells", "error", err)
+		h.respondError(w, "Failed to load wells", http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, wellList{Wells: page.Wells, Total: page.Total, Limit: q.Limit, Next: page.Next, Prev: page.Prev}, http.StatusOK)
+}
+
+// wellQuery разбирает параметры выборки списка скважин: search (часть
+// названия или местоположения), status, диапазоны q_min/q_max, pbuf_min/pbuf_max,
+// depth_min/depth_max в единицах entity.Well, sort (ключ entity.WellSortKeys),
+// order (asc, desc), курсоры after/before и limit.
+func wellQuery(values url.Values, defaultLimit int) (entity.WellQuery, *validation.Validator) {
+	v := validation.New()
+	q := entity.WellQuery{
+		Search: strings.TrimSpace(values.Get("search")),
+		Status: values.Get("status"),
+		Sort:   values.Get("sort"),
+		Limit:  defaultLimit,
+	}
+	v.Check(q.Status == "" || entity.ValidWellStatus(q.Status), "status", "unknown well status")
+	v.Check(q.Sort == "" || entity.ValidWellSortKey(q.Sort), "sort",
+		"sort must be one of: "+strings.Join(entity.WellSortKeys, ", "))
+	switch values.Get("order") {
+	case "", "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		v.AddError("order", "order must be asc or desc")
+	}
+
+	bound := func(key string) *float64 {
+		s := values.Get(key)
+		if s == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		v.Check(err == nil, key, key+" must be a number")
+		return &f
+	}
+	q.Q = entity.Range{Min: bound("q_min"), Max: bound("q_max")}
+	q.Pbuf = entity.Range{Min: bound("pbuf_min"), Max: bound("pbuf_max")}
+	q.Depth = entity.Range{Min: bound("depth_min"), Max: bound("depth_max")}
+
+	if s := values.Get("limit"); s != "" {
+		var err error
+		q.Limit, err = strconv.Atoi(s)
+		v.Check(err == nil && q.Limit > 0 && q.Limit <= maxAPILimit, "limit", "limit must be between 1 and 1000")
+	}
+
+	sortKey := q.Sort
+	if sortKey == "" {
+		sortKey = "name"
+	}
+	var err error
+	if s := values.Get("after"); s != "" {
+		q.After, err = entity.DecodeWellCursor(s, sortKey)
+		v.Check(err == nil, "after", "invalid cursor for this sort order")
+	}
+	if s := values.Get("before"); s != "" {
+		q.Before, err = entity.DecodeWellCursor(s, sortKey)
+		v.Check(err == nil, "before", "invalid cursor for this sort order")
+		v.Check(q.After == nil, "before", "after and before cannot be combined")
+	}
+	return q, v
+}
+
+// GetWellJSON - скважина по ID (JSON)
+func (h *WellHandler) GetWellJSON(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	well, err := h.service.GetWell(r.Context(), id)
+	if errors.Is(err, service.ErrWellNotFound) {
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get well", "id", id, "error", err)
+		h.respondError(w, "Failed to load well", http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, well, http.StatusOK)
+}
+
+// CreateWellJSON - создание скважины (JSON); ответ 201 с созданной скважиной
+func (h *WellHandler) CreateWellJSON(w http.ResponseWriter, r *http.Request) {
+	well := &entity.Well{}
+	if err := json.NewDecoder(r.Body).Decode(well); err != nil {
+		h.respondError(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	well.ID = 0
+	well.Warnings, well.HydrateRisk = nil, ""
+
+	if v := validateWell(well); !v.Valid() {
+		h.respondValidationErrors(w, v.Errors)
+		return
+	}
+
+	created, err := h.service.CreateWell(r.Context(), well)
+	if err != nil {
+		h.respondWellError(w, err, "create well")
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/wells/"+strconv.Itoa(created.ID))
+	h.respondJSON(w, created, http.StatusCreated)
+}
+
+// UpdateWellJSON - полная замена данных скважины (JSON)
+func (h *WellHandler) UpdateWellJSON(w http.ResponseWriter, r *http.Request) {
+	h.saveWellJSON(w, r, false)
+}
+
+// PatchWellJSON - частичное обновление: меняются только переданные поля (JSON)
+func (h *WellHandler) PatchWellJSON(w http.ResponseWriter, r *http.Request) {
+	h.saveWellJSON(w, r, true)
+}
+
+func (h *WellHandler) saveWellJSON(w http.ResponseWriter, r *http.Request, partial bool) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	well := &entity.Well{}
+	if partial {
+		// Поля из тела запроса накладываются на сохраненную скважину
+		well, err = h.service.GetWell(r.Context(), id)
+		if errors.Is(err, service.ErrWellNotFound) {
+			h.respondError(w, "Well not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			h.logger.Error("failed to get well", "id", id, "error", err)
+			h.respondError(w, "Failed to load well", http.StatusInternalServerError)
+			return
+		}
+	}
+	if err := json.NewDecoder(r.Body).Decode(well); err != nil {
+		h.respondError(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	well.ID = id
+	well.Warnings, well.HydrateRisk = nil, ""
+
+	if v := validateWell(well); !v.Valid() {
+		h.respondValidationErrors(w, v.Errors)
+		return
+	}
+
+	updated, err := h.service.UpdateWell(r.Context(), well)
+	if err != nil {
+		h.respondWellError(w, err, "update well", "id", id)
+		return
+	}
+
+	h.respondJSON(w, updated, http.StatusOK)
+}
+
+// DeleteWellJSON - удаление скважины; ответ 204 без тела
+func (h *WellHandler) DeleteWellJSON(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.DeleteWell(r.Context(), id)
+	if errors.Is(err, service.ErrWellNotFound) {
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to delete well", "id", id, "error", err)
+		h.respondError(w, "Failed to delete well", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondWellError отвечает на ошибку сервиса скважин: ErrWellNotFound - 404,
+// ошибки исходных данных - 422 с ошибками по полям; прочие ошибки
+// записываются в журнал, а клиент получает 500 без подробностей.
+// action - действие для сообщения, например "update well".
+func (h *WellHandler) respondWellError(w http.ResponseWriter, err error, action string, args ...any) {
+	if errors.Is(err, service.ErrWellNotFound) {
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	}
+	if fields := wellInputErrors(err); fields != nil {
+		h.respondValidationErrors(w, fields)
+		return
+	}
+	h.logger.Error("failed to "+action, append(args, "error", err)...)
+	h.respondError(w, "Failed to "+action, http.StatusInternalServerError)
+}
+
+// wellFailure возвращает HTTP-статус и текст ответа на ошибку сервиса скважин
+// для расчетов, которые отдаются и в JSON, и файлом выгрузки: ErrWellNotFound -
+// 404, ошибки исходных данных - 422 с текстом ошибки, прочие - 500 без подробностей
+func (h *WellHandler) wellFailure(err error, action string, args ...any) (int, string) {
+	if errors.Is(err, service.ErrWellNotFound) {
+		return http.StatusNotFound, "Well not found"
+	}
+	if wellInputErrors(err) != nil {
+		return http.StatusUnprocessableEntity, err.Error()
+	}
+	h.logger.Error("failed to "+action, append(args, "error", err)...)
+	return http.StatusInternalServerError, "Failed to " + action
+}
+
+// wellInputErrors - ошибки исходных данных по полям (JSON-имена полей скважины,
+// well - данные в целом, calculation - расчет); nil - ошибка не связана с данными
+func wellInputErrors(err error) map[string]string {
+	v := validation.New()
+	switch {
+	case errors.Is(err, service.ErrInvalidTransition):
+		v.AddError("status", err.Error())
+	case errors.Is(err, service.ErrInvalidWell):
+		v.AddError("well", err.Error())
+	case errors.Is(err, service.ErrCalculation):
+		v.AddError("calculation", err.Error())
+	default:
+		return nil
+	}
+	return v.Errors
+}
+
+// validateWell проверяет исходные данные скважины; ключи ошибок - JSON-имена полей
+func validateWell(well *entity.Well) *validation.Validator {
+	v := validation.New()
+	v.Check(validation.NotBlank(well.Name), "name", "name is required")
+	v.Check(validation.MaxLength(well.Name, 100), "name", "name must be at most 100 characters")
+	v.Check(validation.Between(well.GammaG, 0.5, 2), "gamma", "gas gravity must be between 0.5 and 2")
+	v.Check(well.Diameter > 0, "diameter", "tubing diameter must be positive, m")
+	v.Check(well.Temp > 0, "temp", "reservoir temperature must be positive, K")
+	v.Check(well.TempUst > 0, "tempust", "wellhead temperature must be positive, K")
+	v.Check(well.Pbuf > 0, "pbuf", "wellhead pressure must be positive, Pa")
+
+	nonNegative := []struct {
+		key   string
+		value float64
+	}{
+		{"depth", well.Depth}, {"ptb", well.Ptb}, {"ppl", well.Ppl}, {"pz", well.Pz},
+		{"q", well.Q}, {"roughness", well.Roughness}, {"a", well.A}, {"b", well.B},
+		{"mu", well.Mu}, {"wgf", well.WGF}, {"rog", well.Rog}, {"hw", well.Hw},
+		{"qmin", well.Qmin}, {"pmax", well.Pmax}, {"choke", well.Choke}, {"pline", well.Pline},
+	}
+	for _, f := range nonNegative {
+		v.Check(f.value >= 0, f.key, f.key+" must be non-negative")
+	}
+
+	if _, err := calculations.ParseUnloadingModel(well.QminModel); err != nil {
+		v.AddError("qmin_model", err.Error())
+	}
+	if well.Status != "" {
+		v.Check(entity.ValidWellStatus(well.Status), "status",
+			"status must be one of "+strings.Join(entity.WellStatuses, ", "))
+	}
+	return v
+}
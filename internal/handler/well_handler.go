@@ -2,71 +2,201 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/pkg/exporter"
 	"gas_wells/internal/pkg/logger"
 	"gas_wells/internal/service"
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
-	"text/template"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type WellHandler struct {
-	service   *service.WellService
-	logger    logger.Logger
-	templates *template.Template
+	baseHandler
+	service *service.WellService
+	fields  *service.FieldService
 }
 
-func NewWellHandler(service *service.WellService, log logger.Logger) *WellHandler {
+func NewWellHandler(service *service.WellService, fields *service.FieldService, templates Templates, log logger.Logger) *WellHandler {
 	return &WellHandler{
-		service: service,
-		logger:  log.With("layer", "handler"),
+		baseHandler: newBaseHandler(templates, log),
+		service:     service,
+		fields:      fields,
 	}
 }
 
 func (h *WellHandler) RegisterRoutes(r chi.Router) {
 	r.Get("/wells", h.ListWells)
+	r.Get("/wells/export.xlsx", h.ExportWells)
 	r.Get("/wells/create", h.CreateWellForm)
 	r.Post("/wells", h.CreateWell)
 	r.Get("/wells/{id}", h.GetWell)
 	r.Get("/wells/{id}/edit", h.EditWellForm)
 	r.Put("/wells/{id}", h.UpdateWell)
+	r.Post("/wells/{id}", h.UpdateWell)
 	r.Delete("/wells/{id}", h.DeleteWell)
+	r.Get("/wells/{id}/ipr", h.InflowPerformance)
+	r.Get("/wells/{id}/properties", h.GasProperties)
+	r.Get("/wells/{id}/nodal", h.NodalAnalysis)
+	r.Get("/wells/{id}/choke", h.ChokeAnalysis)
+	r.Get("/wells/{id}/tubing", h.TubingSensitivity)
+	r.Get("/wells/{id}/tubing.xlsx", h.ExportTubingSensitivity)
+	r.Get("/wells/{id}/sweep", h.Sweep)
+	r.Get("/wells/{id}/sweep.csv", h.ExportSweepCSV)
+	r.Get("/wells/{id}/sweep.xlsx", h.ExportSweepExcel)
+	r.Get("/wells/{id}/lifecycle", h.Lifecycle)
+	r.Post("/wells/{id}/status", h.ChangeStatus)
+	r.Get("/wells/{id}/as-of", h.WellAsOf)
+	r.Get("/wells/{id}/revisions", h.Revisions)
+	r.Get("/wells/{id}/revisions/diff", h.RevisionDiff)
+	r.Get("/wells/{id}/revisions/{revision}", h.GetRevision)
+	r.Post("/wells/{id}/revisions/{revision}/restore", h.RestoreRevision)
 }
 
-// ListWells - отображает список всех скважин
+// wellPageSize - скважин на странице списка
+const wellPageSize = 50
+
+// ListWells - список скважин с поиском, фильтрами и сортировкой;
+// параметры запроса - см. wellQuery
 func (h *WellHandler) ListWells(w http.ResponseWriter, r *http.Request) {
-	wells, err := h.service.ListWells(r.Context(), 100, 1)
+	if wantsJSON(r) {
+		h.ListWellsJSON(w, r)
+		return
+	}
+	q, v := wellQuery(r.URL.Query(), wellPageSize)
+	if !v.Valid() {
+		h.renderError(w, r, "Invalid list parameters: "+joinErrors(v.Errors), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.service.ListWells(r.Context(), q)
 	if err != nil {
 		h.logger.Error("failed to list wells", "error", err)
-		h.renderError(w, "Failed to load wells", http.StatusInternalServerError)
+		h.renderError(w, r, "Failed to load wells", http.StatusInternalServerError)
 		return
 	}
 
+	// Ссылки сохраняют фильтры запроса; смена сортировки начинает список сначала
+	link := func(set map[string]string) string {
+		query := r.URL.Query()
+		query.Del("after")
+		query.Del("before")
+		for k, val := range set {
+			query.Set(k, val)
+		}
+		return "/wells?" + query.Encode()
+	}
+	sortKey, order := q.Sort, "asc"
+	if sortKey == "" {
+		sortKey = "name"
+	}
+	if q.Desc {
+		order = "desc"
+	}
+	sortLinks := make(map[string]string, len(entity.WellSortKeys))
+	for _, key := range entity.WellSortKeys {
+		next := "asc"
+		if key == sortKey && !q.Desc {
+			next = "desc"
+		}
+		sortLinks[key] = link(map[string]string{"sort": key, "order": next})
+	}
+
 	data := map[string]interface{}{
-		"Title": "Список скважин",
-		"Wells": wells,
+		"Title":     "Список скважин",
+		"Wells":     page.Wells,
+		"Total":     page.Total,
+		"Query":     r.URL.Query(),
+		"Statuses":  entity.WellStatuses,
+		"Sort":      sortKey,
+		"Order":     order,
+		"SortLinks": sortLinks,
+	}
+	export := r.URL.Query()
+	for _, key := range []string{"after", "before", "limit"} {
+		export.Del(key)
+	}
+	data["ExportURL"] = "/wells/export.xlsx?" + export.Encode()
+	if page.Prev != "" {
+		data["PrevPage"] = link(map[string]string{"before": page.Prev})
+	}
+	if page.Next != "" {
+		data["NextPage"] = link(map[string]string{"after": page.Next})
+	}
+
+	h.renderTemplate(w, r, "wells/list.html", data)
+}
+
+// joinErrors - ошибки проверки параметров одной строкой в порядке полей
+func joinErrors(fields map[string]string) string {
+	keys := slices.Sorted(maps.Keys(fields))
+	messages := make([]string, len(keys))
+	for i, key := range keys {
+		messages[i] = fields[key]
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ExportWells - выгрузка скважин в Excel по условиям списка (параметры
+// wellQuery, кроме страниц): поля скважин, расчетные результаты и сведения
+// о выгрузке на отдельных листах
+func (h *WellHandler) ExportWells(w http.ResponseWriter, r *http.Request) {
+	q, v := wellQuery(r.URL.Query(), 0)
+	if !v.Valid() {
+		h.renderError(w, r, "Invalid list parameters: "+joinErrors(v.Errors), http.StatusBadRequest)
+		return
+	}
+	q.After, q.Before, q.Limit = nil, nil, 0
+
+	book, err := h.service.ExportWells(r.Context(), q)
+	if errors.Is(err, service.ErrExportTooLarge) {
+		h.renderError(w, r, "Too many wells to export: "+err.Error(), http.StatusBadRequest)
+		return
 	}
+	if err != nil {
+		h.logger.Error("failed to export wells", "error", err)
+		h.renderError(w, r, "Failed to export wells", http.StatusInternalServerError)
+		return
+	}
+	defer book.Close()
 
-	h.renderTemplate(w, "wells/list.html", data)
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="wells_%s.xlsx"`, time.Now().Format("20060102_1504")))
+	if _, err := book.WriteTo(w); err != nil {
+		h.logger.Error("failed to write wells export", "error", err)
+	}
 }
 
 // CreateWellForm - форма создания новой скважины
 func (h *WellHandler) CreateWellForm(w http.ResponseWriter, r *http.Request) {
 	data := map[string]interface{}{
-		"Title": "Новая скважина",
-		"Well":  &entity.Well{}, // Пустая скважина для формы
+		"Title":    "Новая скважина",
+		"Well":     &entity.Well{}, // Пустая скважина для формы
+		"Pads":     h.padChoices(r),
+		"Statuses": entity.WellStatuses,
 	}
 
-	h.renderTemplate(w, "wells/edit.html", data)
+	h.renderTemplate(w, r, "wells/edit.html", data)
 }
 
 // CreateWell - обработчик создания скважины
 func (h *WellHandler) CreateWell(w http.ResponseWriter, r *http.Request) {
+	if wantsJSON(r) {
+		h.CreateWellJSON(w, r)
+		return
+	}
 	if err := r.ParseForm(); err != nil {
-		h.renderError(w, "Invalid form data", http.StatusBadRequest)
+		h.renderError(w, r, "Invalid form data", http.StatusBadRequest)
 		return
 	}
 
@@ -92,12 +222,17 @@ func (h *WellHandler) CreateWell(w http.ResponseWriter, r *http.Request) {
 		Qmin:      parseFloat(r.FormValue("qmin")),
 		Pmax:      parseFloat(r.FormValue("pmax")),
 		Status:    r.FormValue("status"),
+		QminModel: r.FormValue("qmin_model"),
+		MuManual:  r.FormValue("mu_manual") != "",
+		PzManual:  r.FormValue("pz_manual") != "",
+		PadID:     parseOptionalID(r.FormValue("pad_id")),
+		Choke:     parseFloat(r.FormValue("choke")),
+		Pline:     parseFloat(r.FormValue("pline")),
 	}
 
 	createdWell, err := h.service.CreateWell(r.Context(), well)
 	if err != nil {
-		h.logger.Error("failed to create well", "error", err)
-		h.renderError(w, "Failed to create well", http.StatusInternalServerError)
+		h.wellFormError(w, r, well, err, "create well")
 		return
 	}
 
@@ -106,64 +241,102 @@ func (h *WellHandler) CreateWell(w http.ResponseWriter, r *http.Request) {
 
 // GetWell - отображает детали скважины
 func (h *WellHandler) GetWell(w http.ResponseWriter, r *http.Request) {
+	if wantsJSON(r) {
+		h.GetWellJSON(w, r)
+		return
+	}
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		h.renderError(w, "Invalid well ID", http.StatusBadRequest)
+		h.renderError(w, r, "Invalid well ID", http.StatusBadRequest)
 		return
 	}
 
 	well, err := h.service.GetWell(r.Context(), id)
 	if err != nil {
 		h.logger.Error("failed to get well", "id", id, "error", err)
-		h.renderError(w, "Well not found", http.StatusNotFound)
+		h.renderError(w, r, "Well not found", http.StatusNotFound)
 		return
 	}
 
 	data := map[string]interface{}{
-		"Title": fmt.Sprintf("Скважина %s", well.Name),
-		"Well":  well,
+		"Title":       fmt.Sprintf("Скважина %s", well.Name),
+		"Well":        well,
+		"SweepParams": service.SweepParams(),
+		"Today":       time.Now().Format("2006-01-02"),
+	}
+
+	method, err := calculations.ParseHydrateMethod(r.URL.Query().Get("hydrate_method"))
+	if err != nil {
+		h.renderError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if hydrate, err := h.service.AssessHydrates(well, method); err == nil {
+		data["Hydrate"] = hydrate
+	}
+	if props, err := h.service.WellGasProperties(well); err == nil {
+		data["Properties"] = props
+	}
+	if choke, err := h.service.WellChoke(well); err == nil {
+		data["Choke"] = choke
+	}
+	if lifecycle, err := h.service.Lifecycle(r.Context(), id); err == nil {
+		data["Lifecycle"] = lifecycle
+	} else {
+		h.logger.Error("failed to load well lifecycle", "id", id, "error", err)
+	}
+	if well.PadID != nil {
+		if pad, err := h.fields.GetPad(r.Context(), *well.PadID); err == nil {
+			data["Pad"] = pad
+			data["Field"], _ = h.fields.GetField(r.Context(), pad.FieldID)
+		}
 	}
 
-	h.renderTemplate(w, "wells/view.html", data)
+	h.renderTemplate(w, r, "wells/view.html", data)
 }
 
 // EditWellForm - форма редактирования скважины
 func (h *WellHandler) EditWellForm(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		h.renderError(w, "Invalid well ID", http.StatusBadRequest)
+		h.renderError(w, r, "Invalid well ID", http.StatusBadRequest)
 		return
 	}
 
 	well, err := h.service.GetWell(r.Context(), id)
 	if err != nil {
 		h.logger.Error("failed to get well for edit", "id", id, "error", err)
-		h.renderError(w, "Well not found", http.StatusNotFound)
+		h.renderError(w, r, "Well not found", http.StatusNotFound)
 		return
 	}
 
 	data := map[string]interface{}{
 		"Title": fmt.Sprintf("Редактирование %s", well.Name),
 		"Well":  well,
+		"Pads":  h.padChoices(r),
 	}
 
-	h.renderTemplate(w, "wells/edit.html", data)
+	h.renderTemplate(w, r, "wells/edit.html", data)
 }
 
 // UpdateWell - обработчик обновления скважины
 func (h *WellHandler) UpdateWell(w http.ResponseWriter, r *http.Request) {
+	if wantsJSON(r) {
+		h.UpdateWellJSON(w, r)
+		return
+	}
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		h.renderError(w, "Invalid well ID", http.StatusBadRequest)
+		h.renderError(w, r, "Invalid well ID", http.StatusBadRequest)
 		return
 	}
 
 	if err := r.ParseForm(); err != nil {
-		h.renderError(w, "Invalid form data", http.StatusBadRequest)
+		h.renderError(w, r, "Invalid form data", http.StatusBadRequest)
 		return
 	}
 
 	well := &entity.Well{
+		ID:        id,
 		Name:      r.FormValue("name"),
 		Location:  r.FormValue("location"),
 		GammaG:    parseFloat(r.FormValue("gammag")),
@@ -185,56 +358,346 @@ func (h *WellHandler) UpdateWell(w http.ResponseWriter, r *http.Request) {
 		Qmin:      parseFloat(r.FormValue("qmin")),
 		Pmax:      parseFloat(r.FormValue("pmax")),
 		Status:    r.FormValue("status"),
+		QminModel: r.FormValue("qmin_model"),
+		MuManual:  r.FormValue("mu_manual") != "",
+		PzManual:  r.FormValue("pz_manual") != "",
+		PadID:     parseOptionalID(r.FormValue("pad_id")),
+		Choke:     parseFloat(r.FormValue("choke")),
+		Pline:     parseFloat(r.FormValue("pline")),
 	}
 
 	_, err = h.service.UpdateWell(r.Context(), well)
 	if err != nil {
-		h.logger.Error("failed to update well", "id", id, "error", err)
-		h.renderError(w, "Failed to update well", http.StatusInternalServerError)
+		h.wellFormError(w, r, well, err, "update well", "id", id)
 		return
 	}
 
 	http.Redirect(w, r, fmt.Sprintf("/wells/%d", id), http.StatusSeeOther)
 }
 
+// wellFormError отвечает на ошибку формы скважины: некорректные данные
+// возвращают форму с сообщением (400), недопустимый переход - 409,
+// отсутствующая скважина - 404, прочие ошибки логируются и дают 500
+func (h *WellHandler) wellFormError(w http.ResponseWriter, r *http.Request, well *entity.Well, err error, action string, args ...interface{}) {
+	switch {
+	case errors.Is(err, service.ErrWellNotFound):
+		h.renderError(w, r, "Well not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidTransition):
+		h.renderError(w, r, err.Error(), http.StatusConflict)
+	case well != nil && (errors.Is(err, service.ErrInvalidWell) || errors.Is(err, service.ErrCalculation)):
+		title := "Новая скважина"
+		if well.ID != 0 {
+			title = fmt.Sprintf("Редактирование %s", well.Name)
+		}
+		data := map[string]interface{}{
+			"Title":    title,
+			"Well":     well,
+			"Pads":     h.padChoices(r),
+			"Statuses": entity.WellStatuses,
+			"Error":    err.Error(),
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusBadRequest)
+		h.renderTemplate(w, r, "wells/edit.html", data)
+	default:
+		h.logger.Error("failed to "+action, append(args, "error", err)...)
+		h.renderError(w, r, "Failed to "+action, http.StatusInternalServerError)
+	}
+}
+
 // DeleteWell - обработчик удаления скважины
 func (h *WellHandler) DeleteWell(w http.ResponseWriter, r *http.Request) {
+	if wantsJSON(r) {
+		h.DeleteWellJSON(w, r)
+		return
+	}
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		h.renderError(w, "Invalid well ID", http.StatusBadRequest)
+		h.renderError(w, r, "Invalid well ID", http.StatusBadRequest)
 		return
 	}
 
 	if err := h.service.DeleteWell(r.Context(), id); err != nil {
-		h.logger.Error("failed to delete well", "id", id, "error", err)
-		h.renderError(w, "Failed to delete well", http.StatusInternalServerError)
+		h.wellFormError(w, r, nil, err, "delete well", "id", id)
 		return
 	}
 
 	http.Redirect(w, r, "/wells", http.StatusSeeOther)
 }
 
-// Вспомогательные методы
+// InflowPerformance - индикаторная кривая скважины в формате JSON
+func (h *WellHandler) InflowPerformance(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
 
-func (h *WellHandler) renderTemplate(w http.ResponseWriter, tmpl string, data map[string]interface{}) {
-	w.Header().Set("Content-Type", "text/html")
-	err := h.templates.ExecuteTemplate(w, "base.html", data)
+	query := r.URL.Query()
+	points, err := parsePoints(query.Get("points"))
 	if err != nil {
-		h.logger.Error("failed to render template", "template", tmpl, "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		h.respondError(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	res, err := h.service.InflowPerformance(r.Context(), id, service.IPRQuery{
+		PMin:     parseFloat(query.Get("pmin")),
+		PMax:     parseFloat(query.Get("pmax")),
+		Points:   points,
+		Drawdown: parseFloat(query.Get("drawdown")),
+	})
+	if err != nil {
+		h.respondWellError(w, err, "build IPR curve", "id", id)
+		return
+	}
+
+	h.respondJSON(w, res, http.StatusOK)
 }
 
-func (h *WellHandler) renderError(w http.ResponseWriter, message string, statusCode int) {
-	data := map[string]interface{}{
-		"Error":      message,
-		"StatusCode": statusCode,
+// NodalAnalysis - узловой анализ: кривые притока и лифта и рабочая точка (JSON).
+// Параметр pbuf (Па) позволяет рассчитать сценарий с другим буферным давлением.
+func (h *WellHandler) NodalAnalysis(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	points, err := parsePoints(query.Get("points"))
+	if err != nil {
+		h.respondError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res, err := h.service.NodalAnalysis(r.Context(), id, service.NodalQuery{
+		Pbuf:   parseFloat(query.Get("pbuf")),
+		Points: points,
+	})
+	if err != nil {
+		h.respondWellError(w, err, "run nodal analysis", "id", id)
+		return
 	}
-	w.WriteHeader(statusCode)
-	h.renderTemplate(w, "error.html", data)
+
+	h.respondJSON(w, res, http.StatusOK)
 }
 
+// ChokeAnalysis - расход газа через штуцер и подбор штуцера (JSON).
+// Параметры: d - диаметр штуцера, м; pline - давление после штуцера, Па;
+// q - целевой дебит, м³/сут. Без параметров - данные скважины.
+func (h *WellHandler) ChokeAnalysis(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	res, err := h.service.ChokeAnalysis(r.Context(), id, service.ChokeQuery{
+		Diameter: parseFloat(query.Get("d")),
+		Pline:    parseFloat(query.Get("pline")),
+		Rate:     parseFloat(query.Get("q")),
+	})
+	if err != nil {
+		h.respondWellError(w, err, "analyze choke", "id", id)
+		return
+	}
+
+	h.respondJSON(w, res, http.StatusOK)
+}
+
+// TubingSensitivity - сравнение диаметров НКТ (JSON). Параметр diameters -
+// внутренние диаметры в метрах через запятую; без него - стандартные НКТ.
+func (h *WellHandler) TubingSensitivity(w http.ResponseWriter, r *http.Request) {
+	res, status, msg := h.tubingSensitivity(r)
+	if res == nil {
+		h.respondError(w, msg, status)
+		return
+	}
+	h.respondJSON(w, res, http.StatusOK)
+}
+
+// ExportTubingSensitivity - выгрузка сравнения диаметров НКТ в Excel
+func (h *WellHandler) ExportTubingSensitivity(w http.ResponseWriter, r *http.Request) {
+	res, status, msg := h.tubingSensitivity(r)
+	if res == nil {
+		h.renderError(w, r, msg, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="well_%d_tubing.xlsx"`, res.WellID))
+	if err := exporter.WriteTables(w, tubingTable(res)); err != nil {
+		h.logger.Error("failed to export tubing sensitivity", "id", res.WellID, "error", err)
+	}
+}
+
+// tubingSensitivity выполняет расчет и возвращает HTTP-статус и текст ошибки
+func (h *WellHandler) tubingSensitivity(r *http.Request) (*service.TubingSensitivityResult, int, string) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		return nil, http.StatusBadRequest, "Invalid well ID"
+	}
+	diameters, err := parseFloatList(r.URL.Query().Get("diameters"))
+	if err != nil {
+		return nil, http.StatusBadRequest, err.Error()
+	}
+
+	res, err := h.service.TubingSensitivity(r.Context(), id, diameters)
+	if err != nil {
+		status, msg := h.wellFailure(err, "run tubing sensitivity", "id", id)
+		return nil, status, msg
+	}
+	return res, http.StatusOK, ""
+}
+
+// Sweep - варьирование одного или двух параметров скважины (JSON).
+// Параметры запроса: x, x_from, x_to, x_steps и необязательные y, y_from, y_to, y_steps;
+// значения задаются в единицах entity.Well (Па, К, м...).
+func (h *WellHandler) Sweep(w http.ResponseWriter, r *http.Request) {
+	res, status, msg := h.sweep(r)
+	if res == nil {
+		h.respondError(w, msg, status)
+		return
+	}
+	h.respondJSON(w, res, http.StatusOK)
+}
+
+// ExportSweepCSV - выгрузка результатов варьирования в CSV
+func (h *WellHandler) ExportSweepCSV(w http.ResponseWriter, r *http.Request) {
+	res, status, msg := h.sweep(r)
+	if res == nil {
+		h.renderError(w, r, msg, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="well_%d_sweep.csv"`, res.WellID))
+	if err := exporter.WriteCSV(w, sweepTable(res)); err != nil {
+		h.logger.Error("failed to export sweep", "id", res.WellID, "error", err)
+	}
+}
+
+// ExportSweepExcel - выгрузка результатов варьирования в Excel;
+// для двумерной сетки добавляются листы-матрицы по основным показателям
+func (h *WellHandler) ExportSweepExcel(w http.ResponseWriter, r *http.Request) {
+	res, status, msg := h.sweep(r)
+	if res == nil {
+		h.renderError(w, r, msg, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="well_%d_sweep.xlsx"`, res.WellID))
+	tables := append([]exporter.Table{sweepTable(res)}, sweepMatrixTables(res)...)
+	if err := exporter.WriteTables(w, tables...); err != nil {
+		h.logger.Error("failed to export sweep", "id", res.WellID, "error", err)
+	}
+}
+
+// sweep выполняет расчет и возвращает HTTP-статус и текст ошибки
+func (h *WellHandler) sweep(r *http.Request) (*service.SweepResult, int, string) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		return nil, http.StatusBadRequest, "Invalid well ID"
+	}
+
+	query := r.URL.Query()
+	q := service.SweepQuery{X: sweepAxis(query, "x")}
+	if q.X.Param == "" {
+		return nil, http.StatusBadRequest, "Sweep parameter x is required"
+	}
+	if y := sweepAxis(query, "y"); y.Param != "" {
+		q.Y = &y
+	}
+
+	res, err := h.service.Sweep(r.Context(), id, q)
+	if errors.Is(err, service.ErrInvalidSweep) {
+		return nil, http.StatusBadRequest, err.Error()
+	}
+	if err != nil {
+		status, msg := h.wellFailure(err, "run sweep", "id", id)
+		return nil, status, msg
+	}
+	return res, http.StatusOK, ""
+}
+
+// sweepAxis читает ось сетки из параметров запроса с префиксом prefix
+func sweepAxis(query url.Values, prefix string) service.SweepAxis {
+	steps, _ := strconv.Atoi(query.Get(prefix + "_steps"))
+	return service.SweepAxis{
+		Param: query.Get(prefix),
+		From:  parseFloat(query.Get(prefix + "_from")),
+		To:    parseFloat(query.Get(prefix + "_to")),
+		Steps: steps,
+	}
+}
+
+// GasProperties - свойства газа на устье и забое в формате JSON
+func (h *WellHandler) GasProperties(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	well, err := h.service.GetWell(r.Context(), id)
+	if err != nil {
+		h.respondWellError(w, err, "load well", "id", id)
+		return
+	}
+
+	props, err := h.service.WellGasProperties(well)
+	if err != nil {
+		h.respondWellError(w, err, "calculate gas properties", "id", id)
+		return
+	}
+
+	h.respondJSON(w, props, http.StatusOK)
+}
+
+// Вспомогательные методы
+
 func parseFloat(s string) float64 {
 	f, _ := strconv.ParseFloat(s, 64)
 	return f
 }
+
+// parseOptionalID разбирает необязательный ID; пустое или неверное значение - nil
+func parseOptionalID(s string) *int {
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		return nil
+	}
+	return &id
+}
+
+// padChoices - кусты для выбора в форме скважины
+func (h *WellHandler) padChoices(r *http.Request) []service.PadChoice {
+	if h.fields == nil {
+		return nil
+	}
+	pads, err := h.fields.PadChoices(r.Context())
+	if err != nil {
+		h.logger.Error("failed to load pads", "error", err)
+	}
+	return pads
+}
+
+// parseFloatList разбирает список чисел через запятую, например "0.0503,0.062"
+func parseFloatList(s string) ([]float64, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	values := make([]float64, 0, len(parts))
+	for _, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p)
+		}
+		values = append(values, f)
+	}
+	return values, nil
+}
@@ -1,40 +1,408 @@
-// handler/well_handler_test.go
+// internal/handler/well_handler_test.go
 package handler_test
 
-/*import (
+import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"gas_wells/internal/entity"
 	"gas_wells/internal/handler"
-
-	//"gas_wells/internal/service/mocks"
-	"log/slog"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository/repotest"
+	"gas_wells/internal/service"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
-	"github.com/stretchr/testify/mock"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
 )
 
-func TestCreateWell(t *testing.T) {
-	mockService := new(mocks.WellService)
-	handler := handler.NewWellHandler(mockService, slog.Default())
+func newAPIRouter() http.Handler {
+	svc := service.NewWellService(&repotest.WellRepo{Wells: map[int]*entity.Well{}}, nil, logger.New("test"))
+	h := handler.NewWellHandler(svc, nil, nil, logger.New("test"))
+	r := chi.NewRouter()
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(handler.JSONOnly)
+		h.RegisterAPIRoutes(r)
+	})
+	return r
+}
 
-	well := &entity.Well{
-		Name:        "Test Well",
-		Pressure:    100,
-		Temperature: 20,
+func apiRequest(t *testing.T, router http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
 
-	mockService.On("CreateWell", mock.Anything, well).Return(well, nil)
+func testWell() *entity.Well {
+	return &entity.Well{
+		Name: "101", GammaG: 0.6, Temp: 360, TempUst: 290, Depth: 3000, Pbuf: 8e6,
+		Ppl: 20e6, Q: 100e3, Roughness: 2e-5, Diameter: 0.062, A: 0.6, B: 0.0028, Mu: 0.012,
+	}
+}
 
-	body, _ := json.Marshal(well)
-	req := httptest.NewRequest("POST", "/api/v1/wells", bytes.NewReader(body))
-	rec := httptest.NewRecorder()
+func TestWellAPI(t *testing.T) {
+	router := newAPIRouter()
+
+	rec := apiRequest(t, router, http.MethodPost, "/api/v1/wells", testWell())
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "/api/v1/wells/1", rec.Header().Get("Location"))
+	var created entity.Well
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&created))
+	assert.Equal(t, 1, created.ID)
+	assert.Greater(t, created.Pz, created.Pbuf)
+
+	rec = apiRequest(t, router, http.MethodGet, "/api/v1/wells/1", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// Частичное обновление меняет только переданные поля
+	rec = apiRequest(t, router, http.MethodPatch, "/api/v1/wells/1", map[string]interface{}{"name": "101-бис"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var patched entity.Well
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&patched))
+	assert.Equal(t, "101-бис", patched.Name)
+	assert.Equal(t, created.Depth, patched.Depth)
+
+	rec = apiRequest(t, router, http.MethodGet, "/api/v1/wells?limit=10", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list struct {
+		Wells []entity.Well `json:"wells"`
+		Limit int           `json:"limit"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&list))
+	assert.Len(t, list.Wells, 1)
+	assert.Equal(t, 10, list.Limit)
+
+	rec = apiRequest(t, router, http.MethodDelete, "/api/v1/wells/1", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = apiRequest(t, router, http.MethodDelete, "/api/v1/wells/1", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = apiRequest(t, router, http.MethodGet, "/api/v1/wells/1", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = apiRequest(t, router, http.MethodPut, "/api/v1/wells/1", testWell())
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = apiRequest(t, router, http.MethodGet, "/api/v1/wells/abc", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestWellAPIErrors(t *testing.T) {
+	router := newAPIRouter()
+
+	invalid := testWell()
+	invalid.Name = ""
+	invalid.GammaG = 3
+	invalid.Depth = -1
+	rec := apiRequest(t, router, http.MethodPost, "/api/v1/wells", invalid)
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	var body struct {
+		Error  string            `json:"error"`
+		Fields map[string]string `json:"fields"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, "validation failed", body.Error)
+	assert.Contains(t, body.Fields, "name")
+	assert.Contains(t, body.Fields, "gamma")
+	assert.Contains(t, body.Fields, "depth")
+
+	rec = apiRequest(t, router, http.MethodGet, "/api/v1/wells?limit=0", nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/wells", strings.NewReader("{"))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/wells", strings.NewReader("name=101"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/wells", nil)
+	req.Header.Set("Accept", "text/html")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+}
+
+func TestCurvePointsLimit(t *testing.T) {
+	repo := &repotest.WellRepo{Wells: map[int]*entity.Well{}}
+	svc := service.NewWellService(repo, nil, logger.New("test"))
+	_, err := svc.CreateWell(context.Background(), testWell())
+	require.NoError(t, err)
+	h := handler.NewWellHandler(svc, nil, nil, logger.New("test"))
+	r := chi.NewRouter()
+	r.Get("/wells/{id}/ipr", h.InflowPerformance)
+	r.Get("/wells/{id}/nodal", h.NodalAnalysis)
+
+	for _, path := range []string{"/wells/1/ipr", "/wells/1/nodal"} {
+		rec := apiRequest(t, r, http.MethodGet, path+"?points=20", nil)
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		for _, points := range []string{"501", "-1", "many"} {
+			rec = apiRequest(t, r, http.MethodGet, path+"?points="+points, nil)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, "%s?points=%s", path, points)
+		}
+	}
+}
+
+func TestWellAPIServiceErrors(t *testing.T) {
+	repo := &repotest.WellRepo{Wells: map[int]*entity.Well{}}
+	svc := service.NewWellService(repo, nil, logger.New("test"))
+	h := handler.NewWellHandler(svc, nil, nil, logger.New("test"))
+	router := chi.NewRouter()
+	router.Route("/api/v1", h.RegisterAPIRoutes)
+
+	rec := apiRequest(t, router, http.MethodPost, "/api/v1/wells", testWell())
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var body struct {
+		Error  string            `json:"error"`
+		Fields map[string]string `json:"fields"`
+	}
+	// Смена состояния без перехода - ошибка поля status
+	changed := testWell()
+	changed.Status = entity.StatusShutIn
+	rec = apiRequest(t, router, http.MethodPut, "/api/v1/wells/1", changed)
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Contains(t, body.Fields, "status")
+
+	// Ошибка БД не раскрывается клиенту
+	repo.Err = errors.New(`pq: duplicate key value violates unique constraint "wells_name_key"`)
+	rec = apiRequest(t, router, http.MethodPost, "/api/v1/wells", testWell())
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "wells_name_key")
+	rec = apiRequest(t, router, http.MethodPatch, "/api/v1/wells/1", map[string]float64{"depth": 3100})
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "constraint")
+}
+
+func TestWellCalculationErrors(t *testing.T) {
+	repo := &repotest.WellRepo{Wells: map[int]*entity.Well{}}
+	svc := service.NewWellService(repo, nil, logger.New("test"))
+	noInflow := testWell()
+	noInflow.A, noInflow.B = 0, 0
+	_, err := svc.CreateWell(context.Background(), noInflow)
+	require.NoError(t, err)
+	h := handler.NewWellHandler(svc, nil, nil, logger.New("test"))
+	r := chi.NewRouter()
+	r.Get("/wells/{id}/ipr", h.InflowPerformance)
+	r.Get("/wells/{id}/choke", h.ChokeAnalysis)
+	r.Get("/wells/{id}/nodal", h.NodalAnalysis)
+	r.Get("/wells/{id}/tubing", h.TubingSensitivity)
+	r.Get("/wells/{id}/tubing.xlsx", h.ExportTubingSensitivity)
+	r.Get("/wells/{id}/sweep", h.Sweep)
+	r.Get("/wells/{id}/sweep.csv", h.ExportSweepCSV)
+	r.Get("/wells/{id}/properties", h.GasProperties)
+	r.Post("/wells/{id}/revisions/{revision}/restore", h.RestoreRevision)
+
+	var body struct {
+		Fields map[string]string `json:"fields"`
+	}
+	for _, path := range []string{"/wells/1/ipr", "/wells/1/nodal"} {
+		rec := apiRequest(t, r, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code, path)
+		body.Fields = nil
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		assert.Contains(t, body.Fields, "calculation", path)
+	}
+
+	// Нет давления в шлейфе ни у скважины, ни в запросе
+	rec := apiRequest(t, r, http.MethodGet, "/wells/1/choke?d=0.01", nil)
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	body.Fields = nil
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Contains(t, body.Fields, "well")
+
+	rec = apiRequest(t, r, http.MethodGet, "/wells/2/ipr", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = apiRequest(t, r, http.MethodGet, "/wells/2/choke", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = apiRequest(t, r, http.MethodGet, "/wells/2/nodal", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = apiRequest(t, r, http.MethodGet, "/wells/2/tubing", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = apiRequest(t, r, http.MethodGet, "/wells/2/sweep?x=pbuf", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = apiRequest(t, r, http.MethodGet, "/wells/2/properties", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = apiRequest(t, r, http.MethodGet, "/wells/1/sweep?x=pz", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Ошибка БД не раскрывается клиенту
+	repo.ReadErr = errors.New("pgx: connection refused")
+	for _, path := range []string{
+		"/wells/1/nodal", "/wells/1/tubing", "/wells/1/tubing.xlsx", "/wells/1/sweep?x=pbuf", "/wells/1/sweep.csv?x=pbuf",
+		"/wells/1/properties",
+	} {
+		rec = apiRequest(t, r, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusInternalServerError, rec.Code, path)
+		assert.NotContains(t, rec.Body.String(), "pgx", path)
+	}
+	rec = apiRequest(t, r, http.MethodPost, "/wells/1/revisions/1/restore", nil)
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "pgx")
+}
+
+// newCalcRouter - маршруты расчетов по скважине testWell с ID 1
+func newCalcRouter(t *testing.T) http.Handler {
+	t.Helper()
+	svc := service.NewWellService(&repotest.WellRepo{Wells: map[int]*entity.Well{}}, nil, logger.New("test"))
+	_, err := svc.CreateWell(context.Background(), testWell())
+	require.NoError(t, err)
+	h := handler.NewWellHandler(svc, nil, nil, logger.New("test"))
+	r := chi.NewRouter()
+	h.RegisterRoutes(r)
+	return r
+}
+
+func TestExportTubingSensitivity(t *testing.T) {
+	rec := apiRequest(t, newCalcRouter(t), http.MethodGet, "/wells/1/tubing.xlsx?diameters=0.0503,0.062", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	f, err := excelize.OpenReader(rec.Body)
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err := f.GetRows("Диаметр НКТ")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "Диаметр НКТ, мм", rows[0][0])
+	assert.Equal(t, "62", rows[2][0], "диаметр в мм")
+}
+
+func TestExportSweep(t *testing.T) {
+	router := newCalcRouter(t)
+	grid := "x=pbuf&x_from=6e6&x_to=10e6&x_steps=3&y=diameter&y_from=0.0503&y_to=0.076&y_steps=2"
+
+	rec := apiRequest(t, router, http.MethodGet, "/wells/1/sweep.xlsx?"+grid, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	f, err := excelize.OpenReader(rec.Body)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"Результаты", "Q раб.", "Pz", "Qmin"}, f.GetSheetList())
+	rows, err := f.GetRows("Результаты")
+	require.NoError(t, err)
+	assert.Len(t, rows, 1+6, "строка на узел сетки")
+	assert.Equal(t, []string{"pbuf", "diameter"}, rows[0][:2])
+	matrix, err := f.GetRows("Pz")
+	require.NoError(t, err)
+	require.Len(t, matrix, 1+2, "строка на значение y")
+	assert.Len(t, matrix[0], 1+3, "столбец на значение x")
+
+	rec = apiRequest(t, router, http.MethodGet, "/wells/1/sweep.csv?x=pbuf&x_from=6e6&x_to=10e6&x_steps=3", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	assert.Len(t, lines, 1+3)
+}
+
+func TestWellListQuery(t *testing.T) {
+	repo := &repotest.WellRepo{Wells: map[int]*entity.Well{}}
+	svc := service.NewWellService(repo, nil, logger.New("test"))
+	h := handler.NewWellHandler(svc, nil, nil, logger.New("test"))
+	router := chi.NewRouter()
+	router.Route("/api/v1", func(r chi.Router) {
+		r.Use(handler.JSONOnly)
+		h.RegisterAPIRoutes(r)
+	})
+
+	after := entity.WellCursor{Sort: "q", Value: "150000", ID: 7}.Encode()
+	rec := apiRequest(t, router, http.MethodGet, "/api/v1/wells?search=%D0%BA%D1%83%D1%81%D1%82&status=producing"+
+		"&q_min=1e5&pbuf_max=12e6&sort=q&order=desc&limit=20&after="+after, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	q := repo.Query
+	assert.Equal(t, "куст", q.Search)
+	assert.Equal(t, entity.StatusProducing, q.Status)
+	require.NotNil(t, q.Q.Min)
+	assert.Equal(t, 1e5, *q.Q.Min)
+	assert.Nil(t, q.Q.Max)
+	require.NotNil(t, q.Pbuf.Max)
+	assert.Equal(t, 12e6, *q.Pbuf.Max)
+	assert.Equal(t, "q", q.Sort)
+	assert.True(t, q.Desc)
+	assert.Equal(t, 20, q.Limit)
+	assert.Equal(t, &entity.WellCursor{Sort: "q", Value: "150000", ID: 7}, q.After)
+
+	var body struct {
+		Fields map[string]string `json:"fields"`
+	}
+	rec = apiRequest(t, router, http.MethodGet, "/api/v1/wells?status=active&sort=color&order=up&depth_min=deep", nil)
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	for _, field := range []string{"status", "sort", "order", "depth_min"} {
+		assert.Contains(t, body.Fields, field)
+	}
+
+	// Курсор другой сортировки или с измененным значением не доходит до БД
+	for _, cursor := range []string{
+		entity.WellCursor{Sort: "name", Value: "101", ID: 7}.Encode(),
+		entity.WellCursor{Sort: "q", Value: "много", ID: 7}.Encode(),
+		entity.WellCursor{Sort: "q", Value: "0x10", ID: 7}.Encode(),
+		"not-a-cursor",
+	} {
+		rec = apiRequest(t, router, http.MethodGet, "/api/v1/wells?sort=q&before="+cursor, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	}
+	for _, sort := range []string{"created", "status_since"} {
+		cursor := entity.WellCursor{Sort: sort, Value: "2024-13-45", ID: 7}.Encode()
+		rec = apiRequest(t, router, http.MethodGet, "/api/v1/wells?sort="+sort+"&after="+cursor, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, sort)
+	}
+}
+
+func TestWellFormErrors(t *testing.T) {
+	templates, err := handler.LoadTemplates("../../cmd/server/web/templates")
+	require.NoError(t, err)
+	repo := &repotest.WellRepo{Wells: map[int]*entity.Well{}}
+	svc := service.NewWellService(repo, nil, logger.New("test"))
+	_, err = svc.CreateWell(context.Background(), testWell())
+	require.NoError(t, err)
+	h := handler.NewWellHandler(svc, nil, templates, logger.New("test"))
+	r := chi.NewRouter()
+	r.Post("/wells", h.CreateWell)
+	r.Post("/wells/{id}", h.UpdateWell)
+	r.Post("/wells/{id}/delete", h.DeleteWell)
+
+	post := func(path, form string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Некорректные данные возвращают форму с сообщением
+	rec := post("/wells", "name=102&gammag=-1")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "alert-danger")
+	assert.Contains(t, rec.Body.String(), `value="102"`)
 
-	handler.CreateWell(rec, req)
+	rec = post("/wells/1", "name=101&pbuf=8000000&status=abandoned")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = post("/wells/2", "name=102&gammag=0.6")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = post("/wells/2/delete", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
 
-	require.Equal(t, http.StatusCreated, rec.Code)
-	mockService.AssertExpectations(t)
-}*/
+	// Ошибка БД не раскрывается клиенту
+	repo.ReadErr = errors.New("pgx: connection refused")
+	rec = post("/wells/1", "name=101&pbuf=8000000")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "pgx")
+}
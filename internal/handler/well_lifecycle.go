@@ -0,0 +1,92 @@
+// internal/handler/well_lifecycle.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/service"
+	"net/http"
+)
+
+// statusRequest - тело запроса перехода скважины в другое состояние
+type statusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+	Date   string `json:"date"` // ГГГГ-ММ-ДД или RFC 3339
+}
+
+// Lifecycle - состояние скважины, допустимые переходы, история
+// и время в каждом состоянии (JSON)
+func (h *WellHandler) Lifecycle(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	lifecycle, err := h.service.Lifecycle(r.Context(), id)
+	if errors.Is(err, service.ErrWellNotFound) {
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to load well lifecycle", "id", id, "error", err)
+		h.respondError(w, "Failed to load well lifecycle", http.StatusInternalServerError)
+		return
+	}
+	h.respondJSON(w, lifecycle, http.StatusOK)
+}
+
+// ChangeStatus - переход скважины в другое состояние с причиной и датой.
+// JSON-клиент получает скважину, браузер переходит на ее страницу.
+func (h *WellHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondFailure(w, r, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	var req statusRequest
+	if wantsJSON(r) {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			h.renderError(w, r, "Invalid form data", http.StatusBadRequest)
+			return
+		}
+		req = statusRequest{Status: r.FormValue("status"), Reason: r.FormValue("reason"), Date: r.FormValue("date")}
+	}
+	date, err := parseTime(req.Date)
+	if err != nil {
+		h.respondFailure(w, r, "Invalid date: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	well, err := h.service.ChangeStatus(r.Context(), id, &entity.StatusChange{To: req.Status, Reason: req.Reason, Date: date})
+	switch {
+	case errors.Is(err, service.ErrWellNotFound):
+		h.respondFailure(w, r, "Well not found", http.StatusNotFound)
+		return
+	case errors.Is(err, service.ErrInvalidTransition):
+		h.respondFailure(w, r, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, service.ErrInvalidWell):
+		h.respondFailure(w, r, err.Error(), http.StatusUnprocessableEntity)
+		return
+	case err != nil:
+		h.logger.Error("failed to change well status", "id", id, "error", err)
+		h.respondFailure(w, r, "Failed to change well status", http.StatusInternalServerError)
+		return
+	}
+
+	if wantsJSON(r) {
+		h.respondJSON(w, well, http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/wells/%d", id), http.StatusSeeOther)
+}
@@ -0,0 +1,199 @@
+// internal/handler/well_revisions.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"gas_wells/internal/service"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Revisions - версии скважины. JSON-клиент получает список версий, браузер -
+// страницу версий; параметры at (состояние на момент) и from, to (номера
+// сравниваемых версий) дополняют страницу.
+func (h *WellHandler) Revisions(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondFailure(w, r, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	revisions, err := h.service.WellRevisions(r.Context(), id)
+	if err != nil {
+		h.revisionFailure(w, r, id, err)
+		return
+	}
+	if wantsJSON(r) {
+		h.respondJSON(w, revisions, http.StatusOK)
+		return
+	}
+
+	well, err := h.service.GetWell(r.Context(), id)
+	if err != nil {
+		h.revisionFailure(w, r, id, err)
+		return
+	}
+	data := map[string]interface{}{
+		"Title":     fmt.Sprintf("Версии скважины %s", well.Name),
+		"Well":      well,
+		"Revisions": revisions,
+	}
+
+	query := r.URL.Query()
+	if at := query.Get("at"); at != "" {
+		data["At"] = at
+		moment, err := parseAsOf(at)
+		if err == nil {
+			data["AsOf"], err = h.service.WellAsOf(r.Context(), id, moment)
+		}
+		if err != nil {
+			data["AsOfError"] = err.Error()
+		}
+	}
+	if query.Get("from") != "" || query.Get("to") != "" {
+		from, to, err := revisionPair(r)
+		if err == nil {
+			data["Diff"], err = h.service.CompareRevisions(r.Context(), id, from, to)
+		}
+		if err != nil {
+			data["DiffError"] = err.Error()
+		}
+	}
+	h.renderTemplate(w, r, "wells/revisions.html", data)
+}
+
+// WellAsOf - параметры скважины на момент at (JSON). Дата без времени
+// означает состояние на конец дня.
+func (h *WellHandler) WellAsOf(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+	at, err := parseAsOf(r.URL.Query().Get("at"))
+	if err != nil {
+		h.respondError(w, "Invalid at: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	well, err := h.service.WellAsOf(r.Context(), id, at)
+	if err != nil {
+		h.revisionFailure(w, r, id, err)
+		return
+	}
+	h.respondJSON(w, well, http.StatusOK)
+}
+
+// GetRevision - версия скважины (JSON)
+func (h *WellHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+	revision, err := urlParamInt(r, "revision")
+	if err != nil {
+		h.respondError(w, "Invalid revision", http.StatusBadRequest)
+		return
+	}
+
+	rev, err := h.service.WellRevision(r.Context(), id, revision)
+	if err != nil {
+		h.revisionFailure(w, r, id, err)
+		return
+	}
+	h.respondJSON(w, rev, http.StatusOK)
+}
+
+// RevisionDiff - различия версий from и to скважины (JSON)
+func (h *WellHandler) RevisionDiff(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+	from, to, err := revisionPair(r)
+	if err != nil {
+		h.respondError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.service.CompareRevisions(r.Context(), id, from, to)
+	if err != nil {
+		h.revisionFailure(w, r, id, err)
+		return
+	}
+	h.respondJSON(w, diff, http.StatusOK)
+}
+
+// RestoreRevision - восстановление параметров скважины из версии.
+// JSON-клиент получает скважину, браузер переходит на ее страницу.
+func (h *WellHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondFailure(w, r, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+	revision, err := urlParamInt(r, "revision")
+	if err != nil {
+		h.respondFailure(w, r, "Invalid revision", http.StatusBadRequest)
+		return
+	}
+
+	well, err := h.service.RestoreRevision(r.Context(), id, revision)
+	if err != nil {
+		h.revisionFailure(w, r, id, err)
+		return
+	}
+	if wantsJSON(r) {
+		h.respondJSON(w, well, http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/wells/%d", id), http.StatusSeeOther)
+}
+
+// revisionFailure отвечает на ошибку сервиса версий: восстановленные данные
+// могут не пройти проверку (422), прочие ошибки клиенту не раскрываются
+func (h *WellHandler) revisionFailure(w http.ResponseWriter, r *http.Request, id int, err error) {
+	switch {
+	case errors.Is(err, service.ErrWellNotFound):
+		h.respondFailure(w, r, "Well not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrRevisionNotFound):
+		h.respondFailure(w, r, err.Error(), http.StatusNotFound)
+	case wellInputErrors(err) != nil:
+		h.respondFailure(w, r, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		h.logger.Error("failed to process well revisions", "id", id, "error", err)
+		h.respondFailure(w, r, "Failed to process well revisions", http.StatusInternalServerError)
+	}
+}
+
+// parseAsOf разбирает момент времени для просмотра версии скважины.
+// Дата без времени означает конец дня (точность PostgreSQL - микросекунда).
+func parseAsOf(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, errors.New("time is required")
+	}
+	at, err := parseTime(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(s) == len("2006-01-02") {
+		at = at.AddDate(0, 0, 1).Add(-time.Microsecond)
+	}
+	return at, nil
+}
+
+// revisionPair - номера сравниваемых версий из параметров from и to
+func revisionPair(r *http.Request) (from, to int, err error) {
+	query := r.URL.Query()
+	if from, err = strconv.Atoi(query.Get("from")); err != nil {
+		return 0, 0, fmt.Errorf("invalid from revision %q", query.Get("from"))
+	}
+	if to, err = strconv.Atoi(query.Get("to")); err != nil {
+		return 0, 0, fmt.Errorf("invalid to revision %q", query.Get("to"))
+	}
+	return from, to, nil
+}
@@ -0,0 +1,91 @@
+// internal/handler/well_tables.go
+package handler
+
+import (
+	"fmt"
+	"gas_wells/internal/pkg/exporter"
+	"gas_wells/internal/service"
+)
+
+// tubingTable - сравнение диаметров НКТ для выгрузки в Excel, в промысловых единицах
+func tubingTable(r *service.TubingSensitivityResult) exporter.Table {
+	t := exporter.Table{
+		Sheet: "Диаметр НКТ",
+		Headers: []string{
+			"Диаметр НКТ, мм", "Дебит, тыс. м³/сут", "Pз, МПа",
+			"Потери на трение, МПа", "Критический дебит, тыс. м³/сут", "Вынос жидкости", "Ошибка",
+		},
+	}
+	for _, c := range r.Cases {
+		unloading := "нет"
+		if c.AboveCritical {
+			unloading = "да"
+		}
+		t.Rows = append(t.Rows, []interface{}{
+			c.Diameter * 1e3, c.Rate / 1e3, c.Pwf / 1e6,
+			c.Friction / 1e6, c.CriticalRate / 1e3, unloading, c.Error,
+		})
+	}
+	return t
+}
+
+// sweepTable - результат варьирования в виде плоской таблицы: строка на узел сетки
+func sweepTable(r *service.SweepResult) exporter.Table {
+	headers := []string{r.X.Param}
+	if r.Y != nil {
+		headers = append(headers, r.Y.Param)
+	}
+	headers = append(headers,
+		"Pz, Па", "Mu, мПа·с", "Qmin, м³/сут", "Самозадавливание",
+		"Фонтанирует", "Q раб., м³/сут", "Pз раб., Па", "Ошибка")
+
+	t := exporter.Table{Sheet: "Результаты", Headers: headers}
+	for _, row := range r.Cells {
+		for _, c := range row {
+			values := []interface{}{c.X}
+			if r.Y != nil {
+				values = append(values, c.Y)
+			}
+			values = append(values,
+				c.Pz, c.Mu, c.Qmin, c.LiquidLoading,
+				c.Flowing, c.Rate, c.Pwf, c.Error)
+			t.Rows = append(t.Rows, values)
+		}
+	}
+	return t
+}
+
+// sweepMatrixTables - двумерный результат варьирования в виде матриц
+// по показателям: строки - значения Y, столбцы - значения X
+func sweepMatrixTables(r *service.SweepResult) []exporter.Table {
+	if r.Y == nil {
+		return nil
+	}
+	outputs := []struct {
+		sheet string
+		value func(service.SweepCell) float64
+	}{
+		{"Q раб.", func(c service.SweepCell) float64 { return c.Rate }},
+		{"Pz", func(c service.SweepCell) float64 { return c.Pz }},
+		{"Qmin", func(c service.SweepCell) float64 { return c.Qmin }},
+	}
+
+	headers := []string{r.Y.Param + " \\ " + r.X.Param}
+	for _, x := range r.XValues {
+		headers = append(headers, fmt.Sprint(x))
+	}
+
+	tables := make([]exporter.Table, 0, len(outputs))
+	for _, out := range outputs {
+		t := exporter.Table{Sheet: out.sheet, Headers: headers}
+		for i, row := range r.Cells {
+			values := []interface{}{r.YValues[i]}
+			for _, c := range row {
+				values = append(values, out.value(c))
+			}
+			t.Rows = append(t.Rows, values)
+		}
+		tables = append(tables, t)
+	}
+	return tables
+}
@@ -0,0 +1,167 @@
+// internal/handler/welltest_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"net/http"
+)
+
+type WellTestHandler struct {
+	baseHandler
+	service *service.WellTestService
+}
+
+func NewWellTestHandler(service *service.WellTestService, templates Templates, log logger.Logger) *WellTestHandler {
+	return &WellTestHandler{
+		baseHandler: newBaseHandler(templates, log),
+		service:     service,
+	}
+}
+
+// ListTests - исследования скважины с найденными коэффициентами (JSON)
+func (h *WellTestHandler) ListTests(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	tests, err := h.service.ListTests(r.Context(), wellID)
+	if errors.Is(err, service.ErrWellNotFound) {
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to list well tests", "well_id", wellID, "error", err)
+		h.respondError(w, "Failed to load well tests", http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, tests, http.StatusOK)
+}
+
+// CreateTest - добавление исследования (JSON)
+func (h *WellTestHandler) CreateTest(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+
+	var test entity.WellTest
+	if err := json.NewDecoder(r.Body).Decode(&test); err != nil {
+		h.respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	test.WellID = wellID
+
+	created, err := h.service.CreateTest(r.Context(), &test)
+	if errors.Is(err, service.ErrWellNotFound) {
+		h.respondError(w, "Well not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidWellTest) {
+		h.respondError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to create well test", "well_id", wellID, "error", err)
+		h.respondError(w, "Failed to create well test", http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, created, http.StatusCreated)
+}
+
+// GetTest - исследование с результатом обработки (JSON)
+func (h *WellTestHandler) GetTest(w http.ResponseWriter, r *http.Request) {
+	wellID, testID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetTest(r.Context(), testID)
+	if errors.Is(err, service.ErrWellTestNotFound) || (err == nil && res.WellID != wellID) {
+		h.respondError(w, "Well test not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get well test", "id", testID, "error", err)
+		h.respondError(w, "Failed to load well test", http.StatusInternalServerError)
+		return
+	}
+
+	h.respondJSON(w, res, http.StatusOK)
+}
+
+// DeleteTest - удаление исследования
+func (h *WellTestHandler) DeleteTest(w http.ResponseWriter, r *http.Request) {
+	wellID, testID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.service.DeleteTest(r.Context(), wellID, testID)
+	if errors.Is(err, service.ErrWellTestNotFound) {
+		h.respondError(w, "Well test not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to delete well test", "id", testID, "error", err)
+		h.respondError(w, "Failed to delete well test", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ApplyFit - перенос найденных A и B в карточку скважины (форма на странице скважины)
+func (h *WellTestHandler) ApplyFit(w http.ResponseWriter, r *http.Request) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.renderError(w, r, "Invalid well ID", http.StatusBadRequest)
+		return
+	}
+	testID, err := urlParamInt(r, "testID")
+	if err != nil {
+		h.renderError(w, r, "Invalid well test ID", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.service.ApplyFit(r.Context(), wellID, testID)
+	if errors.Is(err, service.ErrWellNotFound) || errors.Is(err, service.ErrWellTestNotFound) {
+		h.renderError(w, r, "Well test not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidWellTest) || errors.Is(err, service.ErrCalculation) ||
+		errors.Is(err, service.ErrInvalidWell) {
+		h.renderError(w, r, "Failed to apply coefficients: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to apply well test fit", "well_id", wellID, "test_id", testID, "error", err)
+		h.renderError(w, r, "Failed to apply coefficients", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/wells/%d", wellID), http.StatusSeeOther)
+}
+
+func (h *WellTestHandler) parseIDs(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	wellID, err := urlParamInt(r, "id")
+	if err != nil {
+		h.respondError(w, "Invalid well ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	testID, err := urlParamInt(r, "testID")
+	if err != nil {
+		h.respondError(w, "Invalid well test ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return wellID, testID, true
+}
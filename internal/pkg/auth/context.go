@@ -0,0 +1,20 @@
+// internal/pkg/auth/context.go
+package auth
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+)
+
+type contextKey struct{}
+
+// WithUser возвращает контекст с текущим пользователем
+func WithUser(ctx context.Context, user *entity.User) context.Context {
+	return context.WithValue(ctx, contextKey{}, user)
+}
+
+// UserFromContext возвращает пользователя запроса или nil
+func UserFromContext(ctx context.Context) *entity.User {
+	user, _ := ctx.Value(contextKey{}).(*entity.User)
+	return user
+}
@@ -0,0 +1,46 @@
+// internal/pkg/auth/jwt_test.go
+package auth_test
+
+import (
+	"gas_wells/internal/pkg/auth"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignParse(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	claims := auth.NewClaims(7, "admin", auth.TokenAccess, now, 15*time.Minute)
+
+	token, err := auth.Sign(claims, secret)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(token, "."))
+
+	parsed, err := auth.Parse(token, secret, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, claims, *parsed)
+	assert.Equal(t, now.Add(15*time.Minute), parsed.Expires().UTC())
+
+	_, err = auth.Parse(token, secret, now.Add(15*time.Minute))
+	assert.ErrorIs(t, err, auth.ErrTokenExpired)
+	_, err = auth.Parse(token, []byte("other"), now)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// Подмена полезной нагрузки нарушает подпись
+	forged := claims
+	forged.Role = "root"
+	other, err := auth.Sign(forged, []byte("other"))
+	require.NoError(t, err)
+	parts, otherParts := strings.Split(token, "."), strings.Split(other, ".")
+	_, err = auth.Parse(parts[0]+"."+otherParts[1]+"."+parts[2], secret, now)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	_, err = auth.Parse("abc", secret, now)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	assert.NotEqual(t, claims.ID, auth.NewClaims(7, "admin", auth.TokenAccess, now, time.Minute).ID)
+}
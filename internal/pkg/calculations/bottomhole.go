@@ -0,0 +1,228 @@
+package calculations
+
+import (
+	"errors"
+	"gas_wells/internal/entity"
+	"math"
+)
+
+// internal/pkg/calculations/bottomhole.go
+
+// Физические константы и стандартные условия (ГОСТ 2939-63)
+const (
+	gravity     = 9.80665  // м/с²
+	gasConstant = 8.314462 // Дж/(моль·К)
+	airMolar    = 0.028964 // кг/моль
+	pStandard   = 101325.0 // Па
+	tStandard   = 293.15   // К
+	secPerDay   = 86400.0  // с
+	defaultStep = 100      // число шагов по глубине
+)
+
+// Wellbore - геометрия и термобарические условия ствола скважины
+type Wellbore struct {
+	Depth      float64 // Глубина, м
+	Diameter   float64 // Внутренний диаметр НКТ, м
+	Roughness  float64 // Абсолютная шероховатость, м
+	TempHead   float64 // Температура на устье, К
+	TempBottom float64 // Температура на забое, К
+	GammaG     float64 // Относительная плотность газа
+	Mu         float64 // Вязкость газа, мПа·с (0 - квадратичный закон трения)
+}
+
+// WellboreFromWell собирает описание ствола из данных скважины
+func WellboreFromWell(w entity.Well) Wellbore {
+	return Wellbore{
+		Depth:      w.Depth,
+		Diameter:   w.Diameter,
+		Roughness:  w.Roughness,
+		TempHead:   w.TempUst,
+		TempBottom: w.Temp,
+		GammaG:     w.GammaG,
+		Mu:         w.Mu,
+	}
+}
+
+// tempAt - температура на глубине h при линейном профиле
+func (wb Wellbore) tempAt(h float64) float64 {
+	if wb.Depth <= 0 {
+		return wb.TempHead
+	}
+	return wb.TempHead + (wb.TempBottom-wb.TempHead)*h/wb.Depth
+}
+
+// AverageTemp - средняя температура по стволу, К
+func (wb Wellbore) AverageTemp() float64 {
+	return (wb.TempHead + wb.TempBottom) / 2
+}
+
+func (wb Wellbore) validate() error {
+	if wb.Depth <= 0 {
+		return errors.New("well depth must be positive")
+	}
+	if wb.TempHead <= 0 || wb.TempBottom <= 0 {
+		return errors.New("temperature must be above absolute zero")
+	}
+	if wb.GammaG <= 0 {
+		return errors.New("gas gravity must be positive")
+	}
+	return nil
+}
+
+// TraverseOptions - параметры пошагового расчета давления по стволу
+type TraverseOptions struct {
+	Steps int
+	Z     ZOptions
+}
+
+// TraversePoint - точка профиля давления
+type TraversePoint struct {
+	Depth    float64 `json:"depth"`
+	Pressure float64 `json:"pressure"`
+	Temp     float64 `json:"temp"`
+	Z        float64 `json:"z"`
+}
+
+// TraverseResult - результат расчета давления на забое
+type TraverseResult struct {
+	Pressure float64         `json:"pressure"` // Давление на забое, Па
+	Flowing  bool            `json:"flowing"`
+	Friction float64         `json:"friction"` // Потери на трение, Па
+	Profile  []TraversePoint `json:"profile"`
+}
+
+// StaticBottomholePressure рассчитывает забойное давление остановленной
+// скважины по затрубному давлению pHead (Па) - вес столба газа в затрубье
+func StaticBottomholePressure(wb Wellbore, pHead float64, opts TraverseOptions) (TraverseResult, error) {
+	if err := wb.validate(); err != nil {
+		return TraverseResult{}, err
+	}
+	if pHead <= 0 {
+		return TraverseResult{}, errors.New("wellhead pressure must be positive")
+	}
+	return traverse(wb, pHead, 0, 0, 1, opts)
+}
+
+// FlowingBottomholePressure рассчитывает забойное давление работающей
+// скважины по буферному давлению pHead (Па) и дебиту q (м³/сут, ст. усл.)
+// по методу Адамова (Каллендера-Смита): шаговое интегрирование уравнения
+// dp/dh = ρg + λG²/(2DA²ρ) с учетом изменения Z и температуры по глубине
+func FlowingBottomholePressure(wb Wellbore, pHead, q float64, opts TraverseOptions) (TraverseResult, error) {
+	if err := wb.validate(); err != nil {
+		return TraverseResult{}, err
+	}
+	if pHead <= 0 {
+		return TraverseResult{}, errors.New("wellhead pressure must be positive")
+	}
+	if q < 0 {
+		return TraverseResult{}, errors.New("gas rate cannot be negative")
+	}
+	if q == 0 {
+		return traverse(wb, pHead, 0, 0, 1, opts)
+	}
+	if wb.Diameter <= 0 {
+		return TraverseResult{}, errors.New("tubing diameter must be positive")
+	}
+
+	massRate := MassRate(q, wb.GammaG)
+	lambda, err := FrictionFactor(wb.Diameter, wb.Roughness, Reynolds(massRate, wb.Diameter, wb.Mu))
+	if err != nil {
+		return TraverseResult{}, err
+	}
+
+	res, err := traverse(wb, pHead, massRate, lambda, 1, opts)
+	res.Flowing = true
+	return res, err
+}
+
+// MassRate - массовый расход газа, кг/с, по дебиту q в м³/сут при ст. условиях
+func MassRate(q, gammaG float64) float64 {
+	rhoStd := gammaG * airMolar * pStandard / (gasConstant * tStandard)
+	return q / secPerDay * rhoStd
+}
+
+// Reynolds - число Рейнольдса; при неизвестной вязкости возвращает 0
+func Reynolds(massRate, diameter, mu float64) float64 {
+	if mu <= 0 || diameter <= 0 {
+		return 0
+	}
+	return 4 * massRate / (math.Pi * diameter * mu * 1e-3)
+}
+
+// FrictionFactor - коэффициент гидравлического сопротивления λ.
+// При известном числе Рейнольдса - формула Свами-Джейна, иначе
+// квадратичная зона (Никурадзе) по относительной шероховатости.
+func FrictionFactor(diameter, roughness, re float64) (float64, error) {
+	if diameter <= 0 {
+		return 0, errors.New("tubing diameter must be positive")
+	}
+	rel := roughness / diameter
+	switch {
+	case re > 0 && re < 2300:
+		return 64 / re, nil
+	case re > 0:
+		l := math.Log10(rel/3.7 + 5.74/math.Pow(re, 0.9))
+		return 0.25 / (l * l), nil
+	case roughness > 0:
+		l := math.Log10(3.7 / rel)
+		return 1 / (4 * l * l), nil
+	}
+	return 0, errors.New("roughness or gas viscosity is required for friction factor")
+}
+
+// traverse - интегрирование давления сверху вниз методом Рунге-Кутты 4 порядка.
+// sinTheta - доля гравитационной составляющей (1 - вертикальный ствол,
+// для трубопровода - отношение перепада высот к длине).
+func traverse(wb Wellbore, pHead, massRate, lambda, sinTheta float64, opts TraverseOptions) (TraverseResult, error) {
+	steps := opts.Steps
+	if steps <= 0 {
+		steps = defaultStep
+	}
+
+	area := math.Pi * wb.Diameter * wb.Diameter / 4
+
+	var frictionLoss float64
+	var zErr error
+	gradient := func(h, p float64) (float64, float64, float64) {
+		t := wb.tempAt(h)
+		zr, err := ZFactor(p, t, wb.GammaG, opts.Z)
+		if err != nil {
+			zErr = err
+			return 0, 0, 0
+		}
+		rho := GasDensity(p, t, wb.GammaG, zr.Z)
+		var fric float64
+		if massRate > 0 {
+			fric = lambda * massRate * massRate / (2 * wb.Diameter * area * area * rho)
+		}
+		return rho*gravity*sinTheta + fric, fric, zr.Z
+	}
+
+	dh := wb.Depth / float64(steps)
+	res := TraverseResult{Profile: make([]TraversePoint, 0, steps+1)}
+	p := pHead
+	for i := 0; i <= steps; i++ {
+		h := float64(i) * dh
+		k1, f1, z := gradient(h, p)
+		if zErr != nil {
+			return res, zErr
+		}
+		res.Profile = append(res.Profile, TraversePoint{Depth: h, Pressure: p, Temp: wb.tempAt(h), Z: z})
+		if i == steps {
+			break
+		}
+
+		k2, f2, _ := gradient(h+dh/2, p+dh/2*k1)
+		k3, f3, _ := gradient(h+dh/2, p+dh/2*k2)
+		k4, f4, _ := gradient(h+dh, p+dh*k3)
+		if zErr != nil {
+			return res, zErr
+		}
+		p += dh / 6 * (k1 + 2*k2 + 2*k3 + k4)
+		frictionLoss += dh / 6 * (f1 + 2*f2 + 2*f3 + f4)
+	}
+
+	res.Pressure = p
+	res.Friction = frictionLoss
+	return res, nil
+}
@@ -0,0 +1,52 @@
+// pkg/calculations/bottomhole_test.go
+package calculations_test
+
+import (
+	"gas_wells/internal/pkg/calculations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testWellbore = calculations.Wellbore{
+	Depth:      3000,
+	Diameter:   0.062,
+	Roughness:  2e-5,
+	TempHead:   290,
+	TempBottom: 360,
+	GammaG:     0.6,
+}
+
+func TestStaticBottomholePressure(t *testing.T) {
+	res, err := calculations.StaticBottomholePressure(testWellbore, 10e6, calculations.TraverseOptions{})
+	require.NoError(t, err)
+	// Барометрическая формула при Zср ≈ 0.87, Tср = 325 К дает ≈ 12.4 МПа
+	assert.InDelta(t, 12.4e6, res.Pressure, 0.2e6)
+	assert.False(t, res.Flowing)
+	assert.Zero(t, res.Friction)
+	require.Len(t, res.Profile, 101)
+	assert.Equal(t, 10e6, res.Profile[0].Pressure)
+	assert.Equal(t, 360.0, res.Profile[100].Temp)
+}
+
+func TestFlowingBottomholePressure(t *testing.T) {
+	static, err := calculations.StaticBottomholePressure(testWellbore, 10e6, calculations.TraverseOptions{})
+	require.NoError(t, err)
+
+	res, err := calculations.FlowingBottomholePressure(testWellbore, 10e6, 300e3, calculations.TraverseOptions{})
+	require.NoError(t, err)
+	assert.True(t, res.Flowing)
+	assert.Positive(t, res.Friction)
+	assert.Greater(t, res.Pressure, static.Pressure)
+
+	// Без дебита расчет совпадает со статическим
+	res, err = calculations.FlowingBottomholePressure(testWellbore, 10e6, 0, calculations.TraverseOptions{})
+	require.NoError(t, err)
+	assert.InDelta(t, static.Pressure, res.Pressure, 1)
+
+	wb := testWellbore
+	wb.Roughness = 0
+	_, err = calculations.FlowingBottomholePressure(wb, 10e6, 300e3, calculations.TraverseOptions{})
+	assert.Error(t, err)
+}
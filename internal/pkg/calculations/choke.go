@@ -0,0 +1,132 @@
+package calculations
+
+import (
+	"errors"
+	"math"
+)
+
+// internal/pkg/calculations/choke.go
+
+const (
+	defaultChokeCd   = 0.85 // Коэффициент расхода штуцера
+	defaultGasKappa  = 1.3  // Показатель адиабаты природного газа
+	maxChokeDiameter = 0.2  // Верхняя граница подбора штуцера, м
+)
+
+// ChokeInput - условия течения газа через штуцер
+type ChokeInput struct {
+	Diameter float64 // Диаметр штуцера, м
+	P1       float64 // Давление до штуцера (буферное), Па
+	P2       float64 // Давление после штуцера (в шлейфе), Па
+	Temp     float64 // Температура газа до штуцера, К
+	GammaG   float64 // Относительная плотность газа
+	Cd       float64 // Коэффициент расхода (0 - 0.85)
+	Kappa    float64 // Показатель адиабаты (0 - 1.3)
+	Z        ZOptions
+}
+
+// ChokeResult - расход газа через штуцер
+type ChokeResult struct {
+	Diameter      float64 `json:"diameter"`       // Диаметр штуцера, м
+	Rate          float64 `json:"rate"`           // Дебит, м³/сут при ст. усл.
+	DeltaP        float64 `json:"delta_p"`        // Перепад давления на штуцере, Па
+	PressureRatio float64 `json:"pressure_ratio"` // P2/P1
+	CriticalRatio float64 `json:"critical_ratio"` // Критическое отношение давлений
+	Critical      bool    `json:"critical"`       // Критический режим: расход не зависит от P2
+	Z             float64 `json:"z"`              // Z до штуцера
+}
+
+func (in *ChokeInput) defaults() error {
+	if in.P1 <= 0 || in.P2 <= 0 {
+		return errors.New("choke pressures must be positive")
+	}
+	if in.P2 >= in.P1 {
+		return errors.New("downstream pressure must be below upstream pressure")
+	}
+	if in.Temp <= 0 {
+		return errors.New("temperature must be above absolute zero")
+	}
+	if in.GammaG <= 0 {
+		return errors.New("gas gravity must be positive")
+	}
+	if in.Cd <= 0 {
+		in.Cd = defaultChokeCd
+	}
+	if in.Kappa <= 1 {
+		in.Kappa = defaultGasKappa
+	}
+	return nil
+}
+
+// CriticalPressureRatio - критическое отношение давлений (2/(k+1))^(k/(k−1))
+func CriticalPressureRatio(kappa float64) float64 {
+	return math.Pow(2/(kappa+1), kappa/(kappa-1))
+}
+
+// chokeRatePerArea - дебит (м³/сут) на единицу площади проходного сечения
+// по уравнению адиабатического истечения через сопло. При P2/P1 ниже
+// критического отношения истечение критическое, расход определяется P1.
+func chokeRatePerArea(in ChokeInput) (float64, ChokeResult, error) {
+	zr, err := ZFactor(in.P1, in.Temp, in.GammaG, in.Z)
+	if err != nil {
+		return 0, ChokeResult{}, err
+	}
+	k := in.Kappa
+	res := ChokeResult{
+		DeltaP:        in.P1 - in.P2,
+		PressureRatio: in.P2 / in.P1,
+		CriticalRatio: CriticalPressureRatio(k),
+		Z:             zr.Z,
+	}
+	y := res.PressureRatio
+	if y <= res.CriticalRatio {
+		y = res.CriticalRatio
+		res.Critical = true
+	}
+
+	molar := in.GammaG * airMolar
+	mass := in.Cd * in.P1 * math.Sqrt(2*k/(k-1)*molar/(zr.Z*gasConstant*in.Temp)*
+		(math.Pow(y, 2/k)-math.Pow(y, (k+1)/k)))
+	rhoStd := molar * pStandard / (gasConstant * tStandard)
+	return mass / rhoStd * secPerDay, res, nil
+}
+
+// ChokeFlow рассчитывает дебит газа через штуцер в критическом
+// и докритическом режимах
+func ChokeFlow(in ChokeInput) (ChokeResult, error) {
+	if in.Diameter <= 0 {
+		return ChokeResult{}, errors.New("choke diameter must be positive")
+	}
+	if err := in.defaults(); err != nil {
+		return ChokeResult{}, err
+	}
+	perArea, res, err := chokeRatePerArea(in)
+	if err != nil {
+		return ChokeResult{}, err
+	}
+	res.Diameter = in.Diameter
+	res.Rate = perArea * math.Pi * in.Diameter * in.Diameter / 4
+	return res, nil
+}
+
+// ChokeSize подбирает диаметр штуцера (м), обеспечивающий дебит q (м³/сут)
+// при заданных давлениях до и после штуцера; in.Diameter не используется
+func ChokeSize(in ChokeInput, q float64) (ChokeResult, error) {
+	if q <= 0 {
+		return ChokeResult{}, errors.New("target rate must be positive")
+	}
+	if err := in.defaults(); err != nil {
+		return ChokeResult{}, err
+	}
+	perArea, res, err := chokeRatePerArea(in)
+	if err != nil {
+		return ChokeResult{}, err
+	}
+	// Расход пропорционален площади сечения
+	res.Diameter = math.Sqrt(4 * q / perArea / math.Pi)
+	if res.Diameter > maxChokeDiameter {
+		return ChokeResult{}, errors.New("target rate cannot be reached with a wellhead choke")
+	}
+	res.Rate = q
+	return res, nil
+}
@@ -0,0 +1,54 @@
+// pkg/calculations/choke_test.go
+package calculations_test
+
+import (
+	"gas_wells/internal/pkg/calculations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChokeFlow(t *testing.T) {
+	in := calculations.ChokeInput{Diameter: 0.01, P1: 10e6, P2: 2e6, Temp: 290, GammaG: 0.6}
+
+	// Критический режим: при Z ≈ 0.87 массовая скорость ≈ 1.6e4 кг/(с·м²),
+	// через штуцер 10 мм ≈ 150 тыс. м³/сут
+	res, err := calculations.ChokeFlow(in)
+	require.NoError(t, err)
+	assert.True(t, res.Critical)
+	assert.InDelta(t, 0.5457, res.CriticalRatio, 1e-3)
+	assert.InDelta(t, 153e3, res.Rate, 10e3)
+	assert.Equal(t, 8e6, res.DeltaP)
+
+	// В критическом режиме расход не зависит от давления после штуцера
+	in.P2 = 4e6
+	same, err := calculations.ChokeFlow(in)
+	require.NoError(t, err)
+	assert.InDelta(t, res.Rate, same.Rate, 1e-6)
+
+	in.P2 = 8e6
+	sub, err := calculations.ChokeFlow(in)
+	require.NoError(t, err)
+	assert.False(t, sub.Critical)
+	assert.Less(t, sub.Rate, res.Rate)
+
+	in.P2 = 10e6
+	_, err = calculations.ChokeFlow(in)
+	assert.Error(t, err)
+}
+
+func TestChokeSize(t *testing.T) {
+	in := calculations.ChokeInput{P1: 10e6, P2: 8e6, Temp: 290, GammaG: 0.6}
+	size, err := calculations.ChokeSize(in, 100e3)
+	require.NoError(t, err)
+	assert.False(t, size.Critical)
+
+	in.Diameter = size.Diameter
+	res, err := calculations.ChokeFlow(in)
+	require.NoError(t, err)
+	assert.InDelta(t, 100e3, res.Rate, 1)
+
+	_, err = calculations.ChokeSize(in, 1e12)
+	assert.Error(t, err)
+}
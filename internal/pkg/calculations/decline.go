@@ -0,0 +1,268 @@
+package calculations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// internal/pkg/calculations/decline.go
+
+// DeclineModel - модель падения добычи Арпса
+type DeclineModel string
+
+const (
+	DeclineExponential DeclineModel = "exponential" // b = 0
+	DeclineHyperbolic  DeclineModel = "hyperbolic"  // 0 < b < 1
+	DeclineHarmonic    DeclineModel = "harmonic"    // b = 1
+)
+
+// Сетка показателя b для гиперболической модели
+const (
+	hyperbolicBMin  = 0.01
+	hyperbolicBMax  = 0.99
+	hyperbolicBStep = 0.01
+	daysPerYear     = 365.25
+
+	defaultForecastPoints = 50
+)
+
+// ParseDeclineModel возвращает модель по имени; пустая строка - выбор
+// модели с наименьшей ошибкой (возвращается пустая модель)
+func ParseDeclineModel(s string) (DeclineModel, error) {
+	switch m := DeclineModel(s); m {
+	case "", DeclineExponential, DeclineHyperbolic, DeclineHarmonic:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown decline model %q", s)
+}
+
+// RatePoint - дебит Q (м³/сут) в момент T (сут от начала истории)
+type RatePoint struct {
+	T float64 `json:"t"`
+	Q float64 `json:"q"`
+}
+
+// DeclineFit - параметры кривой Арпса q(t) = qi / (1 + b·Di·t)^(1/b)
+type DeclineFit struct {
+	Model    DeclineModel `json:"model"`
+	Qi       float64      `json:"qi"`        // Начальный дебит, м³/сут
+	Di       float64      `json:"di"`        // Начальный темп падения, 1/сут
+	DiAnnual float64      `json:"di_annual"` // Номинальный темп падения, 1/год
+	B        float64      `json:"b"`         // Показатель Арпса
+	R2       float64      `json:"r2"`        // Коэффициент детерминации по дебиту
+	RMSE     float64      `json:"rmse"`      // Среднеквадратичная ошибка, м³/сут
+	Points   int          `json:"points"`
+}
+
+// Rate - дебит (м³/сут) в момент t (сут)
+func (f DeclineFit) Rate(t float64) float64 {
+	switch f.Model {
+	case DeclineExponential:
+		return f.Qi * math.Exp(-f.Di*t)
+	case DeclineHarmonic:
+		return f.Qi / (1 + f.Di*t)
+	}
+	return f.Qi / math.Pow(1+f.B*f.Di*t, 1/f.B)
+}
+
+// Cumulative - накопленная добыча (м³) от начала истории до момента t (сут)
+func (f DeclineFit) Cumulative(t float64) float64 {
+	q := f.Rate(t)
+	switch f.Model {
+	case DeclineExponential:
+		return (f.Qi - q) / f.Di
+	case DeclineHarmonic:
+		return f.Qi / f.Di * math.Log(f.Qi/q)
+	}
+	return math.Pow(f.Qi, f.B) / ((1 - f.B) * f.Di) * (math.Pow(f.Qi, 1-f.B) - math.Pow(q, 1-f.B))
+}
+
+// TimeToRate - момент (сут), когда дебит снизится до q
+func (f DeclineFit) TimeToRate(q float64) (float64, error) {
+	if q <= 0 {
+		return 0, errors.New("rate limit must be positive")
+	}
+	if q >= f.Qi {
+		return 0, nil
+	}
+	switch f.Model {
+	case DeclineExponential:
+		return math.Log(f.Qi/q) / f.Di, nil
+	case DeclineHarmonic:
+		return (f.Qi/q - 1) / f.Di, nil
+	}
+	return (math.Pow(f.Qi/q, f.B) - 1) / (f.B * f.Di), nil
+}
+
+// FitDecline подбирает параметры модели Арпса методом наименьших квадратов.
+// Точки с нулевым дебитом (простои) не учитываются.
+// Пустая модель - подбор всех трех моделей и выбор лучшей по RMSE.
+func FitDecline(points []RatePoint, model DeclineModel) (DeclineFit, error) {
+	data := make([]RatePoint, 0, len(points))
+	for _, p := range points {
+		if p.Q > 0 {
+			data = append(data, p)
+		}
+	}
+	if len(data) < 3 {
+		return DeclineFit{}, errors.New("at least three non-zero rates are required")
+	}
+
+	switch model {
+	case DeclineExponential, DeclineHarmonic:
+		return fitArps(data, model, 0)
+	case DeclineHyperbolic:
+		return fitHyperbolic(data)
+	case "":
+		var best DeclineFit
+		var bestErr error = errors.New("rates are not declining")
+		for _, m := range []DeclineModel{DeclineExponential, DeclineHyperbolic, DeclineHarmonic} {
+			fit, err := FitDecline(data, m)
+			if err != nil {
+				continue
+			}
+			if bestErr != nil || fit.RMSE < best.RMSE {
+				best, bestErr = fit, nil
+			}
+		}
+		return best, bestErr
+	}
+	return DeclineFit{}, fmt.Errorf("unknown decline model %q", model)
+}
+
+// fitHyperbolic перебирает показатель b по сетке
+func fitHyperbolic(data []RatePoint) (DeclineFit, error) {
+	var best DeclineFit
+	found := false
+	for b := hyperbolicBMin; b <= hyperbolicBMax+1e-9; b += hyperbolicBStep {
+		fit, err := fitArps(data, DeclineHyperbolic, b)
+		if err != nil {
+			continue
+		}
+		if !found || fit.RMSE < best.RMSE {
+			best, found = fit, true
+		}
+	}
+	if !found {
+		return DeclineFit{}, errors.New("rates are not declining")
+	}
+	return best, nil
+}
+
+// fitArps - линейная регрессия по линеаризованной форме модели:
+// ln q = ln qi − Di·t (экспоненциальная), q^−b = qi^−b + b·Di·qi^−b·t
+// (гиперболическая, b задано), 1/q = 1/qi + Di/qi·t (гармоническая)
+func fitArps(data []RatePoint, model DeclineModel, b float64) (DeclineFit, error) {
+	transform := func(q float64) float64 { return math.Pow(q, -b) }
+	switch model {
+	case DeclineExponential:
+		transform = math.Log
+		b = 0
+	case DeclineHarmonic:
+		b = 1
+		transform = func(q float64) float64 { return 1 / q }
+	}
+
+	xs := make([]float64, len(data))
+	ys := make([]float64, len(data))
+	for i, p := range data {
+		xs[i] = p.T
+		ys[i] = transform(p.Q)
+	}
+	c0, c1, err := linearFit(xs, ys)
+	if err != nil {
+		return DeclineFit{}, err
+	}
+
+	fit := DeclineFit{Model: model, B: b, Points: len(data)}
+	switch model {
+	case DeclineExponential:
+		fit.Qi = math.Exp(c0)
+		fit.Di = -c1
+	case DeclineHarmonic:
+		fit.Qi = 1 / c0
+		fit.Di = c1 * fit.Qi
+	default:
+		if c0 <= 0 {
+			return DeclineFit{}, errors.New("invalid hyperbolic fit")
+		}
+		fit.Qi = math.Pow(c0, -1/b)
+		fit.Di = c1 / (b * c0)
+	}
+	if fit.Di <= 0 || fit.Qi <= 0 || math.IsInf(fit.Qi, 0) {
+		return DeclineFit{}, errors.New("rates are not declining")
+	}
+	fit.DiAnnual = fit.Di * daysPerYear
+
+	// Качество подбора оценивается по самому дебиту, а не по преобразованию
+	var mean, ssRes, ssTot float64
+	for _, p := range data {
+		mean += p.Q
+	}
+	mean /= float64(len(data))
+	for _, p := range data {
+		r := p.Q - fit.Rate(p.T)
+		ssRes += r * r
+		ssTot += (p.Q - mean) * (p.Q - mean)
+	}
+	fit.RMSE = math.Sqrt(ssRes / float64(len(data)))
+	fit.R2 = 1
+	if ssTot > 0 {
+		fit.R2 = 1 - ssRes/ssTot
+	}
+	return fit, nil
+}
+
+// linearFit - метод наименьших квадратов для y = c0 + c1·x
+func linearFit(xs, ys []float64) (c0, c1 float64, err error) {
+	n := float64(len(xs))
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	den := n*sxx - sx*sx
+	if den == 0 {
+		return 0, 0, errors.New("at least two distinct dates are required")
+	}
+	c1 = (n*sxy - sx*sy) / den
+	c0 = (sy - c1*sx) / n
+	return c0, c1, nil
+}
+
+// DeclineForecast - прогноз добычи до экономического предела
+type DeclineForecast struct {
+	EconomicLimit float64     `json:"economic_limit"` // Минимальный рентабельный дебит, м³/сут
+	TimeToLimit   float64     `json:"time_to_limit"`  // Момент достижения предела, сут от начала истории
+	Remaining     float64     `json:"remaining"`      // Остаточные извлекаемые запасы, м³
+	Curve         []RatePoint `json:"curve"`
+}
+
+// ForecastDecline строит прогноз от момента tFrom (обычно последняя дата
+// истории) до снижения дебита до qLimit и оценивает остаточную добычу
+func ForecastDecline(fit DeclineFit, tFrom, qLimit float64, points int) (DeclineForecast, error) {
+	tLimit, err := fit.TimeToRate(qLimit)
+	if err != nil {
+		return DeclineForecast{}, err
+	}
+	res := DeclineForecast{EconomicLimit: qLimit, TimeToLimit: tLimit}
+	if tLimit <= tFrom {
+		// Предел уже достигнут
+		return res, nil
+	}
+	if points < 2 {
+		points = defaultForecastPoints
+	}
+	points = min(points, MaxCurvePoints)
+
+	res.Remaining = fit.Cumulative(tLimit) - fit.Cumulative(tFrom)
+	res.Curve = make([]RatePoint, points)
+	for i := range res.Curve {
+		t := tFrom + (tLimit-tFrom)*float64(i)/float64(points-1)
+		res.Curve[i] = RatePoint{T: t, Q: fit.Rate(t)}
+	}
+	return res, nil
+}
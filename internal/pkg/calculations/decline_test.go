@@ -0,0 +1,85 @@
+// pkg/calculations/decline_test.go
+package calculations_test
+
+import (
+	"gas_wells/internal/pkg/calculations"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// declineHistory - ежемесячные дебиты по заданной кривой Арпса
+func declineHistory(fit calculations.DeclineFit, months int) []calculations.RatePoint {
+	points := make([]calculations.RatePoint, months)
+	for i := range points {
+		t := float64(i) * 30
+		points[i] = calculations.RatePoint{T: t, Q: fit.Rate(t)}
+	}
+	return points
+}
+
+func TestFitDecline(t *testing.T) {
+	cases := []calculations.DeclineFit{
+		{Model: calculations.DeclineExponential, Qi: 200e3, Di: 0.002},
+		{Model: calculations.DeclineHyperbolic, Qi: 200e3, Di: 0.003, B: 0.5},
+		{Model: calculations.DeclineHarmonic, Qi: 200e3, Di: 0.004, B: 1},
+	}
+	for _, want := range cases {
+		t.Run(string(want.Model), func(t *testing.T) {
+			fit, err := calculations.FitDecline(declineHistory(want, 24), want.Model)
+			require.NoError(t, err)
+			assert.InDelta(t, want.Qi, fit.Qi, want.Qi*1e-3)
+			assert.InDelta(t, want.Di, fit.Di, want.Di*1e-2)
+			assert.InDelta(t, want.B, fit.B, 0.011)
+			assert.InDelta(t, 1, fit.R2, 1e-6)
+
+			// Автоматический выбор находит ту же модель
+			best, err := calculations.FitDecline(declineHistory(want, 24), "")
+			require.NoError(t, err)
+			assert.Equal(t, want.Model, best.Model)
+		})
+	}
+}
+
+func TestFitDeclineRejectsGrowth(t *testing.T) {
+	points := []calculations.RatePoint{{T: 0, Q: 100}, {T: 30, Q: 110}, {T: 60, Q: 0}, {T: 90, Q: 120}}
+	_, err := calculations.FitDecline(points, "")
+	assert.Error(t, err)
+
+	_, err = calculations.FitDecline(points[:2], calculations.DeclineExponential)
+	assert.Error(t, err)
+}
+
+func TestForecastDecline(t *testing.T) {
+	fit := calculations.DeclineFit{Model: calculations.DeclineExponential, Qi: 200e3, Di: 0.002}
+	res, err := calculations.ForecastDecline(fit, 365, 20e3, 10)
+	require.NoError(t, err)
+
+	// ln(10)/0.002 ≈ 1151 сут
+	assert.InDelta(t, math.Log(10)/0.002, res.TimeToLimit, 1e-6)
+	require.Len(t, res.Curve, 10)
+	assert.InDelta(t, 20e3, res.Curve[9].Q, 1e-6)
+	// Остаточные запасы: (q(365) − qlim)/Di
+	assert.InDelta(t, (fit.Rate(365)-20e3)/0.002, res.Remaining, 1)
+
+	// Число точек прогноза ограничено
+	res, err = calculations.ForecastDecline(fit, 365, 20e3, 1e6)
+	require.NoError(t, err)
+	assert.Len(t, res.Curve, calculations.MaxCurvePoints)
+
+	// Интеграл гиперболической кривой совпадает с численным
+	hyp := calculations.DeclineFit{Model: calculations.DeclineHyperbolic, Qi: 200e3, Di: 0.003, B: 0.5}
+	var sum float64
+	for d := 0.0; d < 1000; d += 0.1 {
+		sum += hyp.Rate(d+0.05) * 0.1
+	}
+	assert.InDelta(t, sum, hyp.Cumulative(1000), sum*1e-6)
+
+	// Предел уже достигнут
+	res, err = calculations.ForecastDecline(fit, 2000, 20e3, 10)
+	require.NoError(t, err)
+	assert.Zero(t, res.Remaining)
+	assert.Empty(t, res.Curve)
+}
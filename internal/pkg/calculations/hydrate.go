@@ -0,0 +1,144 @@
+package calculations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// internal/pkg/calculations/hydrate.go
+
+// HydrateMethod - корреляция равновесной температуры гидратообразования
+type HydrateMethod string
+
+const (
+	HydrateKatz   HydrateMethod = "katz"   // Аппроксимация номограммы Катца (Towler-Mokhatab)
+	HydrateMotiee HydrateMethod = "motiee" // Корреляция Мотиее
+
+	DefaultHydrateMethod = HydrateKatz
+)
+
+// HydrateRisk - уровень риска гидратообразования
+type HydrateRisk string
+
+const (
+	HydrateRiskNone    HydrateRisk = "none"    // Запас по температуре больше safetyMargin
+	HydrateRiskWarning HydrateRisk = "warning" // Запас меньше safetyMargin
+	HydrateRiskHigh    HydrateRisk = "high"    // Условия в зоне гидратообразования
+)
+
+const (
+	hydrateSafetyMargin = 3.0     // Запас по температуре, К
+	methanolMolar       = 32.04   // г/моль
+	methanolK           = 1297.0  // Константа Хаммершмидта для метанола
+	methanolDensity     = 792.0   // кг/м³
+	defaultWaterDensity = 1000.0  // кг/м³
+	celsiusZero         = 273.15  // К
+	fahrenheitPerKelvin = 9.0 / 5 // °F/К
+)
+
+// ParseHydrateMethod возвращает метод по имени; пустая строка - метод по умолчанию
+func ParseHydrateMethod(s string) (HydrateMethod, error) {
+	switch m := HydrateMethod(s); m {
+	case "":
+		return DefaultHydrateMethod, nil
+	case HydrateKatz, HydrateMotiee:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown hydrate method %q", s)
+}
+
+// HydrateTemperature возвращает равновесную температуру гидратообразования (К)
+// при давлении p (Па) для газа относительной плотности gammaG
+func HydrateTemperature(p, gammaG float64, method HydrateMethod) (float64, error) {
+	if p <= 0 {
+		return 0, errors.New("pressure must be positive")
+	}
+	if gammaG <= 0 {
+		return 0, errors.New("gas gravity must be positive")
+	}
+
+	psia := p / paPerPsi
+	var tf float64 // °F
+	switch method {
+	case HydrateKatz, "":
+		lp, lg := math.Log(psia), math.Log(gammaG)
+		tf = 13.47*lp + 34.27*lg - 1.675*lp*lg - 20.35
+	case HydrateMotiee:
+		lp := math.Log10(psia)
+		tf = -238.24469 + 78.99667*lp - 5.352544*lp*lp +
+			349.473877*gammaG - 150.854675*gammaG*gammaG - 27.604065*lp*gammaG
+	default:
+		return 0, fmt.Errorf("unknown hydrate method %q", method)
+	}
+
+	return (tf-32)/fahrenheitPerKelvin + celsiusZero, nil
+}
+
+// HydrateInput - условия в точке контроля (обычно устье) и расход воды
+type HydrateInput struct {
+	Pressure     float64 // Давление, Па
+	Temp         float64 // Температура потока, К
+	GammaG       float64 // Относительная плотность газа
+	Q            float64 // Дебит газа, м³/сут
+	WGF          float64 // Водогазовый фактор, см³/м³
+	WaterDensity float64 // Плотность воды, кг/м³ (0 - 1000)
+}
+
+// HydrateResult - оценка риска гидратообразования
+type HydrateResult struct {
+	Method           HydrateMethod `json:"method"`
+	HydrateTemp      float64       `json:"hydrate_temp"` // Равновесная температура, К
+	Subcooling       float64       `json:"subcooling"`   // Thyd − T, К (> 0 - в зоне гидратов)
+	Risk             HydrateRisk   `json:"risk"`
+	MethanolConc     float64       `json:"methanol_conc"`      // Концентрация в водной фазе, % масс.
+	MethanolRate     float64       `json:"methanol_rate"`      // Расход метанола, кг/сут
+	MethanolRateLitr float64       `json:"methanol_rate_litr"` // Расход метанола, л/сут
+}
+
+// AssessHydrates рассчитывает переохлаждение потока относительно равновесной
+// температуры гидратообразования и удельный расход метанола (уравнение
+// Хаммершмидта, только водная фаза) для снижения температуры
+// гидратообразования на величину переохлаждения с запасом hydrateSafetyMargin
+func AssessHydrates(in HydrateInput, method HydrateMethod) (HydrateResult, error) {
+	if in.Temp <= 0 {
+		return HydrateResult{}, errors.New("temperature must be above absolute zero")
+	}
+	if method == "" {
+		method = DefaultHydrateMethod
+	}
+
+	th, err := HydrateTemperature(in.Pressure, in.GammaG, method)
+	if err != nil {
+		return HydrateResult{}, err
+	}
+
+	res := HydrateResult{
+		Method:      method,
+		HydrateTemp: th,
+		Subcooling:  th - in.Temp,
+	}
+	switch {
+	case res.Subcooling > 0:
+		res.Risk = HydrateRiskHigh
+	case res.Subcooling > -hydrateSafetyMargin:
+		res.Risk = HydrateRiskWarning
+	default:
+		res.Risk = HydrateRiskNone
+		return res, nil
+	}
+
+	// Требуемое снижение температуры гидратообразования
+	dt := res.Subcooling + hydrateSafetyMargin
+	res.MethanolConc = 100 * methanolMolar * dt / (methanolK + methanolMolar*dt)
+
+	rhoW := in.WaterDensity
+	if rhoW <= 0 {
+		rhoW = defaultWaterDensity
+	}
+	waterRate := in.Q * in.WGF * 1e-6 * rhoW // кг/сут
+	res.MethanolRate = waterRate * res.MethanolConc / (100 - res.MethanolConc)
+	res.MethanolRateLitr = res.MethanolRate / methanolDensity * 1000
+
+	return res, nil
+}
@@ -0,0 +1,42 @@
+// pkg/calculations/hydrate_test.go
+package calculations_test
+
+import (
+	"gas_wells/internal/pkg/calculations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHydrateTemperature(t *testing.T) {
+	// Номограмма Катца: gammaG = 0.6, 1000 psia -> около 62 °F (16.7 °C)
+	th, err := calculations.HydrateTemperature(6.895e6, 0.6, calculations.HydrateKatz)
+	require.NoError(t, err)
+	assert.InDelta(t, 289.8, th, 1.5)
+
+	// Температура гидратообразования растет с давлением
+	low, err := calculations.HydrateTemperature(2e6, 0.6, calculations.HydrateMotiee)
+	require.NoError(t, err)
+	high, err := calculations.HydrateTemperature(10e6, 0.6, calculations.HydrateMotiee)
+	require.NoError(t, err)
+	assert.Greater(t, high, low)
+}
+
+func TestAssessHydrates(t *testing.T) {
+	in := calculations.HydrateInput{Pressure: 7e6, Temp: 280, GammaG: 0.6, Q: 300e3, WGF: 20}
+
+	res, err := calculations.AssessHydrates(in, calculations.HydrateKatz)
+	require.NoError(t, err)
+	assert.Equal(t, calculations.HydrateRiskHigh, res.Risk)
+	assert.Positive(t, res.Subcooling)
+	assert.Positive(t, res.MethanolConc)
+	// 6 т/сут воды при концентрации ~23.5% требуют ~1.8 т/сут метанола
+	assert.InDelta(t, 1840, res.MethanolRate, 50)
+
+	in.Temp = 310
+	res, err = calculations.AssessHydrates(in, calculations.HydrateKatz)
+	require.NoError(t, err)
+	assert.Equal(t, calculations.HydrateRiskNone, res.Risk)
+	assert.Zero(t, res.MethanolRate)
+}
@@ -0,0 +1,134 @@
+package calculations
+
+import (
+	"errors"
+	"math"
+)
+
+// internal/pkg/calculations/ipr.go
+
+// Двучленное уравнение притока газа: Pпл² − Pз² = A·Q + B·Q².
+// Коэффициенты A и B задаются в промысловых единицах: давление в МПа,
+// дебит в тыс. м³/сут. Внешний интерфейс работает в Па и м³/сут.
+const (
+	paPerMPa       = 1e6
+	m3PerThousand  = 1e3
+	defaultIPRStep = 50
+)
+
+// Inflow - параметры притока газа к забою
+type Inflow struct {
+	A   float64 // МПа²·сут/тыс.м³
+	B   float64 // (МПа·сут/тыс.м³)²
+	Ppl float64 // Пластовое давление, Па
+}
+
+// IPRPoint - точка индикаторной кривой
+type IPRPoint struct {
+	Pz float64 `json:"pz"` // Забойное давление, Па
+	Q  float64 `json:"q"`  // Дебит, м³/сут
+}
+
+func (in Inflow) validate() error {
+	if in.Ppl <= 0 {
+		return errors.New("reservoir pressure must be positive")
+	}
+	if in.A < 0 || in.B < 0 || (in.A == 0 && in.B == 0) {
+		return errors.New("inflow coefficients A and B must be non-negative and not both zero")
+	}
+	return nil
+}
+
+// Rate возвращает дебит (м³/сут) при забойном давлении pz (Па)
+func (in Inflow) Rate(pz float64) (float64, error) {
+	if err := in.validate(); err != nil {
+		return 0, err
+	}
+	if pz < 0 {
+		return 0, errors.New("bottomhole pressure cannot be negative")
+	}
+	if pz >= in.Ppl {
+		return 0, nil
+	}
+
+	ppl := in.Ppl / paPerMPa
+	p := pz / paPerMPa
+	delta := ppl*ppl - p*p
+
+	var q float64
+	if in.B == 0 {
+		q = delta / in.A
+	} else {
+		q = (-in.A + math.Sqrt(in.A*in.A+4*in.B*delta)) / (2 * in.B)
+	}
+	return q * m3PerThousand, nil
+}
+
+// BottomholePressure возвращает забойное давление (Па) при дебите q (м³/сут)
+func (in Inflow) BottomholePressure(q float64) (float64, error) {
+	if err := in.validate(); err != nil {
+		return 0, err
+	}
+	if q < 0 {
+		return 0, errors.New("gas rate cannot be negative")
+	}
+
+	ppl := in.Ppl / paPerMPa
+	qt := q / m3PerThousand
+	p2 := ppl*ppl - in.A*qt - in.B*qt*qt
+	if p2 < 0 {
+		return 0, errors.New("gas rate exceeds absolute open flow potential")
+	}
+	return math.Sqrt(p2) * paPerMPa, nil
+}
+
+// RateAtDrawdown возвращает дебит (м³/сут) при депрессии dp (Па)
+func (in Inflow) RateAtDrawdown(dp float64) (float64, error) {
+	if dp < 0 {
+		return 0, errors.New("drawdown cannot be negative")
+	}
+	if dp > in.Ppl {
+		return 0, errors.New("drawdown exceeds reservoir pressure")
+	}
+	return in.Rate(in.Ppl - dp)
+}
+
+// AbsoluteOpenFlow - абсолютно свободный дебит (м³/сут),
+// т.е. дебит при атмосферном давлении на забое
+func (in Inflow) AbsoluteOpenFlow() (float64, error) {
+	return in.Rate(pStandard)
+}
+
+// Curve строит индикаторную кривую из points точек в диапазоне
+// забойных давлений [pMin, pMax] (Па). Нулевые границы заменяются
+// атмосферным и пластовым давлением соответственно.
+func (in Inflow) Curve(pMin, pMax float64, points int) ([]IPRPoint, error) {
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+	if pMin <= 0 {
+		pMin = pStandard
+	}
+	if pMax <= 0 || pMax > in.Ppl {
+		pMax = in.Ppl
+	}
+	if pMin >= pMax {
+		return nil, errors.New("invalid pressure range")
+	}
+	if points < 2 {
+		points = defaultIPRStep
+	}
+	points = min(points, MaxCurvePoints)
+
+	curve := make([]IPRPoint, 0, points)
+	step := (pMax - pMin) / float64(points-1)
+	for i := 0; i < points; i++ {
+		pz := pMax - float64(i)*step
+		q, err := in.Rate(pz)
+		if err != nil {
+			return nil, err
+		}
+		curve = append(curve, IPRPoint{Pz: pz, Q: q})
+	}
+	return curve, nil
+}
@@ -0,0 +1,66 @@
+// pkg/calculations/ipr_test.go
+package calculations_test
+
+import (
+	"gas_wells/internal/pkg/calculations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInflow(t *testing.T) {
+	// Pпл = 20 МПа, A = 0.5, B = 0.001: при Q = 200 тыс.м³/сут
+	// Pз² = 400 − 100 − 40 = 260
+	in := calculations.Inflow{A: 0.5, B: 0.001, Ppl: 20e6}
+
+	pz, err := in.BottomholePressure(200e3)
+	require.NoError(t, err)
+	assert.InDelta(t, 16.1245e6, pz, 100)
+
+	q, err := in.Rate(pz)
+	require.NoError(t, err)
+	assert.InDelta(t, 200e3, q, 1)
+
+	q, err = in.RateAtDrawdown(20e6 - pz)
+	require.NoError(t, err)
+	assert.InDelta(t, 200e3, q, 1)
+
+	aof, err := in.AbsoluteOpenFlow()
+	require.NoError(t, err)
+	assert.Greater(t, aof, q)
+
+	_, err = in.BottomholePressure(2 * aof)
+	assert.Error(t, err)
+
+	curve, err := in.Curve(0, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, curve, 10)
+	assert.Equal(t, 20e6, curve[0].Pz)
+	assert.Zero(t, curve[0].Q)
+	assert.InDelta(t, aof, curve[9].Q, 1)
+
+	_, err = calculations.Inflow{Ppl: 20e6}.Rate(10e6)
+	assert.Error(t, err)
+}
+
+func TestFitInflow(t *testing.T) {
+	in := calculations.Inflow{A: 0.5, B: 0.001, Ppl: 20e6}
+	var points []calculations.TestPoint
+	for _, q := range []float64{100e3, 200e3, 300e3, 400e3} {
+		pz, err := in.BottomholePressure(q)
+		require.NoError(t, err)
+		points = append(points, calculations.TestPoint{Q: q, Pz: pz, Ppl: in.Ppl})
+	}
+
+	fit, err := calculations.FitInflow(points)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, fit.A, 1e-9)
+	assert.InDelta(t, 0.001, fit.B, 1e-12)
+	assert.InDelta(t, 1, fit.R2, 1e-9)
+	assert.True(t, fit.Valid)
+	assert.Equal(t, 4, fit.Points)
+
+	_, err = calculations.FitInflow(points[:1])
+	assert.Error(t, err)
+}
@@ -0,0 +1,106 @@
+package calculations
+
+import (
+	"errors"
+	"math"
+)
+
+// internal/pkg/calculations/matbal.go
+
+// SurveyPoint - замер пластового давления P (Па) при накопленной добыче Gp (м³)
+type SurveyPoint struct {
+	P  float64
+	Gp float64
+}
+
+// PZPoint - точка графика P/Z от накопленной добычи
+type PZPoint struct {
+	Gp float64 `json:"gp"`  // Накопленная добыча, м³
+	P  float64 `json:"p"`   // Пластовое давление, Па
+	Z  float64 `json:"z"`   // Коэффициент сверхсжимаемости при пластовой температуре
+	PZ float64 `json:"p_z"` // P/Z, Па
+}
+
+// MaterialBalanceResult - оценка запасов газовой залежи по методу падения давления
+type MaterialBalanceResult struct {
+	Points         []PZPoint `json:"points"`
+	PZi            float64   `json:"p_z_initial"`     // Начальное P/Z по прямой, Па
+	Slope          float64   `json:"slope"`           // Наклон прямой, Па/м³
+	GIIP           float64   `json:"giip"`            // Начальные запасы газа, м³
+	R2             float64   `json:"r2"`              // Коэффициент детерминации
+	Abandonment    float64   `json:"abandonment"`     // Давление забрасывания, Па
+	PZAbandonment  float64   `json:"p_z_abandonment"` // P/Z при давлении забрасывания, Па
+	RecoveryFactor float64   `json:"recovery_factor"` // Коэффициент извлечения газа, д.ед.
+	Recoverable    float64   `json:"recoverable"`     // Извлекаемые запасы, м³
+	Remaining      float64   `json:"remaining"`       // Остаточные извлекаемые запасы, м³
+}
+
+// MaterialBalance строит прямую P/Z = (P/Z)i·(1 − Gp/G) по замерам пластового
+// давления для газовой залежи без водонапорного режима. Z рассчитывается при
+// пластовой температуре temp (К). По давлению забрасывания pAbandon (Па)
+// оцениваются коэффициент извлечения и извлекаемые запасы.
+func MaterialBalance(points []SurveyPoint, temp, gammaG, pAbandon float64, opts ZOptions) (MaterialBalanceResult, error) {
+	if len(points) < 2 {
+		return MaterialBalanceResult{}, errors.New("at least two pressure surveys are required")
+	}
+	if pAbandon <= 0 {
+		return MaterialBalanceResult{}, errors.New("abandonment pressure must be positive")
+	}
+
+	res := MaterialBalanceResult{
+		Points:      make([]PZPoint, len(points)),
+		Abandonment: pAbandon,
+	}
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	var maxGp float64
+	for i, p := range points {
+		if p.P <= 0 || p.Gp < 0 {
+			return MaterialBalanceResult{}, errors.New("survey pressure must be positive and cumulative production non-negative")
+		}
+		zr, err := ZFactor(p.P, temp, gammaG, opts)
+		if err != nil {
+			return MaterialBalanceResult{}, err
+		}
+		res.Points[i] = PZPoint{Gp: p.Gp, P: p.P, Z: zr.Z, PZ: p.P / zr.Z}
+		xs[i], ys[i] = p.Gp, res.Points[i].PZ
+		maxGp = math.Max(maxGp, p.Gp)
+	}
+
+	c0, c1, err := linearFit(xs, ys)
+	if err != nil {
+		return MaterialBalanceResult{}, errors.New("surveys must have different cumulative production")
+	}
+	if c1 >= 0 || c0 <= 0 {
+		return MaterialBalanceResult{}, errors.New("P/Z does not decline with cumulative production")
+	}
+	res.PZi = c0
+	res.Slope = c1
+	res.GIIP = -c0 / c1
+
+	var mean, ssRes, ssTot float64
+	for _, y := range ys {
+		mean += y
+	}
+	mean /= float64(len(ys))
+	for i := range xs {
+		r := ys[i] - (c0 + c1*xs[i])
+		ssRes += r * r
+		ssTot += (ys[i] - mean) * (ys[i] - mean)
+	}
+	res.R2 = 1
+	if ssTot > 0 {
+		res.R2 = 1 - ssRes/ssTot
+	}
+
+	za, err := ZFactor(pAbandon, temp, gammaG, opts)
+	if err != nil {
+		return MaterialBalanceResult{}, err
+	}
+	res.PZAbandonment = pAbandon / za.Z
+	res.RecoveryFactor = math.Max(0, 1-res.PZAbandonment/res.PZi)
+	res.Recoverable = res.GIIP * res.RecoveryFactor
+	res.Remaining = math.Max(0, res.Recoverable-maxGp)
+
+	return res, nil
+}
@@ -0,0 +1,68 @@
+// pkg/calculations/matbal_test.go
+package calculations_test
+
+import (
+	"gas_wells/internal/pkg/calculations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaterialBalance(t *testing.T) {
+	const (
+		temp   = 360.0
+		gammaG = 0.6
+		giip   = 5e9 // м³
+	)
+	// Синтетические замеры: давление подобрано так, чтобы P/Z лежало на прямой
+	pzi := pzAt(t, 25e6, temp, gammaG)
+	var points []calculations.SurveyPoint
+	for _, gp := range []float64{0, 0.5e9, 1e9, 1.5e9} {
+		target := pzi * (1 - gp/giip)
+		points = append(points, calculations.SurveyPoint{P: pressureForPZ(t, target, temp, gammaG), Gp: gp})
+	}
+
+	res, err := calculations.MaterialBalance(points, temp, gammaG, 2e6, calculations.ZOptions{})
+	require.NoError(t, err)
+	assert.InDelta(t, giip, res.GIIP, giip*1e-3)
+	assert.InDelta(t, pzi, res.PZi, pzi*1e-4)
+	assert.InDelta(t, 1, res.R2, 1e-6)
+	require.Len(t, res.Points, 4)
+
+	// При давлении забрасывания 2 МПа Z близко к 1 - КИГ около 0.92
+	assert.InDelta(t, 1-res.PZAbandonment/pzi, res.RecoveryFactor, 1e-3)
+	assert.Greater(t, res.RecoveryFactor, 0.85)
+	assert.InDelta(t, res.Recoverable-1.5e9, res.Remaining, 1)
+}
+
+func TestMaterialBalanceErrors(t *testing.T) {
+	_, err := calculations.MaterialBalance([]calculations.SurveyPoint{{P: 20e6}}, 360, 0.6, 2e6, calculations.ZOptions{})
+	assert.Error(t, err)
+
+	// Давление растет с отбором - прямая не имеет смысла
+	rising := []calculations.SurveyPoint{{P: 20e6, Gp: 0}, {P: 21e6, Gp: 1e9}}
+	_, err = calculations.MaterialBalance(rising, 360, 0.6, 2e6, calculations.ZOptions{})
+	assert.Error(t, err)
+}
+
+func pzAt(t *testing.T, p, temp, gammaG float64) float64 {
+	t.Helper()
+	zr, err := calculations.ZFactor(p, temp, gammaG, calculations.ZOptions{})
+	require.NoError(t, err)
+	return p / zr.Z
+}
+
+// pressureForPZ - обратная задача: давление с заданным P/Z (бисекция)
+func pressureForPZ(t *testing.T, target, temp, gammaG float64) float64 {
+	lo, hi := 1e5, 60e6
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if pzAt(t, mid, temp, gammaG) < target {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
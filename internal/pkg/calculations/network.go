@@ -0,0 +1,419 @@
+package calculations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// internal/pkg/calculations/network.go
+
+const (
+	defaultNetworkTolerance  = 1e-4 // Относительная точность по дебитам
+	defaultNetworkIterations = 200
+	networkDamping           = 0.5  // Релаксация дебитов между итерациями
+	wellheadPressureTol      = 10.0 // Па
+)
+
+// Pipe - участок трубопровода системы сбора
+type Pipe struct {
+	Length    float64 // Длина, м
+	Diameter  float64 // Внутренний диаметр, м
+	Roughness float64 // Абсолютная шероховатость, м
+	Elevation float64 // Превышение конца участка над началом, м
+	Temp      float64 // Температура газа, К
+	GammaG    float64 // Относительная плотность газа
+	Mu        float64 // Вязкость газа, мПа·с (0 - квадратичный закон трения)
+}
+
+func (p Pipe) validate() error {
+	if p.Length <= 0 {
+		return errors.New("pipe length must be positive")
+	}
+	if math.Abs(p.Elevation) > p.Length {
+		return errors.New("pipe elevation change exceeds its length")
+	}
+	if p.Diameter <= 0 {
+		return errors.New("pipe diameter must be positive")
+	}
+	if p.Temp <= 0 {
+		return errors.New("temperature must be above absolute zero")
+	}
+	if p.GammaG <= 0 {
+		return errors.New("gas gravity must be positive")
+	}
+	return nil
+}
+
+// PipeInletPressure рассчитывает давление в начале участка по давлению
+// в конце pOut (Па) и расходу q (м³/сут): трение плюс вес столба газа
+// на перепаде высот. Интегрирование ведется от конца участка к началу.
+func PipeInletPressure(p Pipe, pOut, q float64, opts TraverseOptions) (float64, error) {
+	if err := p.validate(); err != nil {
+		return 0, err
+	}
+	if pOut <= 0 {
+		return 0, errors.New("outlet pressure must be positive")
+	}
+	if q < 0 {
+		return 0, errors.New("gas rate cannot be negative")
+	}
+
+	wb := Wellbore{
+		Depth:      p.Length,
+		Diameter:   p.Diameter,
+		Roughness:  p.Roughness,
+		TempHead:   p.Temp,
+		TempBottom: p.Temp,
+		GammaG:     p.GammaG,
+		Mu:         p.Mu,
+	}
+	var massRate, lambda float64
+	if q > 0 {
+		massRate = MassRate(q, p.GammaG)
+		var err error
+		lambda, err = FrictionFactor(p.Diameter, p.Roughness, Reynolds(massRate, p.Diameter, p.Mu))
+		if err != nil {
+			return 0, err
+		}
+	}
+	res, err := traverse(wb, pOut, massRate, lambda, p.Elevation/p.Length, opts)
+	if err != nil {
+		return 0, err
+	}
+	return res.Pressure, nil
+}
+
+// WellheadPressure рассчитывает буферное давление (Па), при котором
+// скважина работает с дебитом q (м³/сут): забойное давление по кривой
+// притока и подбор устьевого давления по кривой лифта
+func WellheadPressure(wb Wellbore, in Inflow, q float64, opts TraverseOptions) (float64, error) {
+	pwf, err := in.BottomholePressure(q)
+	if err != nil {
+		return 0, err
+	}
+	residual := func(pHead float64) (float64, error) {
+		res, err := FlowingBottomholePressure(wb, pHead, q, opts)
+		return res.Pressure - pwf, err
+	}
+
+	lo, hi := pStandard, pwf
+	fLo, err := residual(lo)
+	if err != nil {
+		return 0, err
+	}
+	if fLo > 0 {
+		return 0, fmt.Errorf("well cannot deliver %.0f m3/day", q)
+	}
+	// Забойное давление монотонно растет с устьевым
+	for i := 0; i < nodalMaxIterations && hi-lo > wellheadPressureTol; i++ {
+		mid := (lo + hi) / 2
+		f, err := residual(mid)
+		if err != nil {
+			return 0, err
+		}
+		if f < 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2, nil
+}
+
+// NetworkWell - скважина в узле сети сбора
+type NetworkWell struct {
+	Wellbore Wellbore
+	Inflow   Inflow
+	Rate     float64 // Дебит, заданный штуцером, м³/сут (0 - штуцер открыт полностью)
+}
+
+// NetworkNode - узел сети сбора
+type NetworkNode struct {
+	ID   int
+	Well *NetworkWell // nil - тройник или коллектор
+}
+
+// NetworkPipe - участок сети; газ течет из узла From в узел To
+type NetworkPipe struct {
+	ID   int
+	From int
+	To   int
+	Pipe Pipe
+}
+
+// Network - древовидная сеть сбора с заданным давлением в коллекторе
+type Network struct {
+	Nodes          []NetworkNode
+	Pipes          []NetworkPipe
+	Header         int     // ID узла коллектора
+	HeaderPressure float64 // Давление в коллекторе, Па
+}
+
+// NetworkOptions - параметры расчета сети
+type NetworkOptions struct {
+	Tolerance     float64 // Относительная точность по дебитам (0 - 1e-4)
+	MaxIterations int
+	Traverse      TraverseOptions
+}
+
+// NetworkNodeResult - давление и расход в узле
+type NetworkNodeResult struct {
+	ID       int     `json:"id"`
+	Pressure float64 `json:"pressure"` // Па
+	Flow     float64 `json:"flow"`     // Расход газа через узел, м³/сут
+}
+
+// NetworkPipeResult - расход и давления на концах участка
+type NetworkPipeResult struct {
+	ID     int     `json:"id"`
+	Flow   float64 `json:"flow"`    // м³/сут
+	PIn    float64 `json:"p_in"`    // Давление в начале участка, Па
+	POut   float64 `json:"p_out"`   // Давление в конце участка, Па
+	DeltaP float64 `json:"delta_p"` // Перепад давления, Па
+}
+
+// NetworkWellResult - режим работы скважины в сети
+type NetworkWellResult struct {
+	NodeID  int     `json:"node_id"`
+	Rate    float64 `json:"rate"`    // Дебит, м³/сут
+	Pbuf    float64 `json:"pbuf"`    // Буферное давление, Па
+	Pline   float64 `json:"pline"`   // Давление в шлейфе за штуцером, Па
+	Pwf     float64 `json:"pwf"`     // Забойное давление, Па
+	Flowing bool    `json:"flowing"` // false - скважина не преодолевает давление в шлейфе
+	Choked  bool    `json:"choked"`  // Дебит ограничен штуцером
+}
+
+// NetworkResult - установившийся режим сети сбора
+type NetworkResult struct {
+	HeaderPressure float64             `json:"header_pressure"`
+	TotalRate      float64             `json:"total_rate"`
+	Iterations     int                 `json:"iterations"`
+	Nodes          []NetworkNodeResult `json:"nodes"`
+	Pipes          []NetworkPipeResult `json:"pipes"`
+	Wells          []NetworkWellResult `json:"wells"`
+}
+
+// networkTopology - сеть, упорядоченная от коллектора к скважинам
+type networkTopology struct {
+	outlet map[int]int // Узел -> индекс отводящего участка
+	order  []int       // Узлы, кроме коллектора, по удаленности от коллектора
+	wells  []int       // Индексы узлов со скважинами
+}
+
+func buildTopology(n Network) (networkTopology, error) {
+	top := networkTopology{outlet: make(map[int]int, len(n.Pipes))}
+	nodes := make(map[int]bool, len(n.Nodes))
+	for i, node := range n.Nodes {
+		if nodes[node.ID] {
+			return top, fmt.Errorf("duplicate network node %d", node.ID)
+		}
+		nodes[node.ID] = true
+		if node.Well != nil {
+			if node.ID == n.Header {
+				return top, errors.New("header node cannot be a well")
+			}
+			top.wells = append(top.wells, i)
+		}
+	}
+	if !nodes[n.Header] {
+		return top, fmt.Errorf("header node %d not found", n.Header)
+	}
+
+	for i, pipe := range n.Pipes {
+		if !nodes[pipe.From] || !nodes[pipe.To] {
+			return top, fmt.Errorf("pipe %d references unknown node", pipe.ID)
+		}
+		if pipe.From == n.Header {
+			return top, fmt.Errorf("pipe %d leaves the header node", pipe.ID)
+		}
+		if _, ok := top.outlet[pipe.From]; ok {
+			return top, fmt.Errorf("node %d has more than one outlet pipe", pipe.From)
+		}
+		top.outlet[pipe.From] = i
+	}
+
+	// Удаленность узла от коллектора; заодно проверка на циклы
+	depth := make(map[int]int, len(n.Nodes))
+	depth[n.Header] = 0
+	var walk func(id, hops int) (int, error)
+	walk = func(id, hops int) (int, error) {
+		if d, ok := depth[id]; ok {
+			return d, nil
+		}
+		if hops > len(n.Nodes) {
+			return 0, errors.New("network contains a loop")
+		}
+		i, ok := top.outlet[id]
+		if !ok {
+			return 0, fmt.Errorf("node %d is not connected to the header", id)
+		}
+		d, err := walk(n.Pipes[i].To, hops+1)
+		if err != nil {
+			return 0, err
+		}
+		depth[id] = d + 1
+		return d + 1, nil
+	}
+	for _, node := range n.Nodes {
+		if node.ID == n.Header {
+			continue
+		}
+		if _, err := walk(node.ID, 0); err != nil {
+			return top, err
+		}
+		top.order = append(top.order, node.ID)
+	}
+	sort.SliceStable(top.order, func(i, j int) bool {
+		return depth[top.order[i]] < depth[top.order[j]]
+	})
+	return top, nil
+}
+
+// SolveNetwork рассчитывает установившийся режим сети сбора: распределение
+// дебитов между скважинами и давления в узлах при заданном давлении
+// в коллекторе. Дебиты уточняются последовательными приближениями:
+// по дебитам находятся расходы и давления в сети, по давлению в шлейфе -
+// новый дебит каждой скважины (узловой анализ на устье).
+func SolveNetwork(n Network, opts NetworkOptions) (NetworkResult, error) {
+	if n.HeaderPressure <= 0 {
+		return NetworkResult{}, errors.New("header pressure must be positive")
+	}
+	top, err := buildTopology(n)
+	if err != nil {
+		return NetworkResult{}, err
+	}
+	for _, pipe := range n.Pipes {
+		if err := pipe.Pipe.validate(); err != nil {
+			return NetworkResult{}, fmt.Errorf("pipe %d: %w", pipe.ID, err)
+		}
+	}
+	tol := opts.Tolerance
+	if tol <= 0 {
+		tol = defaultNetworkTolerance
+	}
+	maxIter := opts.MaxIterations
+	if maxIter <= 0 {
+		maxIter = defaultNetworkIterations
+	}
+
+	// Дебит скважины при давлении pline в шлейфе: не больше заданного штуцером
+	wellRate := func(w *NetworkWell, pline float64) (float64, error) {
+		res, err := SolveNodal(w.Wellbore, w.Inflow, pline, NodalOptions{Points: 2, Traverse: opts.Traverse})
+		if err != nil {
+			return 0, err
+		}
+		if w.Rate > 0 && w.Rate < res.Rate {
+			return w.Rate, nil
+		}
+		return res.Rate, nil
+	}
+
+	flows := make([]float64, len(n.Pipes))
+	pressures := make(map[int]float64, len(n.Nodes))
+	solvePressures := func(rates []float64) error {
+		for i := range flows {
+			flows[i] = 0
+		}
+		for k, idx := range top.wells {
+			for id := n.Nodes[idx].ID; id != n.Header; id = n.Pipes[top.outlet[id]].To {
+				flows[top.outlet[id]] += rates[k]
+			}
+		}
+		pressures[n.Header] = n.HeaderPressure
+		for _, id := range top.order {
+			i := top.outlet[id]
+			p, err := PipeInletPressure(n.Pipes[i].Pipe, pressures[n.Pipes[i].To], flows[i], opts.Traverse)
+			if err != nil {
+				return fmt.Errorf("pipe %d: %w", n.Pipes[i].ID, err)
+			}
+			pressures[id] = p
+		}
+		return nil
+	}
+
+	// Начальное приближение - дебиты при давлении коллектора в шлейфах
+	rates := make([]float64, len(top.wells))
+	for k, idx := range top.wells {
+		if rates[k], err = wellRate(n.Nodes[idx].Well, n.HeaderPressure); err != nil {
+			return NetworkResult{}, fmt.Errorf("node %d: %w", n.Nodes[idx].ID, err)
+		}
+	}
+
+	res := NetworkResult{HeaderPressure: n.HeaderPressure}
+	converged := false
+	for res.Iterations < maxIter && !converged {
+		res.Iterations++
+		if err := solvePressures(rates); err != nil {
+			return NetworkResult{}, err
+		}
+		var total, change float64
+		next := make([]float64, len(rates))
+		for k, idx := range top.wells {
+			node := n.Nodes[idx]
+			if next[k], err = wellRate(node.Well, pressures[node.ID]); err != nil {
+				return NetworkResult{}, fmt.Errorf("node %d: %w", node.ID, err)
+			}
+			total += next[k]
+			change = math.Max(change, math.Abs(next[k]-rates[k]))
+		}
+		converged = change <= tol*math.Max(total, 1)
+		for k := range rates {
+			rates[k] += networkDamping * (next[k] - rates[k])
+		}
+	}
+	if !converged {
+		return NetworkResult{}, errors.New("network solution did not converge")
+	}
+	if err := solvePressures(rates); err != nil {
+		return NetworkResult{}, err
+	}
+
+	for k, idx := range top.wells {
+		node := n.Nodes[idx]
+		w := node.Well
+		wr := NetworkWellResult{
+			NodeID:  node.ID,
+			Rate:    math.Round(rates[k]),
+			Pline:   pressures[node.ID],
+			Pbuf:    pressures[node.ID],
+			Flowing: rates[k] > 0,
+		}
+		if wr.Flowing {
+			if w.Rate > 0 && math.Abs(rates[k]-w.Rate) <= tol*w.Rate {
+				// Избыток давления срабатывается на штуцере
+				wr.Choked = true
+				if wr.Pbuf, err = WellheadPressure(w.Wellbore, w.Inflow, rates[k], opts.Traverse); err != nil {
+					return NetworkResult{}, fmt.Errorf("node %d: %w", node.ID, err)
+				}
+			}
+			if wr.Pwf, err = w.Inflow.BottomholePressure(rates[k]); err != nil {
+				return NetworkResult{}, fmt.Errorf("node %d: %w", node.ID, err)
+			}
+		}
+		res.TotalRate += wr.Rate
+		res.Wells = append(res.Wells, wr)
+	}
+
+	nodeFlow := make(map[int]float64, len(n.Nodes))
+	for i, pipe := range n.Pipes {
+		nodeFlow[pipe.From] = flows[i]
+		res.Pipes = append(res.Pipes, NetworkPipeResult{
+			ID:     pipe.ID,
+			Flow:   math.Round(flows[i]),
+			PIn:    pressures[pipe.From],
+			POut:   pressures[pipe.To],
+			DeltaP: pressures[pipe.From] - pressures[pipe.To],
+		})
+	}
+	nodeFlow[n.Header] = res.TotalRate
+	for _, node := range n.Nodes {
+		res.Nodes = append(res.Nodes, NetworkNodeResult{
+			ID:       node.ID,
+			Pressure: pressures[node.ID],
+			Flow:     math.Round(nodeFlow[node.ID]),
+		})
+	}
+	return res, nil
+}
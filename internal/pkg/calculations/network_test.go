@@ -0,0 +1,136 @@
+// pkg/calculations/network_test.go
+package calculations_test
+
+import (
+	"gas_wells/internal/pkg/calculations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPipe = calculations.Pipe{
+	Length:    2000,
+	Diameter:  0.1,
+	Roughness: 5e-5,
+	Temp:      285,
+	GammaG:    0.6,
+}
+
+func TestPipeInletPressure(t *testing.T) {
+	opts := calculations.TraverseOptions{}
+
+	p, err := calculations.PipeInletPressure(testPipe, 5e6, 0, opts)
+	require.NoError(t, err)
+	assert.InDelta(t, 5e6, p, 1)
+
+	// Подъем на 100 м без расхода - вес столба газа ρgh, ρ ≈ 40 кг/м³ при 5 МПа
+	uphill := testPipe
+	uphill.Elevation = 100
+	pUp, err := calculations.PipeInletPressure(uphill, 5e6, 0, opts)
+	require.NoError(t, err)
+	assert.InDelta(t, 5e6+40*9.8*100, pUp, 5e3)
+
+	flowing, err := calculations.PipeInletPressure(testPipe, 5e6, 500e3, opts)
+	require.NoError(t, err)
+	assert.Greater(t, flowing, 5e6)
+
+	downhill := testPipe
+	downhill.Elevation = -100
+	pDown, err := calculations.PipeInletPressure(downhill, 5e6, 500e3, opts)
+	require.NoError(t, err)
+	assert.Less(t, pDown, flowing)
+
+	bad := testPipe
+	bad.Elevation = 3000
+	_, err = calculations.PipeInletPressure(bad, 5e6, 0, opts)
+	assert.Error(t, err)
+}
+
+func TestWellheadPressure(t *testing.T) {
+	in := calculations.Inflow{A: 0.5, B: 0.001, Ppl: 20e6}
+	pbuf, err := calculations.WellheadPressure(testWellbore, in, 200e3, calculations.TraverseOptions{})
+	require.NoError(t, err)
+
+	res, err := calculations.SolveNodal(testWellbore, in, pbuf, calculations.NodalOptions{Points: 2})
+	require.NoError(t, err)
+	assert.InDelta(t, 200e3, res.Rate, 100)
+
+	_, err = calculations.WellheadPressure(testWellbore, in, 1e9, calculations.TraverseOptions{})
+	assert.Error(t, err)
+}
+
+// testNetwork - две скважины со шлейфами в общий коллектор куста
+func testNetwork(rate1 float64) calculations.Network {
+	well := func(rate float64) *calculations.NetworkWell {
+		return &calculations.NetworkWell{
+			Wellbore: testWellbore,
+			Inflow:   calculations.Inflow{A: 0.5, B: 0.001, Ppl: 20e6},
+			Rate:     rate,
+		}
+	}
+	return calculations.Network{
+		Nodes: []calculations.NetworkNode{
+			{ID: 1},
+			{ID: 2},
+			{ID: 3, Well: well(rate1)},
+			{ID: 4, Well: well(0)},
+		},
+		Pipes: []calculations.NetworkPipe{
+			{ID: 10, From: 3, To: 2, Pipe: testPipe},
+			{ID: 11, From: 4, To: 2, Pipe: testPipe},
+			{ID: 12, From: 2, To: 1, Pipe: testPipe},
+		},
+		Header:         1,
+		HeaderPressure: 5e6,
+	}
+}
+
+func TestSolveNetwork(t *testing.T) {
+	base, err := calculations.SolveNetwork(testNetwork(0), calculations.NetworkOptions{})
+	require.NoError(t, err)
+	require.Len(t, base.Wells, 2)
+	require.Len(t, base.Pipes, 3)
+
+	w1, w2 := base.Wells[0], base.Wells[1]
+	assert.True(t, w1.Flowing)
+	assert.InDelta(t, w1.Rate, w2.Rate, w1.Rate*1e-3)
+	assert.InDelta(t, w1.Rate+w2.Rate, base.TotalRate, 1)
+	assert.Equal(t, w1.Pline, w1.Pbuf)
+	assert.Greater(t, w1.Pbuf, base.HeaderPressure)
+	assert.InDelta(t, base.TotalRate, base.Pipes[2].Flow, 2)
+
+	// Рабочая точка скважины согласована с давлением в шлейфе
+	nodal, err := calculations.SolveNodal(testWellbore, calculations.Inflow{A: 0.5, B: 0.001, Ppl: 20e6},
+		w1.Pbuf, calculations.NodalOptions{Points: 2})
+	require.NoError(t, err)
+	assert.InDelta(t, nodal.Rate, w1.Rate, w1.Rate*1e-3)
+
+	// Прикрытие штуцера первой скважины снижает противодавление на соседнюю
+	choked, err := calculations.SolveNetwork(testNetwork(w1.Rate/2), calculations.NetworkOptions{})
+	require.NoError(t, err)
+	c1, c2 := choked.Wells[0], choked.Wells[1]
+	assert.True(t, c1.Choked)
+	assert.InDelta(t, w1.Rate/2, c1.Rate, 1)
+	assert.Greater(t, c1.Pbuf, c1.Pline)
+	assert.False(t, c2.Choked)
+	assert.Less(t, c2.Pbuf, w2.Pbuf)
+	assert.Greater(t, c2.Rate, w2.Rate)
+}
+
+func TestSolveNetworkTopology(t *testing.T) {
+	n := testNetwork(0)
+	n.Header = 99
+	_, err := calculations.SolveNetwork(n, calculations.NetworkOptions{})
+	assert.Error(t, err)
+
+	n = testNetwork(0)
+	n.Pipes[2] = calculations.NetworkPipe{ID: 12, From: 2, To: 3, Pipe: testPipe}
+	_, err = calculations.SolveNetwork(n, calculations.NetworkOptions{})
+	assert.Error(t, err)
+
+	n = testNetwork(0)
+	n.Pipes = n.Pipes[:2]
+	_, err = calculations.SolveNetwork(n, calculations.NetworkOptions{})
+	assert.Error(t, err)
+}
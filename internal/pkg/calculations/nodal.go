@@ -0,0 +1,134 @@
+package calculations
+
+import (
+	"errors"
+	"math"
+)
+
+// internal/pkg/calculations/nodal.go
+
+// MaxCurvePoints - наибольшее число точек расчетной кривой;
+// большее значение ограничивается им
+const MaxCurvePoints = 500
+
+const (
+	defaultNodalPoints = 30
+	nodalMaxIterations = 100
+	nodalRateTolerance = 1.0 // м³/сут
+)
+
+// NodalPoint - точка кривой притока или лифта
+type NodalPoint struct {
+	Q   float64 `json:"q"`   // Дебит, м³/сут
+	Pwf float64 `json:"pwf"` // Забойное давление, Па
+}
+
+// NodalOptions - параметры узлового анализа
+type NodalOptions struct {
+	Points   int // Число точек на кривых
+	Traverse TraverseOptions
+}
+
+// NodalResult - кривые притока (IPR) и лифта (TPC) и рабочая точка
+type NodalResult struct {
+	Pbuf    float64      `json:"pbuf"`
+	IPR     []NodalPoint `json:"ipr"`
+	TPC     []NodalPoint `json:"tpc"`
+	Flowing bool         `json:"flowing"` // false - скважина не фонтанирует при заданном Pbuf
+	Rate    float64      `json:"rate"`    // Дебит в рабочей точке, м³/сут
+	Pwf     float64      `json:"pwf"`     // Забойное давление в рабочей точке, Па
+}
+
+// SolveNodal находит рабочую точку системы "пласт - НКТ" с узлом на забое:
+// пересечение кривой притока in с кривой лифта для буферного давления pbuf
+func SolveNodal(wb Wellbore, in Inflow, pbuf float64, opts NodalOptions) (NodalResult, error) {
+	if err := in.validate(); err != nil {
+		return NodalResult{}, err
+	}
+	aof, err := in.AbsoluteOpenFlow()
+	if err != nil {
+		return NodalResult{}, err
+	}
+
+	tpc := func(q float64) (float64, error) {
+		res, err := FlowingBottomholePressure(wb, pbuf, q, opts.Traverse)
+		return res.Pressure, err
+	}
+	// Невязка: давление, требуемое лифтом, минус давление, обеспечиваемое пластом
+	residual := func(q float64) (float64, error) {
+		pTPC, err := tpc(q)
+		if err != nil {
+			return 0, err
+		}
+		pIPR, err := in.BottomholePressure(q)
+		if err != nil {
+			return 0, err
+		}
+		return pTPC - pIPR, nil
+	}
+
+	points := opts.Points
+	if points < 2 {
+		points = defaultNodalPoints
+	}
+	points = min(points, MaxCurvePoints)
+	res := NodalResult{
+		Pbuf: pbuf,
+		IPR:  make([]NodalPoint, 0, points),
+		TPC:  make([]NodalPoint, 0, points),
+	}
+	for i := 0; i < points; i++ {
+		q := aof * float64(i) / float64(points-1)
+		pIPR, err := in.BottomholePressure(q)
+		if err != nil {
+			// На границе AOF возможна погрешность округления
+			pIPR = pStandard
+		}
+		pTPC, err := tpc(q)
+		if err != nil {
+			return NodalResult{}, err
+		}
+		res.IPR = append(res.IPR, NodalPoint{Q: q, Pwf: pIPR})
+		res.TPC = append(res.TPC, NodalPoint{Q: q, Pwf: pTPC})
+	}
+
+	lo, hi := 0.0, aof*(1-1e-9)
+	fLo, err := residual(lo)
+	if err != nil {
+		return NodalResult{}, err
+	}
+	if fLo >= 0 {
+		// Столб газа при Pbuf уравновешивает пластовое давление - притока нет
+		return res, nil
+	}
+	fHi, err := residual(hi)
+	if err != nil {
+		return NodalResult{}, err
+	}
+	if fHi <= 0 {
+		return NodalResult{}, errors.New("tubing performance curve does not intersect inflow curve")
+	}
+
+	// Метод бисекции: кривая лифта для газа монотонна по дебиту
+	for i := 0; i < nodalMaxIterations && hi-lo > nodalRateTolerance; i++ {
+		mid := (lo + hi) / 2
+		f, err := residual(mid)
+		if err != nil {
+			return NodalResult{}, err
+		}
+		if f < 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	res.Flowing = true
+	res.Rate = (lo + hi) / 2
+	res.Pwf, err = in.BottomholePressure(res.Rate)
+	if err != nil {
+		return NodalResult{}, err
+	}
+	res.Rate = math.Round(res.Rate)
+	return res, nil
+}
@@ -0,0 +1,48 @@
+// pkg/calculations/nodal_test.go
+package calculations_test
+
+import (
+	"gas_wells/internal/pkg/calculations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolveNodal(t *testing.T) {
+	in := calculations.Inflow{A: 0.5, B: 0.001, Ppl: 20e6}
+
+	res, err := calculations.SolveNodal(testWellbore, in, 8e6, calculations.NodalOptions{Points: 10})
+	require.NoError(t, err)
+	require.True(t, res.Flowing)
+	assert.Len(t, res.IPR, 10)
+	assert.Len(t, res.TPC, 10)
+
+	// В рабочей точке давление лифта совпадает с давлением притока
+	lift, err := calculations.FlowingBottomholePressure(testWellbore, 8e6, res.Rate, calculations.TraverseOptions{})
+	require.NoError(t, err)
+	assert.InDelta(t, res.Pwf, lift.Pressure, 2e3)
+
+	// Снижение буферного давления увеличивает дебит
+	lower, err := calculations.SolveNodal(testWellbore, in, 5e6, calculations.NodalOptions{})
+	require.NoError(t, err)
+	assert.Greater(t, lower.Rate, res.Rate)
+
+	// Буферное давление выше пластового - притока нет
+	none, err := calculations.SolveNodal(testWellbore, in, 19e6, calculations.NodalOptions{})
+	require.NoError(t, err)
+	assert.False(t, none.Flowing)
+}
+
+func TestCurvePointsLimit(t *testing.T) {
+	in := calculations.Inflow{A: 0.5, B: 0.001, Ppl: 20e6}
+
+	res, err := calculations.SolveNodal(testWellbore, in, 8e6, calculations.NodalOptions{Points: 1e6})
+	require.NoError(t, err)
+	assert.Len(t, res.IPR, calculations.MaxCurvePoints)
+	assert.Len(t, res.TPC, calculations.MaxCurvePoints)
+
+	curve, err := in.Curve(0, 0, 1e6)
+	require.NoError(t, err)
+	assert.Len(t, curve, calculations.MaxCurvePoints)
+}
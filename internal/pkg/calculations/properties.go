@@ -0,0 +1,78 @@
+package calculations
+
+import (
+	"errors"
+	"math"
+)
+
+// internal/pkg/calculations/properties.go
+
+// GasProperties - свойства газа при заданных давлении и температуре
+type GasProperties struct {
+	Pressure        float64 `json:"pressure"`        // Па
+	Temp            float64 `json:"temp"`            // К
+	Z               float64 `json:"z"`               // Коэффициент сверхсжимаемости
+	Density         float64 `json:"density"`         // Плотность, кг/м³
+	Viscosity       float64 `json:"viscosity"`       // Динамическая вязкость, мПа·с
+	Bg              float64 `json:"bg"`              // Объемный коэффициент, м³/м³ ст. усл.
+	Compressibility float64 `json:"compressibility"` // Изотермическая сжимаемость, 1/Па
+}
+
+// GasDensity - плотность газа (кг/м³) при давлении p (Па), температуре t (К)
+// и коэффициенте сверхсжимаемости z
+func GasDensity(p, t, gammaG, z float64) float64 {
+	return p * gammaG * airMolar / (z * gasConstant * t)
+}
+
+// GasFVF - объемный коэффициент газа Bg (м³/м³ ст. усл.)
+func GasFVF(p, t, z float64) float64 {
+	return pStandard * z * t / (p * tStandard)
+}
+
+// LeeGonzalezEakin - вязкость газа (мПа·с) по корреляции Ли-Гонсалеса-Икина
+// по температуре t (К), плотности газа density (кг/м³) и относительной плотности gammaG
+func LeeGonzalezEakin(t, density, gammaG float64) float64 {
+	m := gammaG * airMolar * 1e3 // г/моль
+	tr := t / kelvinPerRankine   // °R
+	rho := density * 1e-3        // г/см³
+
+	k := (9.4 + 0.02*m) * math.Pow(tr, 1.5) / (209 + 19*m + tr)
+	x := 3.5 + 986/tr + 0.01*m
+	y := 2.4 - 0.2*x
+	return 1e-4 * k * math.Exp(x*math.Pow(rho, y))
+}
+
+// GasPropertiesAt рассчитывает свойства газа при давлении p (Па) и температуре t (К)
+func GasPropertiesAt(p, t, gammaG float64, opts ZOptions) (GasProperties, error) {
+	if p <= 0 {
+		return GasProperties{}, errors.New("pressure must be positive")
+	}
+
+	zr, err := ZFactor(p, t, gammaG, opts)
+	if err != nil {
+		return GasProperties{}, err
+	}
+	density := GasDensity(p, t, gammaG, zr.Z)
+
+	// cg = 1/p − (1/Z)·(dZ/dp), производная - центральной разностью
+	dp := p * 1e-4
+	zHi, err := ZFactor(p+dp, t, gammaG, opts)
+	if err != nil {
+		return GasProperties{}, err
+	}
+	zLo, err := ZFactor(p-dp, t, gammaG, opts)
+	if err != nil {
+		return GasProperties{}, err
+	}
+	dzdp := (zHi.Z - zLo.Z) / (2 * dp)
+
+	return GasProperties{
+		Pressure:        p,
+		Temp:            t,
+		Z:               zr.Z,
+		Density:         density,
+		Viscosity:       LeeGonzalezEakin(t, density, gammaG),
+		Bg:              GasFVF(p, t, zr.Z),
+		Compressibility: 1/p - dzdp/zr.Z,
+	}, nil
+}
@@ -0,0 +1,28 @@
+// pkg/calculations/properties_test.go
+package calculations_test
+
+import (
+	"gas_wells/internal/pkg/calculations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasPropertiesAt(t *testing.T) {
+	// 10 МПа, 350 К, gammaG = 0.65
+	props, err := calculations.GasPropertiesAt(10e6, 350, 0.65, calculations.ZOptions{})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.88, props.Z, 0.03)
+	assert.InDelta(t, 75, props.Density, 5)
+	assert.InDelta(t, 0.016, props.Viscosity, 0.002)
+	assert.InDelta(t, 0.0104, props.Bg, 0.001)
+	// Для реального газа cg близко к 1/p
+	assert.InDelta(t, 1/10e6, props.Compressibility, 0.3/10e6)
+
+	// При атмосферном давлении вязкость метана около 0.011 мПа·с
+	props, err = calculations.GasPropertiesAt(101325, 293.15, 0.554, calculations.ZOptions{})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.011, props.Viscosity, 0.001)
+	assert.InDelta(t, 1, props.Bg, 0.01)
+}
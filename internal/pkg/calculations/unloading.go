@@ -0,0 +1,111 @@
+package calculations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// internal/pkg/calculations/unloading.go
+
+// UnloadingModel - капельная модель выноса жидкости с забоя
+type UnloadingModel string
+
+const (
+	UnloadingTurner  UnloadingModel = "turner"  // Тернер: сферическая капля, +20% к скорости
+	UnloadingColeman UnloadingModel = "coleman" // Коулман: сферическая капля без поправки
+	UnloadingLi      UnloadingModel = "li"      // Ли: сплющенная капля, Cd = 1
+
+	DefaultUnloadingModel = UnloadingTurner
+)
+
+// Значения по умолчанию для пластовой воды
+const (
+	defaultLiquidDensity  = 1000.0 // кг/м³
+	defaultSurfaceTension = 0.06   // Н/м
+)
+
+// Коэффициенты формулы v = k·[σ(ρж − ρг)/ρг²]^0.25 для σ в Н/м, ρ в кг/м³,
+// v в м/с. Промысловые значения 1.912/1.593/0.7241 относятся к σ в дин/см,
+// ρ в фунт/фут³ и v в фут/с и к СИ неприменимы.
+var unloadingCoefficients = map[UnloadingModel]float64{
+	UnloadingTurner:  6.6, // 5.5 · 1.2
+	UnloadingColeman: 5.5,
+	UnloadingLi:      2.5,
+}
+
+// ParseUnloadingModel возвращает модель по имени; пустая строка - модель по умолчанию
+func ParseUnloadingModel(s string) (UnloadingModel, error) {
+	m := UnloadingModel(s)
+	if m == "" {
+		return DefaultUnloadingModel, nil
+	}
+	if _, ok := unloadingCoefficients[m]; !ok {
+		return "", fmt.Errorf("unknown liquid unloading model %q", s)
+	}
+	return m, nil
+}
+
+// UnloadingInput - условия в расчетном сечении (обычно устье)
+type UnloadingInput struct {
+	Pressure       float64 // Давление, Па
+	Temp           float64 // Температура, К
+	Diameter       float64 // Внутренний диаметр НКТ, м
+	GammaG         float64 // Относительная плотность газа
+	LiquidDensity  float64 // Плотность жидкости, кг/м³ (0 - вода)
+	SurfaceTension float64 // Поверхностное натяжение, Н/м (0 - вода)
+	Z              ZOptions
+}
+
+// UnloadingResult - критическая скорость и дебит выноса жидкости
+type UnloadingResult struct {
+	Model      UnloadingModel `json:"model"`
+	Velocity   float64        `json:"velocity"`    // Критическая скорость газа, м/с
+	Rate       float64        `json:"rate"`        // Критический дебит, м³/сут при ст. усл.
+	GasDensity float64        `json:"gas_density"` // Плотность газа, кг/м³
+	Z          float64        `json:"z"`
+}
+
+// CriticalUnloadingRate рассчитывает минимальный дебит газа,
+// обеспечивающий вынос капель жидкости с забоя
+func CriticalUnloadingRate(in UnloadingInput, model UnloadingModel) (UnloadingResult, error) {
+	if model == "" {
+		model = DefaultUnloadingModel
+	}
+	k, ok := unloadingCoefficients[model]
+	if !ok {
+		return UnloadingResult{}, fmt.Errorf("unknown liquid unloading model %q", model)
+	}
+	if in.Diameter <= 0 {
+		return UnloadingResult{}, errors.New("tubing diameter must be positive")
+	}
+	if in.LiquidDensity <= 0 {
+		in.LiquidDensity = defaultLiquidDensity
+	}
+	if in.SurfaceTension <= 0 {
+		in.SurfaceTension = defaultSurfaceTension
+	}
+
+	zr, err := ZFactor(in.Pressure, in.Temp, in.GammaG, in.Z)
+	if err != nil {
+		return UnloadingResult{}, err
+	}
+
+	rhoG := GasDensity(in.Pressure, in.Temp, in.GammaG, zr.Z)
+	if rhoG >= in.LiquidDensity {
+		return UnloadingResult{}, errors.New("gas density exceeds liquid density")
+	}
+
+	v := k * math.Pow(in.SurfaceTension*(in.LiquidDensity-rhoG)/(rhoG*rhoG), 0.25)
+	area := math.Pi * in.Diameter * in.Diameter / 4
+	// Приведение объемного расхода к стандартным условиям
+	rate := v * area * secPerDay * (in.Pressure / pStandard) * (tStandard / (zr.Z * in.Temp))
+
+	return UnloadingResult{
+		Model:      model,
+		Velocity:   v,
+		Rate:       rate,
+		GasDensity: rhoG,
+		Z:          zr.Z,
+	}, nil
+}
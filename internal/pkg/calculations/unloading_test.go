@@ -0,0 +1,38 @@
+// pkg/calculations/unloading_test.go
+package calculations_test
+
+import (
+	"gas_wells/internal/pkg/calculations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCriticalUnloadingRate(t *testing.T) {
+	in := calculations.UnloadingInput{
+		Pressure: 5e6,
+		Temp:     290,
+		Diameter: 0.062,
+		GammaG:   0.6,
+	}
+
+	turner, err := calculations.CriticalUnloadingRate(in, calculations.UnloadingTurner)
+	require.NoError(t, err)
+	// ≈ 41 тыс. м³/сут для НКТ 73 мм (62 мм внутр.) при 5 МПа, скорость ≈ 2.9 м/с
+	assert.InDelta(t, 41e3, turner.Rate, 3e3)
+	assert.InDelta(t, 2.9, turner.Velocity, 0.2)
+	assert.Equal(t, calculations.UnloadingTurner, turner.Model)
+
+	coleman, err := calculations.CriticalUnloadingRate(in, calculations.UnloadingColeman)
+	require.NoError(t, err)
+	li, err := calculations.CriticalUnloadingRate(in, calculations.UnloadingLi)
+	require.NoError(t, err)
+
+	// Тернер консервативнее Коулмана, модель Ли дает наименьший дебит
+	assert.InDelta(t, 1.2, turner.Rate/coleman.Rate, 0.01)
+	assert.Less(t, li.Rate, coleman.Rate)
+
+	_, err = calculations.CriticalUnloadingRate(in, "unknown")
+	assert.Error(t, err)
+}
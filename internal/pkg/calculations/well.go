@@ -6,9 +6,16 @@ import (
 )
 
 // internal/pkg/calculations/well.go
+
+// ProcessWellData возвращает коэффициент сверхсжимаемости газа
+// при устьевых условиях (Pbuf, TempUst) методом по умолчанию
 func ProcessWellData(well entity.Well) (float64, error) {
 	if well.Pbuf <= 0 {
 		return 0, errors.New("pressure must be positive")
 	}
-	return well.Pbuf * well.Temp * 0.85, nil
+	res, err := ZFactor(well.Pbuf, well.TempUst, well.GammaG, ZOptions{})
+	if err != nil {
+		return 0, err
+	}
+	return res.Z, nil
 }
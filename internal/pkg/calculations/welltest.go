@@ -0,0 +1,85 @@
+package calculations
+
+import (
+	"errors"
+	"math"
+)
+
+// internal/pkg/calculations/welltest.go
+
+// TestPoint - режим исследования: дебит (м³/сут), забойное и пластовое давление (Па)
+type TestPoint struct {
+	Q   float64
+	Pz  float64
+	Ppl float64
+}
+
+// InflowFit - коэффициенты A и B, найденные по результатам исследования
+type InflowFit struct {
+	A        float64 `json:"a"`
+	B        float64 `json:"b"`
+	R2       float64 `json:"r2"`        // Коэффициент детерминации
+	StdError float64 `json:"std_error"` // Стандартная ошибка регрессии, МПа²·сут/тыс.м³
+	Points   int     `json:"points"`
+	Valid    bool    `json:"valid"` // A > 0 и B >= 0
+}
+
+// FitInflow находит коэффициенты двучленного уравнения притока методом
+// наименьших квадратов по линеаризованной форме (Pпл² − Pз²)/Q = A + B·Q
+func FitInflow(points []TestPoint) (InflowFit, error) {
+	if len(points) < 2 {
+		return InflowFit{}, errors.New("at least two test points are required")
+	}
+
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		if p.Q <= 0 {
+			return InflowFit{}, errors.New("test rate must be positive")
+		}
+		if p.Pz <= 0 || p.Ppl <= p.Pz {
+			return InflowFit{}, errors.New("test pressures must satisfy 0 < Pz < Ppl")
+		}
+		q := p.Q / m3PerThousand
+		ppl := p.Ppl / paPerMPa
+		pz := p.Pz / paPerMPa
+		xs[i] = q
+		ys[i] = (ppl*ppl - pz*pz) / q
+	}
+
+	n := float64(len(points))
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	den := n*sxx - sx*sx
+	if den == 0 {
+		return InflowFit{}, errors.New("test rates must differ")
+	}
+
+	fit := InflowFit{Points: len(points)}
+	fit.B = (n*sxy - sx*sy) / den
+	fit.A = (sy - fit.B*sx) / n
+
+	meanY := sy / n
+	var ssRes, ssTot float64
+	for i := range xs {
+		r := ys[i] - (fit.A + fit.B*xs[i])
+		ssRes += r * r
+		ssTot += (ys[i] - meanY) * (ys[i] - meanY)
+	}
+	if ssTot > 0 {
+		fit.R2 = 1 - ssRes/ssTot
+	} else {
+		fit.R2 = 1
+	}
+	if len(points) > 2 {
+		fit.StdError = math.Sqrt(ssRes / (n - 2))
+	}
+	fit.Valid = fit.A > 0 && fit.B >= 0
+
+	return fit, nil
+}
@@ -0,0 +1,264 @@
+package calculations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// internal/pkg/calculations/zfactor.go
+
+// Коэффициенты перевода единиц: корреляции опубликованы в промысловых
+// единицах (psia, °R), а в entity.Well давление хранится в Па, температура в К.
+const (
+	paPerPsi         = 6894.757
+	kelvinPerRankine = 5.0 / 9.0
+)
+
+// ErrNotConverged возвращается, когда итерационный метод не сошелся
+var ErrNotConverged = errors.New("z-factor iteration did not converge")
+
+// ZMethod - метод расчета коэффициента сверхсжимаемости
+type ZMethod string
+
+const (
+	ZHallYarborough     ZMethod = "hall-yarborough"
+	ZDranchukAbouKassem ZMethod = "dranchuk-abou-kassem"
+	ZPapay              ZMethod = "papay"
+
+	DefaultZMethod = ZDranchukAbouKassem
+)
+
+const (
+	defaultZTolerance     = 1e-10
+	defaultZMaxIterations = 100
+)
+
+// ParseZMethod возвращает метод по его имени; пустая строка - метод по умолчанию
+func ParseZMethod(s string) (ZMethod, error) {
+	switch m := ZMethod(s); m {
+	case "":
+		return DefaultZMethod, nil
+	case ZHallYarborough, ZDranchukAbouKassem, ZPapay:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown z-factor method %q", s)
+}
+
+// CriticalCorrelation - корреляция для псевдокритических свойств газа
+type CriticalCorrelation string
+
+const (
+	CriticalSutton   CriticalCorrelation = "sutton"
+	CriticalStanding CriticalCorrelation = "standing"
+)
+
+// PseudoCritical - псевдокритические давление (Па) и температура (К)
+type PseudoCritical struct {
+	Ppc float64 `json:"ppc"`
+	Tpc float64 `json:"tpc"`
+}
+
+// PseudoCriticalProperties рассчитывает псевдокритические свойства газа
+// по относительной плотности gammaG (по воздуху)
+func PseudoCriticalProperties(gammaG float64, corr CriticalCorrelation) (PseudoCritical, error) {
+	if gammaG <= 0 {
+		return PseudoCritical{}, errors.New("gas gravity must be positive")
+	}
+
+	var ppc, tpc float64 // psia, °R
+	switch corr {
+	case CriticalSutton, "":
+		ppc = 756.8 - 131.0*gammaG - 3.6*gammaG*gammaG
+		tpc = 169.2 + 349.5*gammaG - 74.0*gammaG*gammaG
+	case CriticalStanding:
+		ppc = 677.0 + 15.0*gammaG - 37.5*gammaG*gammaG
+		tpc = 168.0 + 325.0*gammaG - 12.5*gammaG*gammaG
+	default:
+		return PseudoCritical{}, fmt.Errorf("unknown pseudo-critical correlation %q", corr)
+	}
+
+	return PseudoCritical{Ppc: ppc * paPerPsi, Tpc: tpc * kelvinPerRankine}, nil
+}
+
+// ZOptions - параметры расчета коэффициента сверхсжимаемости.
+// Нулевые значения заменяются значениями по умолчанию.
+type ZOptions struct {
+	Method        ZMethod
+	Correlation   CriticalCorrelation
+	Tolerance     float64
+	MaxIterations int
+}
+
+func (o ZOptions) withDefaults() ZOptions {
+	if o.Method == "" {
+		o.Method = DefaultZMethod
+	}
+	if o.Correlation == "" {
+		o.Correlation = CriticalSutton
+	}
+	if o.Tolerance <= 0 {
+		o.Tolerance = defaultZTolerance
+	}
+	if o.MaxIterations <= 0 {
+		o.MaxIterations = defaultZMaxIterations
+	}
+	return o
+}
+
+// ZResult - результат расчета Z с диагностикой сходимости
+type ZResult struct {
+	Z          float64 `json:"z"`
+	Method     ZMethod `json:"method"`
+	Ppr        float64 `json:"ppr"`
+	Tpr        float64 `json:"tpr"`
+	Iterations int     `json:"iterations"`
+	Residual   float64 `json:"residual"`
+	Converged  bool    `json:"converged"`
+}
+
+// ZFactor рассчитывает коэффициент сверхсжимаемости газа при давлении p (Па)
+// и температуре t (К) для газа относительной плотности gammaG
+func ZFactor(p, t, gammaG float64, opts ZOptions) (ZResult, error) {
+	if p <= 0 {
+		return ZResult{}, errors.New("pressure must be positive")
+	}
+	if t <= 0 {
+		return ZResult{}, errors.New("temperature must be above absolute zero")
+	}
+
+	opts = opts.withDefaults()
+	pc, err := PseudoCriticalProperties(gammaG, opts.Correlation)
+	if err != nil {
+		return ZResult{}, err
+	}
+
+	return ZFactorReduced(p/pc.Ppc, t/pc.Tpc, opts)
+}
+
+// ZFactorReduced рассчитывает Z по приведенным давлению и температуре
+func ZFactorReduced(ppr, tpr float64, opts ZOptions) (ZResult, error) {
+	if ppr <= 0 || tpr <= 0 {
+		return ZResult{}, errors.New("reduced pressure and temperature must be positive")
+	}
+
+	opts = opts.withDefaults()
+	var res ZResult
+	switch opts.Method {
+	case ZHallYarborough:
+		res = hallYarborough(ppr, tpr, opts)
+	case ZDranchukAbouKassem:
+		res = dranchukAbouKassem(ppr, tpr, opts)
+	case ZPapay:
+		res = papay(ppr, tpr)
+	default:
+		return ZResult{}, fmt.Errorf("unknown z-factor method %q", opts.Method)
+	}
+	res.Method = opts.Method
+	res.Ppr = ppr
+	res.Tpr = tpr
+
+	if !res.Converged {
+		return res, fmt.Errorf("%w: method %s, ppr=%.3f, tpr=%.3f",
+			ErrNotConverged, opts.Method, ppr, tpr)
+	}
+	if res.Z <= 0 || math.IsNaN(res.Z) {
+		return res, fmt.Errorf("non-physical z-factor %.4f (ppr=%.3f, tpr=%.3f)", res.Z, ppr, tpr)
+	}
+	return res, nil
+}
+
+// hallYarborough - метод Холла-Ярборо: решение уравнения состояния
+// Старлинга-Карнахана относительно приведенной плотности методом Ньютона
+func hallYarborough(ppr, tpr float64, opts ZOptions) ZResult {
+	t := 1 / tpr
+	a := 0.06125 * t * math.Exp(-1.2*(1-t)*(1-t))
+	b := 14.76*t - 9.76*t*t + 4.58*t*t*t
+	c := 90.7*t - 242.2*t*t + 42.4*t*t*t
+	d := 2.18 + 2.82*t
+
+	f := func(y float64) float64 {
+		return -a*ppr + (y+y*y+y*y*y-y*y*y*y)/math.Pow(1-y, 3) - b*y*y + c*math.Pow(y, d)
+	}
+	df := func(y float64) float64 {
+		return (1+4*y+4*y*y-4*y*y*y+y*y*y*y)/math.Pow(1-y, 4) - 2*b*y + c*d*math.Pow(y, d-1)
+	}
+
+	y := 0.001
+	res := ZResult{}
+	for res.Iterations < opts.MaxIterations {
+		res.Iterations++
+		fy := f(y)
+		step := fy / df(y)
+		y -= step
+		// Приведенная плотность должна оставаться в интервале (0, 1)
+		if y <= 0 {
+			y = 1e-6
+		} else if y >= 1 {
+			y = 0.999
+		}
+		res.Residual = math.Abs(f(y))
+		if math.Abs(step) < opts.Tolerance || res.Residual < opts.Tolerance {
+			res.Converged = true
+			break
+		}
+	}
+
+	res.Z = a * ppr / y
+	return res
+}
+
+// Коэффициенты корреляции Дранчука-Абу-Кассема
+var dak = [...]float64{
+	0.3265, -1.0700, -0.5339, 0.01569, -0.05165,
+	0.5475, -0.7361, 0.1844, 0.1056, 0.6134, 0.7210,
+}
+
+// dranchukAbouKassem - 11-константное уравнение Дранчука-Абу-Кассема,
+// решаемое методом Ньютона относительно приведенной плотности
+func dranchukAbouKassem(ppr, tpr float64, opts ZOptions) ZResult {
+	c1 := dak[0] + dak[1]/tpr + dak[2]/math.Pow(tpr, 3) + dak[3]/math.Pow(tpr, 4) + dak[4]/math.Pow(tpr, 5)
+	c2 := dak[5] + dak[6]/tpr + dak[7]/(tpr*tpr)
+	c3 := dak[8] * (dak[6]/tpr + dak[7]/(tpr*tpr))
+	c4 := dak[9] / math.Pow(tpr, 3)
+	a11 := dak[10]
+
+	z := func(rho float64) float64 {
+		r2 := rho * rho
+		return 1 + c1*rho + c2*r2 - c3*math.Pow(rho, 5) + c4*r2*(1+a11*r2)*math.Exp(-a11*r2)
+	}
+	f := func(rho float64) float64 {
+		return z(rho) - 0.27*ppr/(rho*tpr)
+	}
+	df := func(rho float64) float64 {
+		r2 := rho * rho
+		return c1 + 2*c2*rho - 5*c3*r2*r2 +
+			2*c4*rho*(1+a11*r2-a11*a11*r2*r2)*math.Exp(-a11*r2) +
+			0.27*ppr/(r2*tpr)
+	}
+
+	rho := 0.27 * ppr / tpr // начальное приближение при Z = 1
+	res := ZResult{}
+	for res.Iterations < opts.MaxIterations {
+		res.Iterations++
+		step := f(rho) / df(rho)
+		rho -= step
+		if rho <= 0 {
+			rho = 1e-6
+		}
+		res.Residual = math.Abs(f(rho))
+		if math.Abs(step) < opts.Tolerance || res.Residual < opts.Tolerance {
+			res.Converged = true
+			break
+		}
+	}
+
+	res.Z = z(rho)
+	return res
+}
+
+// papay - явная формула Папая, пригодна для оценочных расчетов
+func papay(ppr, tpr float64) ZResult {
+	z := 1 - 3.53*ppr/math.Pow(10, 0.9813*tpr) + 0.274*ppr*ppr/math.Pow(10, 0.8157*tpr)
+	return ZResult{Z: z, Converged: true}
+}
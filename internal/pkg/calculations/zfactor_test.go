@@ -0,0 +1,91 @@
+// pkg/calculations/zfactor_test.go
+package calculations_test
+
+import (
+	"gas_wells/internal/pkg/calculations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPseudoCriticalProperties(t *testing.T) {
+	// Саттон, gammaG = 0.65: Ppc = 670.13 psia, Tpc = 365.11 °R
+	pc, err := calculations.PseudoCriticalProperties(0.65, calculations.CriticalSutton)
+	require.NoError(t, err)
+	assert.InDelta(t, 670.13*6894.757, pc.Ppc, 1e3)
+	assert.InDelta(t, 365.11*5.0/9.0, pc.Tpc, 0.05)
+
+	_, err = calculations.PseudoCriticalProperties(0, calculations.CriticalSutton)
+	assert.Error(t, err)
+	_, err = calculations.PseudoCriticalProperties(0.65, "unknown")
+	assert.Error(t, err)
+}
+
+func TestZFactorReduced(t *testing.T) {
+	// Опорные значения по номограмме Стэндинга-Катца
+	cases := []struct {
+		ppr, tpr, z float64
+	}{
+		{0.5, 1.2, 0.89},
+		{2.0, 1.5, 0.82},
+		{5.0, 1.3, 0.72},
+		{10.0, 2.0, 1.14},
+	}
+
+	methods := map[calculations.ZMethod]float64{
+		calculations.ZHallYarborough:     0.01,
+		calculations.ZDranchukAbouKassem: 0.01,
+	}
+
+	for method, tol := range methods {
+		for _, c := range cases {
+			res, err := calculations.ZFactorReduced(c.ppr, c.tpr, calculations.ZOptions{Method: method})
+			require.NoError(t, err, "%s ppr=%v tpr=%v", method, c.ppr, c.tpr)
+			assert.True(t, res.Converged)
+			assert.Positive(t, res.Iterations)
+			assert.Less(t, res.Residual, 1e-8)
+			assert.InDelta(t, c.z, res.Z, tol, "%s ppr=%v tpr=%v", method, c.ppr, c.tpr)
+		}
+	}
+
+	// Папай - явная формула, применима при умеренных давлениях
+	res, err := calculations.ZFactorReduced(2.0, 1.5, calculations.ZOptions{Method: calculations.ZPapay})
+	require.NoError(t, err)
+	assert.Zero(t, res.Iterations)
+	assert.InDelta(t, 0.82, res.Z, 0.02)
+}
+
+func TestZFactorNotConverged(t *testing.T) {
+	_, err := calculations.ZFactorReduced(2.0, 1.5, calculations.ZOptions{
+		Method:        calculations.ZHallYarborough,
+		MaxIterations: 1,
+	})
+	assert.ErrorIs(t, err, calculations.ErrNotConverged)
+}
+
+func TestZFactor(t *testing.T) {
+	// 10 МПа, 300 К, gammaG = 0.6
+	res, err := calculations.ZFactor(10e6, 300, 0.6, calculations.ZOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, calculations.DefaultZMethod, res.Method)
+	assert.InDelta(t, 0.83, res.Z, 0.02)
+
+	_, err = calculations.ZFactor(-1, 300, 0.6, calculations.ZOptions{})
+	assert.Error(t, err)
+	_, err = calculations.ZFactor(10e6, 300, 0.6, calculations.ZOptions{Method: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestParseZMethod(t *testing.T) {
+	m, err := calculations.ParseZMethod("")
+	require.NoError(t, err)
+	assert.Equal(t, calculations.DefaultZMethod, m)
+
+	m, err = calculations.ParseZMethod("papay")
+	require.NoError(t, err)
+	assert.Equal(t, calculations.ZPapay, m)
+
+	_, err = calculations.ParseZMethod("foo")
+	assert.Error(t, err)
+}
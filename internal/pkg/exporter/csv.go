@@ -0,0 +1,29 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// internal/pkg/exporter/csv.go
+
+// WriteCSV записывает таблицу в формате CSV с заголовком
+func WriteCSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Headers); err != nil {
+		return err
+	}
+	record := make([]string, 0, len(t.Headers))
+	for _, row := range t.Rows {
+		record = record[:0]
+		for _, v := range row {
+			record = append(record, fmt.Sprint(v))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
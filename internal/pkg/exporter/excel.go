@@ -2,23 +2,187 @@ package exporter
 
 import (
 	"fmt"
-	"gas_wells/internal/entity"
+	"io"
+	"time"
 
 	"github.com/xuri/excelize/v2"
 )
 
 // internal/pkg/exporter/excel.go
-func ExportToExcel(wells []entity.Well, filename string) error {
+
+// Column - столбец листа: заголовок с единицами измерения и формат чисел
+type Column struct {
+	Key    string  // Ключ столбца, например JSON-имя поля
+	Header string  // Заголовок с единицами: "Pбуф, Па"
+	NumFmt string  // Формат чисел Excel; пусто - общий
+	Width  float64 // Ширина столбца в символах; 0 - по умолчанию
+}
+
+// Форматы чисел и дат столбцов
+const (
+	FmtInt      = "#,##0"
+	FmtDecimal1 = "#,##0.0"
+	FmtDecimal3 = "0.000"
+	FmtDecimal6 = "0.000000"
+	FmtExp      = "0.00E+00"
+	FmtDate     = "dd.mm.yyyy"
+	FmtDateTime = "dd.mm.yyyy hh:mm"
+)
+
+// StreamWorkbook - книга Excel, листы которой записываются потоком построчно.
+// Строки сбрасываются во временные файлы, а не хранятся в памяти, поэтому
+// размер выгрузки не ограничен памятью сервера. После записи книгу нужно
+// закрыть (Close), чтобы удалить временные файлы.
+type StreamWorkbook struct {
+	f      *excelize.File
+	sheets []*StreamSheet
+	header int // Стиль заголовков
+}
+
+// StreamSheet - лист книги StreamWorkbook
+type StreamSheet struct {
+	sw     *excelize.StreamWriter
+	styles []int // Стили столбцов по форматам чисел
+	width  int
+	rows   int // Записано строк, включая заголовок
+	table  bool
+}
+
+func NewStreamWorkbook() (*StreamWorkbook, error) {
 	f := excelize.NewFile()
-	sheet := "Sheet1"
-	f.SetCellValue(sheet, "A1", "ID")
-	f.SetCellValue(sheet, "B1", "Name")
+	header, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Alignment: &excelize.Alignment{WrapText: true, Vertical: "center"},
+	})
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &StreamWorkbook{f: f, header: header}, nil
+}
+
+// AddSheet добавляет лист со строкой заголовков. Для table = true заголовок
+// закрепляется, а строки оформляются таблицей Excel с автофильтром.
+func (b *StreamWorkbook) AddSheet(name string, columns []Column, table bool) (*StreamSheet, error) {
+	if len(b.sheets) == 0 {
+		if err := b.f.SetSheetName("Sheet1", name); err != nil {
+			return nil, err
+		}
+	} else if _, err := b.f.NewSheet(name); err != nil {
+		return nil, err
+	}
+	sw, err := b.f.NewStreamWriter(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StreamSheet{sw: sw, styles: make([]int, len(columns)), width: len(columns), table: table}
+	styles := make(map[string]int)
+	for i, col := range columns {
+		if col.NumFmt != "" {
+			style, ok := styles[col.NumFmt]
+			if !ok {
+				numFmt := col.NumFmt
+				if style, err = b.f.NewStyle(&excelize.Style{CustomNumFmt: &numFmt}); err != nil {
+					return nil, err
+				}
+				styles[col.NumFmt] = style
+			}
+			s.styles[i] = style
+		}
+		if col.Width > 0 {
+			if err := sw.SetColWidth(i+1, i+1, col.Width); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if table {
+		if err := sw.SetPanes(&excelize.Panes{
+			Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft",
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	headers := make([]interface{}, len(columns))
+	for i, col := range columns {
+		headers[i] = excelize.Cell{StyleID: b.header, Value: col.Header}
+	}
+	if err := sw.SetRow("A1", headers); err != nil {
+		return nil, err
+	}
+	s.rows = 1
+	b.sheets = append(b.sheets, s)
+	return s, nil
+}
 
-	for i, well := range wells {
-		row := i + 2
-		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), well.ID)
-		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), well.Name)
+// WriteRow добавляет строку значений по порядку столбцов листа.
+// nil и нулевые указатели дают пустую ячейку.
+func (s *StreamSheet) WriteRow(values ...interface{}) error {
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		v = deref(v)
+		if v != nil && i < len(s.styles) && s.styles[i] != 0 {
+			v = excelize.Cell{StyleID: s.styles[i], Value: v}
+		}
+		row[i] = v
+	}
+	cell, err := excelize.CoordinatesToCellName(1, s.rows+1)
+	if err != nil {
+		return err
+	}
+	if err := s.sw.SetRow(cell, row); err != nil {
+		return err
 	}
+	s.rows++
+	return nil
+}
+
+// deref - значение по указателю; nil для нулевого указателя
+func deref(v interface{}) interface{} {
+	switch p := v.(type) {
+	case *int:
+		if p == nil {
+			return nil
+		}
+		return *p
+	case *float64:
+		if p == nil {
+			return nil
+		}
+		return *p
+	case *time.Time:
+		if p == nil {
+			return nil
+		}
+		return *p
+	}
+	return v
+}
+
+// WriteTo завершает листы и записывает книгу в w
+func (b *StreamWorkbook) WriteTo(w io.Writer) (int64, error) {
+	for _, s := range b.sheets {
+		if s.table && s.width > 0 {
+			last, err := excelize.CoordinatesToCellName(s.width, s.rows)
+			if err != nil {
+				return 0, err
+			}
+			disable := false
+			if err := s.sw.AddTable(&excelize.Table{
+				Range: "A1:" + last, StyleName: "TableStyleLight1", ShowRowStripes: &disable,
+			}); err != nil {
+				return 0, fmt.Errorf("failed to add table to sheet %s: %w", s.sw.Sheet, err)
+			}
+		}
+		if err := s.sw.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	return b.f.WriteTo(w)
+}
 
-	return f.SaveAs(filename)
+// Close удаляет временные файлы книги
+func (b *StreamWorkbook) Close() error {
+	return b.f.Close()
 }
@@ -0,0 +1,89 @@
+// pkg/exporter/excel_test.go
+package exporter_test
+
+import (
+	"bytes"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/exporter"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestStreamWorkbook(t *testing.T) {
+	book, err := exporter.NewStreamWorkbook()
+	require.NoError(t, err)
+	defer book.Close()
+
+	data, err := book.AddSheet("Данные", []exporter.Column{
+		{Header: "Название"},
+		{Header: "P, Па", NumFmt: exporter.FmtInt},
+		{Header: "Дата", NumFmt: exporter.FmtDate},
+	}, true)
+	require.NoError(t, err)
+	meta, err := book.AddSheet("Сведения", []exporter.Column{{Header: "Параметр"}, {Header: "Значение"}}, false)
+	require.NoError(t, err)
+
+	var since *time.Time
+	require.NoError(t, data.WriteRow("101", 12.5e6, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, data.WriteRow("102", 9e6, since))
+	require.NoError(t, meta.WriteRow("Пользователь", "engineer@example.com"))
+
+	var buf bytes.Buffer
+	_, err = book.WriteTo(&buf)
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, []string{"Данные", "Сведения"}, f.GetSheetList())
+	rows, err := f.GetRows("Данные")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"Название", "P, Па", "Дата"}, rows[0])
+	assert.Equal(t, []string{"101", "12,500,000", "01.03.2026"}, rows[1])
+	assert.Equal(t, []string{"102", "9,000,000"}, rows[2])
+
+	tables, err := f.GetTables("Данные")
+	require.NoError(t, err)
+	require.Len(t, tables, 1)
+	assert.Equal(t, "A1:C3", tables[0].Range)
+	panes, err := f.GetPanes("Данные")
+	require.NoError(t, err)
+	assert.True(t, panes.Freeze)
+	assert.Equal(t, 1, panes.YSplit)
+
+	tables, err = f.GetTables("Сведения")
+	require.NoError(t, err)
+	assert.Empty(t, tables)
+}
+
+func TestWellColumnsCoverWell(t *testing.T) {
+	// Расчетные признаки не хранятся и выгружаются на листе расчета
+	calculated := map[string]bool{"warnings": true, "hydrate_risk": true}
+
+	keys := map[string]bool{}
+	for _, col := range exporter.WellColumns() {
+		assert.False(t, keys[col.Key], "duplicate column %s", col.Key)
+		keys[col.Key] = true
+	}
+	typ := reflect.TypeOf(entity.Well{})
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		if !calculated[name] {
+			assert.True(t, keys[name], "well field %s is not exported", name)
+		}
+	}
+
+	padID := 3
+	row := exporter.WellRow(&entity.Well{ID: 7, Name: "101", Status: entity.StatusShutIn, PadID: &padID})
+	require.Len(t, row, len(exporter.WellColumns()))
+	assert.Equal(t, 7, row[0])
+	assert.Contains(t, row, entity.WellStatusName(entity.StatusShutIn))
+}
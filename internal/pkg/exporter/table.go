@@ -0,0 +1,56 @@
+package exporter
+
+import (
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// internal/pkg/exporter/table.go
+
+// Table - произвольная таблица результатов расчета для выгрузки
+type Table struct {
+	Sheet   string
+	Headers []string
+	Rows    [][]interface{}
+}
+
+// WriteTables записывает таблицы в книгу Excel, каждую на отдельный лист
+func WriteTables(w io.Writer, tables ...Table) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	for i, t := range tables {
+		sheet := t.Sheet
+		if sheet == "" {
+			sheet = "Sheet1"
+		}
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", sheet); err != nil {
+				return err
+			}
+		} else if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+
+		headers := make([]interface{}, len(t.Headers))
+		for j, h := range t.Headers {
+			headers[j] = h
+		}
+		if err := f.SetSheetRow(sheet, "A1", &headers); err != nil {
+			return err
+		}
+		for j, row := range t.Rows {
+			cell, err := excelize.CoordinatesToCellName(1, j+2)
+			if err != nil {
+				return err
+			}
+			if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := f.WriteTo(w)
+	return err
+}
@@ -0,0 +1,36 @@
+// pkg/exporter/table_test.go
+package exporter_test
+
+import (
+	"bytes"
+	"gas_wells/internal/pkg/exporter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestWriteTables(t *testing.T) {
+	var buf bytes.Buffer
+	err := exporter.WriteTables(&buf,
+		exporter.Table{
+			Sheet:   "Диаметр НКТ",
+			Headers: []string{"D, мм", "Q"},
+			Rows:    [][]interface{}{{50.3, 120.5}, {62.0, 140.0}},
+		},
+		exporter.Table{Sheet: "Второй", Headers: []string{"A"}},
+	)
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.Equal(t, []string{"Диаметр НКТ", "Второй"}, f.GetSheetList())
+	rows, err := f.GetRows("Диаметр НКТ")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"D, мм", "Q"}, rows[0])
+	assert.Equal(t, []string{"62", "140"}, rows[2])
+}
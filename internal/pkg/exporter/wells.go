@@ -0,0 +1,69 @@
+package exporter
+
+import (
+	"gas_wells/internal/entity"
+)
+
+// internal/pkg/exporter/wells.go
+
+// wellField - столбец листа скважин и значение поля entity.Well
+type wellField struct {
+	Column
+	value func(w *entity.Well) interface{}
+}
+
+// wellFields - хранимые поля entity.Well в единицах СИ, как в БД и API.
+// Ключ столбца - JSON-имя поля.
+var wellFields = []wellField{
+	{Column{"id", "ID", "", 6}, func(w *entity.Well) interface{} { return w.ID }},
+	{Column{"name", "Название", "", 14}, func(w *entity.Well) interface{} { return w.Name }},
+	{Column{"location", "Месторождение", "", 18}, func(w *entity.Well) interface{} { return w.Location }},
+	{Column{"pad_id", "ID куста", "", 8}, func(w *entity.Well) interface{} { return w.PadID }},
+	{Column{"status", "Состояние", "", 14}, func(w *entity.Well) interface{} { return entity.WellStatusName(w.Status) }},
+	{Column{"status_since", "В состоянии с", FmtDate, 12}, func(w *entity.Well) interface{} { return w.StatusSince }},
+	{Column{"gamma", "Относительная плотность газа", FmtDecimal3, 12}, func(w *entity.Well) interface{} { return w.GammaG }},
+	{Column{"temp", "Tпл, К", FmtDecimal1, 9}, func(w *entity.Well) interface{} { return w.Temp }},
+	{Column{"tempust", "Tуст, К", FmtDecimal1, 9}, func(w *entity.Well) interface{} { return w.TempUst }},
+	{Column{"depth", "Глубина, м", FmtDecimal1, 10}, func(w *entity.Well) interface{} { return w.Depth }},
+	{Column{"pbuf", "Pбуф, Па", FmtInt, 13}, func(w *entity.Well) interface{} { return w.Pbuf }},
+	{Column{"ptb", "Pзатр, Па", FmtInt, 13}, func(w *entity.Well) interface{} { return w.Ptb }},
+	{Column{"ppl", "Pпл, Па", FmtInt, 13}, func(w *entity.Well) interface{} { return w.Ppl }},
+	{Column{"pz", "Pзаб, Па", FmtInt, 13}, func(w *entity.Well) interface{} { return w.Pz }},
+	{Column{"pz_manual", "Pзаб по замеру", "", 10}, func(w *entity.Well) interface{} { return w.PzManual }},
+	{Column{"q", "Q, м³/сут", FmtInt, 11}, func(w *entity.Well) interface{} { return w.Q }},
+	{Column{"roughness", "Шероховатость, м", FmtExp, 11}, func(w *entity.Well) interface{} { return w.Roughness }},
+	{Column{"diameter", "Диаметр НКТ, м", FmtDecimal3, 10}, func(w *entity.Well) interface{} { return w.Diameter }},
+	{Column{"a", "A, МПа²·сут/тыс.м³", FmtDecimal6, 12}, func(w *entity.Well) interface{} { return w.A }},
+	{Column{"b", "B, (МПа·сут/тыс.м³)²", FmtDecimal6, 12}, func(w *entity.Well) interface{} { return w.B }},
+	{Column{"mu", "Вязкость газа, мПа·с", FmtDecimal6, 11}, func(w *entity.Well) interface{} { return w.Mu }},
+	{Column{"mu_manual", "Вязкость задана вручную", "", 10}, func(w *entity.Well) interface{} { return w.MuManual }},
+	{Column{"wgf", "ВГФ, см³/м³", FmtDecimal1, 9}, func(w *entity.Well) interface{} { return w.WGF }},
+	{Column{"rog", "Плотность воды, кг/м³", FmtDecimal1, 10}, func(w *entity.Well) interface{} { return w.Rog }},
+	{Column{"hw", "Высота столба ГЖС, м", FmtDecimal1, 10}, func(w *entity.Well) interface{} { return w.Hw }},
+	{Column{"qmin", "Qmin, м³/сут", FmtInt, 11}, func(w *entity.Well) interface{} { return w.Qmin }},
+	{Column{"qmin_model", "Модель Qmin", "", 10}, func(w *entity.Well) interface{} { return w.QminModel }},
+	{Column{"liquid_loading", "Риск самозадавливания", "", 10}, func(w *entity.Well) interface{} { return w.LiquidLoading }},
+	{Column{"pmax", "Pmax, Па", FmtInt, 13}, func(w *entity.Well) interface{} { return w.Pmax }},
+	{Column{"choke", "Диаметр штуцера, м", FmtDecimal3, 10}, func(w *entity.Well) interface{} { return w.Choke }},
+	{Column{"pline", "Pшлейфа, Па", FmtInt, 13}, func(w *entity.Well) interface{} { return w.Pline }},
+	{Column{"created", "Создана", FmtDateTime, 16}, func(w *entity.Well) interface{} { return w.Created }},
+	{Column{"updated", "Изменена", FmtDateTime, 16}, func(w *entity.Well) interface{} { return w.Updated }},
+}
+
+// WellColumns - столбцы листа скважин: все хранимые поля entity.Well
+func WellColumns() []Column {
+	columns := make([]Column, len(wellFields))
+	for i, f := range wellFields {
+		columns[i] = f.Column
+	}
+	return columns
+}
+
+// WellRow - значения полей скважины по порядку WellColumns
+func WellRow(w *entity.Well) []interface{} {
+	row := make([]interface{}, len(wellFields))
+	for i, f := range wellFields {
+		row[i] = f.value(w)
+	}
+	return row
+}
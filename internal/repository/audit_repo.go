@@ -0,0 +1,117 @@
+// internal/repository/audit_repo.go
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type auditRepo struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+func NewAuditRepo(db *pgxpool.Pool, log logger.Logger) AuditRepository {
+	return &auditRepo{
+		db:     db,
+		logger: log.With("layer", "repository"),
+	}
+}
+
+// Create - добавление записи журнала от имени пользователя из контекста запроса
+func (r *auditRepo) Create(ctx context.Context, entry *entity.AuditEntry) error {
+	return insertAuditEntry(ctx, r.db, entry)
+}
+
+// rowQuerier - пул соединений или транзакция
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// insertAuditEntry записывает запись журнала через db, в том числе
+// в транзакции изменения объекта
+func insertAuditEntry(ctx context.Context, db rowQuerier, entry *entity.AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode changes: %w", err)
+	}
+
+	entry.ActorID, entry.Actor = actorFrom(ctx)
+	query := `
+		INSERT INTO audit_log (entity_type, entity_id, entity_name, action, actor_id, actor, changes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	return db.QueryRow(ctx, query,
+		entry.EntityType, entry.EntityID, entry.EntityName, entry.Action, entry.ActorID, entry.Actor, changes,
+	).Scan(&entry.ID, &entry.Created)
+}
+
+// List - записи журнала по фильтру, новые первыми
+func (r *auditRepo) List(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.EntityType != "" {
+		where("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		where("entity_id = $%d", filter.EntityID)
+	}
+	if filter.Action != "" {
+		where("action = $%d", filter.Action)
+	}
+	if filter.Actor != "" {
+		where(`actor ILIKE '%%' || $%d || '%%' ESCAPE '\'`, likeEscaper.Replace(filter.Actor))
+	}
+	if !filter.From.IsZero() {
+		where("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		where("created_at < $%d", filter.To)
+	}
+
+	query := `
+		SELECT id, entity_type, entity_id, entity_name, action, actor_id, actor, changes, created_at
+		FROM audit_log`
+	if len(conditions) > 0 {
+		query += "\n\t\tWHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf("\n\t\tORDER BY created_at DESC, id DESC\n\t\tLIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to list audit log", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*entity.AuditEntry
+	for rows.Next() {
+		entry := &entity.AuditEntry{}
+		var changes []byte
+		err := rows.Scan(&entry.ID, &entry.EntityType, &entry.EntityID, &entry.EntityName, &entry.Action,
+			&entry.ActorID, &entry.Actor, &changes, &entry.Created)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode changes of audit entry %d: %w", entry.ID, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,44 @@
+// internal/repository/audit_repo_test.go
+package repository_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/auth"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepositoryActorFilter(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	log := logger.New("test")
+	users := repository.NewUserRepo(db, log)
+	audit := repository.NewAuditRepo(db, log)
+
+	for i, email := range []string{"a%b@example.com", "axb@example.com", "c_d@example.com", "cxd@example.com"} {
+		user := &entity.User{Email: email, Username: email, PasswordHash: "x", Role: entity.RoleEngineer}
+		require.NoError(t, users.Create(ctx, user))
+		entry := &entity.AuditEntry{EntityType: entity.AuditWell, EntityID: i + 1, Action: entity.AuditCreate}
+		require.NoError(t, audit.Create(auth.WithUser(ctx, user), entry))
+	}
+
+	// Символы шаблона LIKE в фильтре по автору сравниваются буквально
+	for actor, want := range map[string][]string{
+		"a%b": {"a%b@example.com"},
+		"c_d": {"c_d@example.com"},
+		"XD@": {"cxd@example.com"},
+	} {
+		entries, err := audit.List(ctx, entity.AuditFilter{Actor: actor, Limit: 10})
+		require.NoError(t, err)
+		var actors []string
+		for _, e := range entries {
+			actors = append(actors, e.Actor)
+		}
+		assert.Equal(t, want, actors, actor)
+	}
+}
@@ -0,0 +1,245 @@
+// internal/repository/field_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Код ошибки PostgreSQL при нарушении уникальности
+const uniqueViolation = "23505"
+
+type fieldRepo struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+func NewFieldRepo(db *pgxpool.Pool, log logger.Logger) FieldRepository {
+	return &fieldRepo{
+		db:     db,
+		logger: log.With("layer", "repository"),
+	}
+}
+
+// CreateField - добавление месторождения
+func (r *fieldRepo) CreateField(ctx context.Context, field *entity.Field) error {
+	query := `
+		INSERT INTO fields (name, description)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(ctx, query, field.Name, field.Description).Scan(&field.ID, &field.Created)
+	return duplicateError(err)
+}
+
+// GetField - получение месторождения по ID
+func (r *fieldRepo) GetField(ctx context.Context, id int) (*entity.Field, error) {
+	field := &entity.Field{}
+	err := r.db.QueryRow(ctx,
+		`SELECT id, name, description, created_at FROM fields WHERE id = $1`, id,
+	).Scan(&field.ID, &field.Name, &field.Description, &field.Created)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// ListFields - все месторождения по названию
+func (r *fieldRepo) ListFields(ctx context.Context) ([]*entity.Field, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, name, description, created_at FROM fields ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fields []*entity.Field
+	for rows.Next() {
+		field := &entity.Field{}
+		if err := rows.Scan(&field.ID, &field.Name, &field.Description, &field.Created); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, rows.Err()
+}
+
+// DeleteField - удаление месторождения вместе с кустами;
+// скважины остаются без привязки к кусту
+func (r *fieldRepo) DeleteField(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM fields WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("failed to delete field", "id", id, "error", err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("field not found")
+	}
+	return nil
+}
+
+// CreatePad - добавление куста
+func (r *fieldRepo) CreatePad(ctx context.Context, pad *entity.Pad) error {
+	query := `
+		INSERT INTO pads (field_id, name)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(ctx, query, pad.FieldID, pad.Name).Scan(&pad.ID, &pad.Created)
+	return duplicateError(err)
+}
+
+// GetPad - получение куста по ID
+func (r *fieldRepo) GetPad(ctx context.Context, id int) (*entity.Pad, error) {
+	pad := &entity.Pad{}
+	err := r.db.QueryRow(ctx,
+		`SELECT id, field_id, name, created_at FROM pads WHERE id = $1`, id,
+	).Scan(&pad.ID, &pad.FieldID, &pad.Name, &pad.Created)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pad, nil
+}
+
+// ListPads - кусты месторождения по названию
+func (r *fieldRepo) ListPads(ctx context.Context, fieldID int) ([]*entity.Pad, error) {
+	query := `
+		SELECT id, field_id, name, created_at
+		FROM pads
+		WHERE $1 = 0 OR field_id = $1
+		ORDER BY field_id, name
+	`
+	rows, err := r.db.Query(ctx, query, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pads []*entity.Pad
+	for rows.Next() {
+		pad := &entity.Pad{}
+		if err := rows.Scan(&pad.ID, &pad.FieldID, &pad.Name, &pad.Created); err != nil {
+			return nil, err
+		}
+		pads = append(pads, pad)
+	}
+	return pads, rows.Err()
+}
+
+// DeletePad - удаление куста; скважины остаются без привязки к кусту
+func (r *fieldRepo) DeletePad(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM pads WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("failed to delete pad", "id", id, "error", err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("pad not found")
+	}
+	return nil
+}
+
+// FieldSummaries - суммарный дебит, среднее Pбуф и число скважин по статусам
+// для каждого месторождения
+func (r *fieldRepo) FieldSummaries(ctx context.Context) ([]*entity.GroupSummary, error) {
+	totals := `
+		SELECT f.id, f.name, COUNT(w.id), COALESCE(SUM(w.q), 0), COALESCE(AVG(w.pbuf), 0)
+		FROM fields f
+		LEFT JOIN pads p ON p.field_id = f.id
+		LEFT JOIN wells w ON w.pad_id = p.id
+		GROUP BY f.id, f.name
+		ORDER BY f.name
+	`
+	statuses := `
+		SELECT p.field_id, w.status, COUNT(*)
+		FROM wells w
+		JOIN pads p ON p.id = w.pad_id
+		GROUP BY p.field_id, w.status
+	`
+	return r.summaries(ctx, totals, statuses)
+}
+
+// PadSummaries - суммарный дебит, среднее Pбуф и число скважин по статусам
+// для каждого куста месторождения
+func (r *fieldRepo) PadSummaries(ctx context.Context, fieldID int) ([]*entity.GroupSummary, error) {
+	totals := `
+		SELECT p.id, p.name, COUNT(w.id), COALESCE(SUM(w.q), 0), COALESCE(AVG(w.pbuf), 0)
+		FROM pads p
+		LEFT JOIN wells w ON w.pad_id = p.id
+		WHERE p.field_id = $1
+		GROUP BY p.id, p.name
+		ORDER BY p.name
+	`
+	statuses := `
+		SELECT w.pad_id, w.status, COUNT(*)
+		FROM wells w
+		JOIN pads p ON p.id = w.pad_id
+		WHERE p.field_id = $1
+		GROUP BY w.pad_id, w.status
+	`
+	return r.summaries(ctx, totals, statuses, fieldID)
+}
+
+// summaries выполняет запрос итогов по группам (id, name, count, sum q, avg pbuf)
+// и запрос числа скважин по статусам (group id, status, count)
+func (r *fieldRepo) summaries(ctx context.Context, totals, statuses string, args ...any) ([]*entity.GroupSummary, error) {
+	rows, err := r.db.Query(ctx, totals, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []*entity.GroupSummary
+	byID := make(map[int]*entity.GroupSummary)
+	for rows.Next() {
+		g := &entity.GroupSummary{ByStatus: make(map[string]int)}
+		if err := rows.Scan(&g.ID, &g.Name, &g.Wells, &g.TotalQ, &g.AvgPbuf); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+		byID[g.ID] = g
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.Query(ctx, statuses, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, count int
+		var status string
+		if err := rows.Scan(&id, &status, &count); err != nil {
+			return nil, err
+		}
+		if g, ok := byID[id]; ok {
+			g.ByStatus[status] = count
+		}
+	}
+	return groups, rows.Err()
+}
+
+// duplicateError заменяет нарушение уникальности на ErrDuplicate
+func duplicateError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: %s", ErrDuplicate, pgErr.ConstraintName)
+	}
+	return err
+}
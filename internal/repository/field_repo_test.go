@@ -0,0 +1,74 @@
+// internal/repository/field_repo_test.go
+package repository_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFieldSummaries - итоги по месторождениям и кустам: пустые группы
+// сохраняются с нулями, скважины без куста не учитываются
+func TestFieldSummaries(t *testing.T) {
+	ctx := context.Background()
+	db := testDB(t)
+	log := logger.New("test")
+	fields := repository.NewFieldRepo(db, log)
+	wells := repository.NewWellRepo(db, log)
+
+	field := func(name string) *entity.Field {
+		f := &entity.Field{Name: name}
+		require.NoError(t, fields.CreateField(ctx, f))
+		return f
+	}
+	pad := func(fieldID int, name string) *entity.Pad {
+		p := &entity.Pad{FieldID: fieldID, Name: name}
+		require.NoError(t, fields.CreatePad(ctx, p))
+		return p
+	}
+	well := func(padID *int, q, pbuf float64, status string) {
+		w := testWell("W", q, pbuf)
+		w.PadID, w.Status = padID, status
+		require.NoError(t, wells.Create(ctx, w, nil))
+	}
+
+	north, south := field("Северное"), field("Южное")
+	field("Западное")
+	k1, k2, k3 := pad(north.ID, "К1"), pad(north.ID, "К2"), pad(south.ID, "К3")
+
+	// Повторные названия - ErrDuplicate; название куста уникально в пределах месторождения
+	assert.ErrorIs(t, fields.CreateField(ctx, &entity.Field{Name: "Южное"}), repository.ErrDuplicate)
+	assert.ErrorIs(t, fields.CreatePad(ctx, &entity.Pad{FieldID: north.ID, Name: "К1"}), repository.ErrDuplicate)
+	pad(south.ID, "К1")
+	well(&k1.ID, 100e3, 8e6, entity.StatusProducing)
+	well(&k1.ID, 50e3, 6e6, entity.StatusShutIn)
+	well(&k3.ID, 70e3, 9e6, entity.StatusProducing)
+	well(nil, 500e3, 20e6, entity.StatusProducing)
+
+	summaries, err := fields.FieldSummaries(ctx)
+	require.NoError(t, err)
+	require.Len(t, summaries, 3)
+	assert.Equal(t, &entity.GroupSummary{Name: "Западное", ID: summaries[0].ID, ByStatus: map[string]int{}}, summaries[0])
+	assert.Equal(t, &entity.GroupSummary{
+		ID: north.ID, Name: "Северное", Wells: 2, TotalQ: 150e3, AvgPbuf: 7e6,
+		ByStatus: map[string]int{entity.StatusProducing: 1, entity.StatusShutIn: 1},
+	}, summaries[1])
+	assert.Equal(t, &entity.GroupSummary{
+		ID: south.ID, Name: "Южное", Wells: 1, TotalQ: 70e3, AvgPbuf: 9e6,
+		ByStatus: map[string]int{entity.StatusProducing: 1},
+	}, summaries[2])
+
+	pads, err := fields.PadSummaries(ctx, north.ID)
+	require.NoError(t, err)
+	require.Len(t, pads, 2)
+	assert.Equal(t, &entity.GroupSummary{
+		ID: k1.ID, Name: "К1", Wells: 2, TotalQ: 150e3, AvgPbuf: 7e6,
+		ByStatus: map[string]int{entity.StatusProducing: 1, entity.StatusShutIn: 1},
+	}, pads[0])
+	assert.Equal(t, &entity.GroupSummary{ID: k2.ID, Name: "К2", ByStatus: map[string]int{}}, pads[1])
+}
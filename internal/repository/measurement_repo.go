@@ -0,0 +1,172 @@
+// internal/repository/measurement_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type measurementRepo struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+func NewMeasurementRepo(db *pgxpool.Pool, log logger.Logger) MeasurementRepository {
+	return &measurementRepo{
+		db:     db,
+		logger: log.With("layer", "repository"),
+	}
+}
+
+// Save - добавление замеров одной транзакцией
+func (r *measurementRepo) Save(ctx context.Context, measurements []*entity.Measurement) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO well_measurements (well_id, measured_at, pbuf, ptb, q, tempust)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (well_id, measured_at) DO UPDATE SET
+			pbuf = EXCLUDED.pbuf, ptb = EXCLUDED.ptb,
+			q = EXCLUDED.q, tempust = EXCLUDED.tempust
+		RETURNING id, created_at
+	`
+	for _, m := range measurements {
+		err := tx.QueryRow(ctx, query,
+			m.WellID, m.MeasuredAt, m.Pbuf, m.Ptb, m.Q, m.TempUst,
+		).Scan(&m.ID, &m.Created)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// List - исходные замеры скважины в интервале [from, to)
+func (r *measurementRepo) List(ctx context.Context, wellID int, from, to time.Time) ([]*entity.Measurement, error) {
+	query := `
+		SELECT id, well_id, measured_at, pbuf, ptb, q, tempust, created_at
+		FROM well_measurements
+		WHERE well_id = $1 AND measured_at >= $2 AND measured_at < $3
+		ORDER BY measured_at
+	`
+	rows, err := r.db.Query(ctx, query, wellID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var measurements []*entity.Measurement
+	for rows.Next() {
+		m := &entity.Measurement{}
+		err := rows.Scan(
+			&m.ID,
+			&m.WellID,
+			&m.MeasuredAt,
+			&m.Pbuf,
+			&m.Ptb,
+			&m.Q,
+			&m.TempUst,
+			&m.Created,
+		)
+		if err != nil {
+			return nil, err
+		}
+		measurements = append(measurements, m)
+	}
+	return measurements, rows.Err()
+}
+
+// Aggregate - средние значения за час или сутки в интервале [from, to).
+// Пропущенные параметры не учитываются в среднем.
+func (r *measurementRepo) Aggregate(ctx context.Context, wellID int, from, to time.Time, res entity.MeasurementResolution) ([]*entity.Measurement, error) {
+	if res != entity.ResolutionHour && res != entity.ResolutionDay {
+		return nil, fmt.Errorf("unsupported resolution %q", res)
+	}
+
+	query := `
+		SELECT date_trunc($4, measured_at) AS bucket,
+			AVG(pbuf), AVG(ptb), AVG(q), AVG(tempust), COUNT(*)
+		FROM well_measurements
+		WHERE well_id = $1 AND measured_at >= $2 AND measured_at < $3
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+	rows, err := r.db.Query(ctx, query, wellID, from, to, string(res))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var measurements []*entity.Measurement
+	for rows.Next() {
+		m := &entity.Measurement{WellID: wellID}
+		err := rows.Scan(
+			&m.MeasuredAt,
+			&m.Pbuf,
+			&m.Ptb,
+			&m.Q,
+			&m.TempUst,
+			&m.Samples,
+		)
+		if err != nil {
+			return nil, err
+		}
+		measurements = append(measurements, m)
+	}
+	return measurements, rows.Err()
+}
+
+// Latest - последние известные значения каждого параметра;
+// MeasuredAt - время последнего замера. nil, если замеров нет.
+func (r *measurementRepo) Latest(ctx context.Context, wellID int) (*entity.Measurement, error) {
+	query := `
+		SELECT MAX(measured_at),
+			(SELECT pbuf FROM well_measurements
+				WHERE well_id = $1 AND pbuf IS NOT NULL ORDER BY measured_at DESC LIMIT 1),
+			(SELECT ptb FROM well_measurements
+				WHERE well_id = $1 AND ptb IS NOT NULL ORDER BY measured_at DESC LIMIT 1),
+			(SELECT q FROM well_measurements
+				WHERE well_id = $1 AND q IS NOT NULL ORDER BY measured_at DESC LIMIT 1),
+			(SELECT tempust FROM well_measurements
+				WHERE well_id = $1 AND tempust IS NOT NULL ORDER BY measured_at DESC LIMIT 1)
+		FROM well_measurements
+		WHERE well_id = $1
+	`
+	var measuredAt *time.Time
+	m := &entity.Measurement{WellID: wellID}
+	err := r.db.QueryRow(ctx, query, wellID).Scan(&measuredAt, &m.Pbuf, &m.Ptb, &m.Q, &m.TempUst)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && measuredAt == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.MeasuredAt = *measuredAt
+	return m, nil
+}
+
+// Delete - удаление замера скважины
+func (r *measurementRepo) Delete(ctx context.Context, wellID, id int) error {
+	result, err := r.db.Exec(ctx,
+		`DELETE FROM well_measurements WHERE id = $1 AND well_id = $2`, id, wellID)
+	if err != nil {
+		r.logger.Error("failed to delete measurement", "id", id, "error", err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("measurement not found")
+	}
+	return nil
+}
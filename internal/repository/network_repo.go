@@ -0,0 +1,135 @@
+// internal/repository/network_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type networkRepo struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+func NewNetworkRepo(db *pgxpool.Pool, log logger.Logger) NetworkRepository {
+	return &networkRepo{
+		db:     db,
+		logger: log.With("layer", "repository"),
+	}
+}
+
+// CreateNode - добавление узла сети сбора
+func (r *networkRepo) CreateNode(ctx context.Context, node *entity.NetworkNode) error {
+	query := `
+		INSERT INTO network_nodes (pad_id, name, kind, well_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(ctx, query, node.PadID, node.Name, node.Kind, node.WellID).
+		Scan(&node.ID, &node.Created)
+}
+
+// ListNodes - узлы сети куста
+func (r *networkRepo) ListNodes(ctx context.Context, padID int) ([]*entity.NetworkNode, error) {
+	query := `
+		SELECT id, pad_id, name, kind, well_id, created_at
+		FROM network_nodes
+		WHERE pad_id = $1
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query, padID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []*entity.NetworkNode
+	for rows.Next() {
+		n := &entity.NetworkNode{}
+		if err := rows.Scan(&n.ID, &n.PadID, &n.Name, &n.Kind, &n.WellID, &n.Created); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, rows.Err()
+}
+
+// DeleteNode - удаление узла вместе с примыкающими участками
+func (r *networkRepo) DeleteNode(ctx context.Context, padID, id int) error {
+	result, err := r.db.Exec(ctx,
+		`DELETE FROM network_nodes WHERE id = $1 AND pad_id = $2`, id, padID)
+	if err != nil {
+		r.logger.Error("failed to delete network node", "id", id, "error", err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("network node not found")
+	}
+	return nil
+}
+
+// CreateSegment - добавление участка трубопровода
+func (r *networkRepo) CreateSegment(ctx context.Context, s *entity.PipeSegment) error {
+	query := `
+		INSERT INTO pipe_segments (pad_id, from_node_id, to_node_id, length, diameter, roughness, elevation)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(ctx, query,
+		s.PadID, s.FromNodeID, s.ToNodeID, s.Length, s.Diameter, s.Roughness, s.Elevation,
+	).Scan(&s.ID, &s.Created)
+}
+
+// ListSegments - участки сети куста
+func (r *networkRepo) ListSegments(ctx context.Context, padID int) ([]*entity.PipeSegment, error) {
+	query := `
+		SELECT id, pad_id, from_node_id, to_node_id, length, diameter, roughness, elevation, created_at
+		FROM pipe_segments
+		WHERE pad_id = $1
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query, padID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var segments []*entity.PipeSegment
+	for rows.Next() {
+		s := &entity.PipeSegment{}
+		err := rows.Scan(
+			&s.ID,
+			&s.PadID,
+			&s.FromNodeID,
+			&s.ToNodeID,
+			&s.Length,
+			&s.Diameter,
+			&s.Roughness,
+			&s.Elevation,
+			&s.Created,
+		)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, s)
+	}
+	return segments, rows.Err()
+}
+
+// DeleteSegment - удаление участка
+func (r *networkRepo) DeleteSegment(ctx context.Context, padID, id int) error {
+	result, err := r.db.Exec(ctx,
+		`DELETE FROM pipe_segments WHERE id = $1 AND pad_id = $2`, id, padID)
+	if err != nil {
+		r.logger.Error("failed to delete pipe segment", "id", id, "error", err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("pipe segment not found")
+	}
+	return nil
+}
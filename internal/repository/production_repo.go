@@ -0,0 +1,85 @@
+// internal/repository/production_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type productionRepo struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+func NewProductionRepo(db *pgxpool.Pool, log logger.Logger) ProductionRepository {
+	return &productionRepo{
+		db:     db,
+		logger: log.With("layer", "repository"),
+	}
+}
+
+// Save - добавление записей истории добычи одной транзакцией
+func (r *productionRepo) Save(ctx context.Context, records []*entity.ProductionRecord) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO well_production (well_id, date, q)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (well_id, date) DO UPDATE SET q = EXCLUDED.q
+		RETURNING id, created_at
+	`
+	for _, rec := range records {
+		if err := tx.QueryRow(ctx, query, rec.WellID, rec.Date, rec.Q).Scan(&rec.ID, &rec.Created); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListByWell - история добычи скважины по возрастанию даты
+func (r *productionRepo) ListByWell(ctx context.Context, wellID int) ([]*entity.ProductionRecord, error) {
+	query := `
+		SELECT id, well_id, date, q, created_at
+		FROM well_production
+		WHERE well_id = $1
+		ORDER BY date
+	`
+	rows, err := r.db.Query(ctx, query, wellID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*entity.ProductionRecord
+	for rows.Next() {
+		rec := &entity.ProductionRecord{}
+		if err := rows.Scan(&rec.ID, &rec.WellID, &rec.Date, &rec.Q, &rec.Created); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// Delete - удаление записи истории
+func (r *productionRepo) Delete(ctx context.Context, wellID, id int) error {
+	result, err := r.db.Exec(ctx,
+		`DELETE FROM well_production WHERE id = $1 AND well_id = $2`, id, wellID)
+	if err != nil {
+		r.logger.Error("failed to delete production record", "id", id, "error", err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("production record not found")
+	}
+	return nil
+}
@@ -3,20 +3,128 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"gas_wells/internal/entity"
+	"time"
 )
 
+// ErrDuplicate - запись нарушает условие уникальности (например, имя уже занято)
+var ErrDuplicate = errors.New("duplicate record")
+
 //Сначала создаем интерфейсы для репозиториев.
 //Это гарантирует, что сервисы не зависят от конкретной БД.
 
 type WellRepository interface {
-	Create(ctx context.Context, well *entity.Well) error
+	// Create, Update, Delete и ChangeStatus сохраняют запись журнала аудита
+	// entry в той же транзакции, что и изменение; nil - без записи.
+	// ID новой скважины в записи заполняется при создании.
+
+	Create(ctx context.Context, well *entity.Well, entry *entity.AuditEntry) error
 	GetByID(ctx context.Context, id int) (*entity.Well, error)
-	Update(ctx context.Context, well *entity.Well) error
+	Update(ctx context.Context, well *entity.Well, entry *entity.AuditEntry) error
+	Delete(ctx context.Context, id int, entry *entity.AuditEntry) error
+	// List возвращает страницу списка скважин по условиям запроса
+	List(ctx context.Context, q entity.WellQuery) (*entity.WellPage, error)
+	// Each передает в fn все скважины по условиям запроса, не загружая список в память
+	Each(ctx context.Context, q entity.WellQuery, fn func(*entity.Well) error) error
+	ListByPad(ctx context.Context, padID int) ([]*entity.Well, error)
+
+	// Create и Update сохраняют новую версию скважины (entity.WellRevision)
+
+	// GetByIDAsOf возвращает скважину в версии, действовавшей в момент at;
+	// nil - в этот момент версий скважины еще не было
+	GetByIDAsOf(ctx context.Context, id int, at time.Time) (*entity.Well, error)
+	// Revisions возвращает версии скважины, новые первыми
+	Revisions(ctx context.Context, id int) ([]*entity.WellRevision, error)
+	GetRevision(ctx context.Context, id, revision int) (*entity.WellRevision, error)
+
+	// ChangeStatus переводит скважину в состояние change.To и записывает
+	// переход в историю состояний; Create записывает начальное состояние
+	ChangeStatus(ctx context.Context, well *entity.Well, change *entity.StatusChange, entry *entity.AuditEntry) error
+	// StatusHistory возвращает переходы скважины по датам
+	StatusHistory(ctx context.Context, id int) ([]*entity.StatusChange, error)
+}
+
+type FieldRepository interface {
+	CreateField(ctx context.Context, field *entity.Field) error
+	GetField(ctx context.Context, id int) (*entity.Field, error)
+	ListFields(ctx context.Context) ([]*entity.Field, error)
+	DeleteField(ctx context.Context, id int) error
+
+	CreatePad(ctx context.Context, pad *entity.Pad) error
+	GetPad(ctx context.Context, id int) (*entity.Pad, error)
+	// ListPads возвращает кусты месторождения; fieldID = 0 - все кусты
+	ListPads(ctx context.Context, fieldID int) ([]*entity.Pad, error)
+	DeletePad(ctx context.Context, id int) error
+
+	// FieldSummaries - сводка по скважинам каждого месторождения
+	FieldSummaries(ctx context.Context) ([]*entity.GroupSummary, error)
+	// PadSummaries - сводка по скважинам каждого куста месторождения
+	PadSummaries(ctx context.Context, fieldID int) ([]*entity.GroupSummary, error)
+}
+
+type NetworkRepository interface {
+	CreateNode(ctx context.Context, node *entity.NetworkNode) error
+	ListNodes(ctx context.Context, padID int) ([]*entity.NetworkNode, error)
+	DeleteNode(ctx context.Context, padID, id int) error
+
+	CreateSegment(ctx context.Context, segment *entity.PipeSegment) error
+	ListSegments(ctx context.Context, padID int) ([]*entity.PipeSegment, error)
+	DeleteSegment(ctx context.Context, padID, id int) error
+}
+
+type WellTestRepository interface {
+	Create(ctx context.Context, test *entity.WellTest) error
+	GetByID(ctx context.Context, id int) (*entity.WellTest, error)
+	ListByWell(ctx context.Context, wellID int) ([]*entity.WellTest, error)
 	Delete(ctx context.Context, id int) error
-	List(ctx context.Context, limit, offset int) ([]*entity.Well, error)
+}
+
+type MeasurementRepository interface {
+	// Save добавляет замеры; замер на ту же дату и время заменяет прежний
+	Save(ctx context.Context, measurements []*entity.Measurement) error
+	List(ctx context.Context, wellID int, from, to time.Time) ([]*entity.Measurement, error)
+	Aggregate(ctx context.Context, wellID int, from, to time.Time, res entity.MeasurementResolution) ([]*entity.Measurement, error)
+	// Latest возвращает последние известные значения каждого параметра
+	Latest(ctx context.Context, wellID int) (*entity.Measurement, error)
+	Delete(ctx context.Context, wellID, id int) error
+}
+
+type ProductionRepository interface {
+	// Save добавляет записи истории; запись на ту же дату заменяет прежнюю
+	Save(ctx context.Context, records []*entity.ProductionRecord) error
+	ListByWell(ctx context.Context, wellID int) ([]*entity.ProductionRecord, error)
+	Delete(ctx context.Context, wellID, id int) error
+}
+
+type PressureSurveyRepository interface {
+	Create(ctx context.Context, survey *entity.PressureSurvey) error
+	ListByWell(ctx context.Context, wellID int) ([]*entity.PressureSurvey, error)
+	Delete(ctx context.Context, wellID, id int) error
 }
 
 type UserRepository interface {
+	Create(ctx context.Context, user *entity.User) error
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetByID(ctx context.Context, id int) (*entity.User, error)
+	List(ctx context.Context) ([]*entity.User, error)
+	UpdateRole(ctx context.Context, id int, role string) error
+}
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entity.RefreshToken) error
+	Get(ctx context.Context, id string) (*entity.RefreshToken, error)
+	// Revoke отзывает токен; false - токен не найден или уже отозван
+	Revoke(ctx context.Context, id string) (bool, error)
+	// Rotate отзывает токен при обмене на новый с отметкой времени обмена;
+	// false - токен не найден или уже отозван
+	Rotate(ctx context.Context, id string) (bool, error)
+	// RevokeAll отзывает все токены пользователя, в том числе только что обмененные
+	RevokeAll(ctx context.Context, userID int) error
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *entity.AuditEntry) error
+	// List возвращает записи по фильтру, новые первыми
+	List(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error)
 }
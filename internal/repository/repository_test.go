@@ -0,0 +1,68 @@
+// internal/repository/repository_test.go
+package repository_test
+
+import (
+	"context"
+	"gas_wells/internal/pkg/logger"
+	database "gas_wells/migration"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// Общая для тестов пакета БД в контейнере PostgreSQL; запускается
+// первым тестом, которому она нужна, и останавливается после всех тестов
+var (
+	dbOnce      sync.Once
+	dbContainer *postgres.PostgresContainer
+	dbPool      *pgxpool.Pool
+	dbErr       error
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if dbPool != nil {
+		dbPool.Close()
+	}
+	if dbContainer != nil {
+		_ = testcontainers.TerminateContainer(dbContainer)
+	}
+	os.Exit(code)
+}
+
+// testDB возвращает пул соединений с БД со всеми миграциями и без данных.
+// Без Docker тест пропускается.
+func testDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	dbOnce.Do(func() {
+		ctx := context.Background()
+		dbContainer, dbErr = postgres.Run(ctx, "postgres:16-alpine",
+			postgres.WithDatabase("gas_wells_test"),
+			postgres.BasicWaitStrategies(),
+		)
+		if dbErr != nil {
+			return
+		}
+		var dsn string
+		if dsn, dbErr = dbContainer.ConnectionString(ctx, "sslmode=disable"); dbErr != nil {
+			return
+		}
+		if dbPool, dbErr = pgxpool.New(ctx, dsn); dbErr != nil {
+			return
+		}
+		dbErr = database.NewMigrator(dbPool, logger.New("test")).Run(ctx)
+	})
+	require.NoError(t, dbErr)
+
+	_, err := dbPool.Exec(context.Background(),
+		`TRUNCATE wells, fields, users, audit_log RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+	return dbPool
+}
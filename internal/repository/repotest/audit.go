@@ -0,0 +1,60 @@
+// internal/repository/repotest/audit.go
+package repotest
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/auth"
+	"gas_wells/internal/repository"
+	"strings"
+	"time"
+)
+
+var _ repository.AuditRepository = (*AuditRepo)(nil)
+
+// AuditRepo - журнал аудита в памяти. Записи получают время Now,
+// увеличенное на минуту; Err - ошибка записи.
+type AuditRepo struct {
+	Entries []*entity.AuditEntry
+	Now     time.Time
+	Err     error
+}
+
+func (r *AuditRepo) Create(ctx context.Context, entry *entity.AuditEntry) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if user := auth.UserFromContext(ctx); user != nil {
+		id := user.ID
+		entry.ActorID, entry.Actor = &id, user.Email
+	}
+	r.Now = r.Now.Add(time.Minute)
+	entry.ID = int64(len(r.Entries) + 1)
+	entry.Created = r.Now
+	r.Entries = append(r.Entries, entry)
+	return nil
+}
+
+func (r *AuditRepo) List(_ context.Context, f entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	var entries []*entity.AuditEntry
+	for i := len(r.Entries) - 1; i >= 0; i-- {
+		e := r.Entries[i]
+		if (f.EntityType != "" && e.EntityType != f.EntityType) ||
+			(f.EntityID != 0 && e.EntityID != f.EntityID) ||
+			(f.Action != "" && e.Action != f.Action) ||
+			(f.Actor != "" && !strings.Contains(e.Actor, f.Actor)) ||
+			(!f.From.IsZero() && e.Created.Before(f.From)) ||
+			(!f.To.IsZero() && !e.Created.Before(f.To)) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if f.Offset >= len(entries) {
+		return nil, nil
+	}
+	entries = entries[f.Offset:]
+	if len(entries) > f.Limit {
+		entries = entries[:f.Limit]
+	}
+	return entries, nil
+}
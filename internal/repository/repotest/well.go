@@ -0,0 +1,184 @@
+// internal/repository/repotest/well.go
+
+// Package repotest - репозитории в памяти для тестов сервисов и обработчиков
+package repotest
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/repository"
+	"sort"
+	"time"
+)
+
+var _ repository.WellRepository = (*WellRepo)(nil)
+
+// WellRepo - скважины в памяти. Версии сохраняются с интервалом в час
+// от нулевого времени, записи журнала аудита - в Audit, если он задан.
+// Query - условия последней выборки списка; Err - ошибка записи,
+// ReadErr - ошибка чтения скважины.
+type WellRepo struct {
+	Wells   map[int]*entity.Well
+	Audit   *AuditRepo
+	Query   entity.WellQuery
+	Err     error
+	ReadErr error
+
+	nextID    int
+	revisions map[int][]*entity.WellRevision
+	history   map[int][]*entity.StatusChange
+	clock     time.Time
+}
+
+// record сохраняет запись журнала аудита, переданную с изменением
+func (r *WellRepo) record(ctx context.Context, entry *entity.AuditEntry) error {
+	if entry == nil || r.Audit == nil {
+		return nil
+	}
+	return r.Audit.Create(ctx, entry)
+}
+
+func (r *WellRepo) Create(ctx context.Context, well *entity.Well, entry *entity.AuditEntry) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if r.Wells == nil {
+		r.Wells = make(map[int]*entity.Well)
+	}
+	r.nextID = max(r.nextID, len(r.Wells)) + 1
+	well.ID = r.nextID
+	if r.history == nil {
+		r.history = make(map[int][]*entity.StatusChange)
+	}
+	r.history[well.ID] = []*entity.StatusChange{{WellID: well.ID, To: well.Status, Date: well.Created}}
+	since := well.Created
+	well.StatusSince = &since
+	r.save(well)
+	if entry != nil {
+		entry.EntityID = well.ID
+	}
+	return r.record(ctx, entry)
+}
+
+func (r *WellRepo) GetByID(_ context.Context, id int) (*entity.Well, error) {
+	if r.ReadErr != nil {
+		return nil, r.ReadErr
+	}
+	well, ok := r.Wells[id]
+	if !ok {
+		return nil, nil
+	}
+	clone := *well
+	return &clone, nil
+}
+
+func (r *WellRepo) Update(ctx context.Context, well *entity.Well, entry *entity.AuditEntry) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	r.save(well)
+	return r.record(ctx, entry)
+}
+
+func (r *WellRepo) Delete(ctx context.Context, id int, entry *entity.AuditEntry) error {
+	delete(r.Wells, id)
+	return r.record(ctx, entry)
+}
+
+func (r *WellRepo) List(_ context.Context, q entity.WellQuery) (*entity.WellPage, error) {
+	r.Query = q
+	wells := r.sorted(q)
+	return &entity.WellPage{Wells: wells, Total: len(wells)}, nil
+}
+
+func (r *WellRepo) Each(_ context.Context, q entity.WellQuery, fn func(*entity.Well) error) error {
+	r.Query = q
+	for _, w := range r.sorted(q) {
+		if err := fn(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *WellRepo) ListByPad(_ context.Context, padID int) ([]*entity.Well, error) {
+	var wells []*entity.Well
+	for _, w := range r.sorted(entity.WellQuery{}) {
+		if w.PadID != nil && *w.PadID == padID {
+			wells = append(wells, w)
+		}
+	}
+	return wells, nil
+}
+
+func (r *WellRepo) GetByIDAsOf(_ context.Context, id int, at time.Time) (*entity.Well, error) {
+	var well *entity.Well
+	for _, rev := range r.revisions[id] {
+		if !rev.Created.After(at) {
+			clone := *rev.Well
+			well = &clone
+		}
+	}
+	return well, nil
+}
+
+func (r *WellRepo) Revisions(_ context.Context, id int) ([]*entity.WellRevision, error) {
+	var revisions []*entity.WellRevision
+	for i := len(r.revisions[id]) - 1; i >= 0; i-- {
+		revisions = append(revisions, r.revisions[id][i])
+	}
+	return revisions, nil
+}
+
+func (r *WellRepo) GetRevision(_ context.Context, id, revision int) (*entity.WellRevision, error) {
+	if revision < 1 || revision > len(r.revisions[id]) {
+		return nil, nil
+	}
+	return r.revisions[id][revision-1], nil
+}
+
+func (r *WellRepo) ChangeStatus(ctx context.Context, well *entity.Well, change *entity.StatusChange, entry *entity.AuditEntry) error {
+	if r.history == nil {
+		r.history = make(map[int][]*entity.StatusChange)
+	}
+	r.history[well.ID] = append(r.history[well.ID], change)
+	well.Status, well.StatusSince = change.To, &change.Date
+	r.save(well)
+	return r.record(ctx, entry)
+}
+
+func (r *WellRepo) StatusHistory(_ context.Context, id int) ([]*entity.StatusChange, error) {
+	return r.history[id], nil
+}
+
+// save сохраняет копию скважины и ее новую версию
+func (r *WellRepo) save(well *entity.Well) {
+	clone := *well
+	r.Wells[well.ID] = &clone
+
+	if r.revisions == nil {
+		r.revisions = make(map[int][]*entity.WellRevision)
+	}
+	r.clock = r.clock.Add(time.Hour)
+	revision := *well
+	revisions := r.revisions[well.ID]
+	r.revisions[well.ID] = append(revisions, &entity.WellRevision{
+		WellID: well.ID, Revision: len(revisions) + 1, Well: &revision, Created: r.clock,
+	})
+}
+
+// sorted возвращает скважины по возрастанию ID с отбором по статусу
+func (r *WellRepo) sorted(q entity.WellQuery) []*entity.Well {
+	ids := make([]int, 0, len(r.Wells))
+	for id, w := range r.Wells {
+		if q.Status == "" || w.Status == q.Status {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	wells := make([]*entity.Well, len(ids))
+	for i, id := range ids {
+		wells[i] = r.Wells[id]
+	}
+	return wells
+}
@@ -0,0 +1,81 @@
+// internal/repository/survey_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type pressureSurveyRepo struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+func NewPressureSurveyRepo(db *pgxpool.Pool, log logger.Logger) PressureSurveyRepository {
+	return &pressureSurveyRepo{
+		db:     db,
+		logger: log.With("layer", "repository"),
+	}
+}
+
+// Create - сохранение замера пластового давления
+func (r *pressureSurveyRepo) Create(ctx context.Context, survey *entity.PressureSurvey) error {
+	query := `
+		INSERT INTO pressure_surveys (well_id, survey_date, ppl, gp)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(ctx, query, survey.WellID, survey.SurveyDate, survey.Ppl, survey.Gp).
+		Scan(&survey.ID, &survey.Created)
+}
+
+// ListByWell - замеры пластового давления скважины по возрастанию даты
+func (r *pressureSurveyRepo) ListByWell(ctx context.Context, wellID int) ([]*entity.PressureSurvey, error) {
+	query := `
+		SELECT id, well_id, survey_date, ppl, gp, created_at
+		FROM pressure_surveys
+		WHERE well_id = $1
+		ORDER BY survey_date, id
+	`
+	rows, err := r.db.Query(ctx, query, wellID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var surveys []*entity.PressureSurvey
+	for rows.Next() {
+		s := &entity.PressureSurvey{}
+		err := rows.Scan(
+			&s.ID,
+			&s.WellID,
+			&s.SurveyDate,
+			&s.Ppl,
+			&s.Gp,
+			&s.Created,
+		)
+		if err != nil {
+			return nil, err
+		}
+		surveys = append(surveys, s)
+	}
+	return surveys, rows.Err()
+}
+
+// Delete - удаление замера
+func (r *pressureSurveyRepo) Delete(ctx context.Context, wellID, id int) error {
+	result, err := r.db.Exec(ctx,
+		`DELETE FROM pressure_surveys WHERE id = $1 AND well_id = $2`, id, wellID)
+	if err != nil {
+		r.logger.Error("failed to delete pressure survey", "id", id, "error", err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("pressure survey not found")
+	}
+	return nil
+}
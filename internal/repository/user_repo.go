@@ -1 +1,157 @@
+// internal/repository/user_repo.go
 package repository
+
+import (
+	"context"
+	"errors"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type userRepo struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+func NewUserRepo(db *pgxpool.Pool, log logger.Logger) UserRepository {
+	return &userRepo{
+		db:     db,
+		logger: log.With("layer", "repository"),
+	}
+}
+
+const userColumns = `id, email, username, password_hash, role, created_at`
+
+func scanUser(row pgx.Row) (*entity.User, error) {
+	user := &entity.User{}
+	err := row.Scan(&user.ID, &user.Email, &user.Username, &user.PasswordHash, &user.Role, &user.Created)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Create - добавление пользователя
+func (r *userRepo) Create(ctx context.Context, user *entity.User) error {
+	query := `
+		INSERT INTO users (email, username, password_hash, role)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(ctx, query, user.Email, user.Username, user.PasswordHash, user.Role).
+		Scan(&user.ID, &user.Created)
+}
+
+// GetByEmail - пользователь по email (без учета регистра)
+func (r *userRepo) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return scanUser(r.db.QueryRow(ctx,
+		`SELECT `+userColumns+` FROM users WHERE lower(email) = lower($1)`, email))
+}
+
+// GetByID - пользователь по ID
+func (r *userRepo) GetByID(ctx context.Context, id int) (*entity.User, error) {
+	return scanUser(r.db.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id))
+}
+
+// List - все пользователи
+func (r *userRepo) List(ctx context.Context) ([]*entity.User, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+userColumns+` FROM users ORDER BY email`)
+	if err != nil {
+		r.logger.Error("failed to list users", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entity.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// UpdateRole - смена роли пользователя
+func (r *userRepo) UpdateRole(ctx context.Context, id int, role string) error {
+	_, err := r.db.Exec(ctx, `UPDATE users SET role = $2 WHERE id = $1`, id, role)
+	return err
+}
+
+type refreshTokenRepo struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+func NewRefreshTokenRepo(db *pgxpool.Pool, log logger.Logger) RefreshTokenRepository {
+	return &refreshTokenRepo{
+		db:     db,
+		logger: log.With("layer", "repository"),
+	}
+}
+
+// Create - сохранение выданного refresh-токена
+func (r *refreshTokenRepo) Create(ctx context.Context, token *entity.RefreshToken) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO refresh_tokens (id, user_id, expires_at) VALUES ($1, $2, $3)`,
+		token.ID, token.UserID, token.Expires)
+	return err
+}
+
+// Get - refresh-токен по jti
+func (r *refreshTokenRepo) Get(ctx context.Context, id string) (*entity.RefreshToken, error) {
+	token := &entity.RefreshToken{}
+	err := r.db.QueryRow(ctx,
+		`SELECT id, user_id, expires_at, revoked_at, rotated_at FROM refresh_tokens WHERE id = $1`, id,
+	).Scan(&token.ID, &token.UserID, &token.Expires, &token.RevokedAt, &token.RotatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Revoke - отзыв токена; условие revoked_at IS NULL не дает использовать
+// один refresh-токен в двух параллельных запросах
+func (r *refreshTokenRepo) Revoke(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		r.logger.Error("failed to revoke refresh token", "error", err)
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// Rotate - отзыв токена при обмене на новую пару
+func (r *refreshTokenRepo) Rotate(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = now(), rotated_at = now() WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		r.logger.Error("failed to rotate refresh token", "error", err)
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// RevokeAll - отзыв всех токенов пользователя и удаление истекших.
+// Отметка обмена снимается, чтобы обмененные токены не принимались
+// и в окне параллельного обмена.
+func (r *refreshTokenRepo) RevokeAll(ctx context.Context, userID int) error {
+	if _, err := r.db.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = COALESCE(revoked_at, now()), rotated_at = NULL
+		WHERE user_id = $1 AND (revoked_at IS NULL OR rotated_at IS NOT NULL)`, userID); err != nil {
+		return err
+	}
+	_, err := r.db.Exec(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1 AND expires_at < now()`, userID)
+	return err
+}
@@ -3,10 +3,16 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/auth"
 	"gas_wells/internal/pkg/logger"
 	"log/slog"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -25,16 +31,24 @@ func NewWellRepo(db *pgxpool.Pool, log logger.Logger) WellRepository {
 }
 
 // Create - добавление новой скважины
-func (r *wellRepo) Create(ctx context.Context, well *entity.Well) error {
+func (r *wellRepo) Create(ctx context.Context, well *entity.Well, entry *entity.AuditEntry) error {
 	query := `
 		INSERT INTO wells (name, location, gammag, temp, tempust, depth,
 					pbuf, ptb, ppl, pz, q, roughness, diametr, a, b, mu,
-					wgf, rog, hw, qmin, pmax, status)
+					wgf, rog, hw, qmin, pmax, status, qmin_model, liquid_loading,
+					mu_manual, pad_id, choke, pline, pz_manual)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8,$9, $10, $11, $12, $13, $14,
-				$15, $16, $17, $18, $19, $20, $21, $22) 
-		RETURNING id, created_at
+				$15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26,
+				$27, $28, $29) 
+		RETURNING id, created_at, updated_at
 	`
-	err := r.db.QueryRow(
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
 		ctx,
 		query,
 		well.Name,
@@ -59,18 +73,63 @@ func (r *wellRepo) Create(ctx context.Context, well *entity.Well) error {
 		well.Qmin,
 		well.Pmax,
 		well.Status,
-	).Scan(&well.ID, &well.Created)
+		well.QminModel,
+		well.LiquidLoading,
+		well.MuManual,
+		well.PadID,
+		well.Choke,
+		well.Pline,
+		well.PzManual,
+	).Scan(&well.ID, &well.Created, &well.Updated)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	// Начальное состояние - первая запись истории состояний
+	initial := &entity.StatusChange{WellID: well.ID, To: well.Status, Date: well.Created}
+	if err := r.saveStatusChange(ctx, tx, initial); err != nil {
+		return err
+	}
+	well.StatusSince = &initial.Date
+	if err := r.saveRevision(ctx, tx, well); err != nil {
+		return err
+	}
+	if entry != nil {
+		entry.EntityID = well.ID
+	}
+	if err := r.saveAuditEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
+// Столбцы, загружаемые для скважины целиком (scanWell)
+const wellColumns = `id, name, location, gammag, temp, tempust, depth,
+	pbuf, ptb, ppl, pz, q, roughness, diametr, a, b, mu,
+	wgf, rog, hw, qmin, pmax, status, qmin_model, liquid_loading,
+	mu_manual, pad_id, choke, pline, pz_manual, created_at, updated_at, ` + wellStatusSince
+
 // GetByID - получение скважины по ID
 func (r *wellRepo) GetByID(ctx context.Context, id int) (*entity.Well, error) {
-	query := `SELECT * FROM wells WHERE id = $1`
+	query := `
+		SELECT ` + wellColumns + `
+		FROM wells WHERE id = $1
+	`
+	well, err := scanWell(r.db.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return well, nil
+}
+
+// scanWell читает строку со столбцами wellColumns
+func scanWell(row pgx.Row) (*entity.Well, error) {
 	well := &entity.Well{}
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := row.Scan(
+		&well.ID,
 		&well.Name,
 		&well.Location,
 		&well.GammaG,
@@ -93,10 +152,17 @@ func (r *wellRepo) GetByID(ctx context.Context, id int) (*entity.Well, error) {
 		&well.Qmin,
 		&well.Pmax,
 		&well.Status,
+		&well.QminModel,
+		&well.LiquidLoading,
+		&well.MuManual,
+		&well.PadID,
+		&well.Choke,
+		&well.Pline,
+		&well.PzManual,
+		&well.Created,
+		&well.Updated,
+		&well.StatusSince,
 	)
-	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, nil
-	}
 	if err != nil {
 		return nil, err
 	}
@@ -104,16 +170,25 @@ func (r *wellRepo) GetByID(ctx context.Context, id int) (*entity.Well, error) {
 }
 
 // Update - обновление данных скважины
-func (r *wellRepo) Update(ctx context.Context, well *entity.Well) error {
+func (r *wellRepo) Update(ctx context.Context, well *entity.Well, entry *entity.AuditEntry) error {
 	query := `
 	UPDATE wells 
 	SET name=$1, location=$2, gammag=$3, temp=$4, tempust=$5, 
 		depth=$6, pbuf=$7, ptb=$8, ppl=$9, pz=$10, q=$11, roughness=$12,
 		diametr=$13, a=$14, b=$15, mu=$16, wgf=$17,	rog=$18, hw=$19,
-		qmin=$20, pmax=$21, status=$22, updated_at = NOW() 
-		WHERE id = $23	
+		qmin=$20, pmax=$21, status=$22, qmin_model=$23, liquid_loading=$24,
+		mu_manual=$25, pad_id=$26, choke=$27, pline=$28, pz_manual=$29,
+		updated_at = NOW()
+		WHERE id = $30
+		RETURNING updated_at
 	`
-	result, err := r.db.Exec(
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
 		ctx,
 		query,
 		well.Name,
@@ -138,22 +213,224 @@ func (r *wellRepo) Update(ctx context.Context, well *entity.Well) error {
 		well.Qmin,
 		well.Pmax,
 		well.Status,
+		well.QminModel,
+		well.LiquidLoading,
+		well.MuManual,
+		well.PadID,
+		well.Choke,
+		well.Pline,
+		well.PzManual,
 		well.ID,
-	)
+	).Scan(&well.Updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("no rows affected (well not found)")
+	}
 	if err != nil {
 		return err
 	}
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return errors.New("no rows affected (well not found)")
+	// Строка скважины заблокирована UPDATE до конца транзакции,
+	// поэтому номер следующей версии не займет параллельное сохранение
+	if err := r.saveRevision(ctx, tx, well); err != nil {
+		return err
+	}
+	if err := r.saveAuditEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// saveAuditEntry - запись журнала аудита в транзакции изменения скважины
+func (r *wellRepo) saveAuditEntry(ctx context.Context, tx pgx.Tx, entry *entity.AuditEntry) error {
+	if entry == nil {
+		return nil
+	}
+	if err := insertAuditEntry(ctx, tx, entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
 	}
 	return nil
 }
 
-// Delete - удаление скважины по ID
-func (r *wellRepo) Delete(ctx context.Context, id int) error {
+// saveRevision - новая версия скважины с текущими параметрами
+// от имени пользователя из контекста запроса
+func (r *wellRepo) saveRevision(ctx context.Context, tx pgx.Tx, well *entity.Well) error {
+	snapshot := *well
+	snapshot.Warnings, snapshot.HydrateRisk = nil, ""
+	data, err := json.Marshal(&snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode well revision: %w", err)
+	}
+
+	actorID, actor := actorFrom(ctx)
+	query := `
+		INSERT INTO well_revisions (well_id, revision, data, actor_id, actor)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4
+		FROM well_revisions WHERE well_id = $1
+	`
+	_, err = tx.Exec(ctx, query, well.ID, data, actorID, actor)
+	return err
+}
+
+// actorFrom - пользователь из контекста запроса для отметки об изменении
+func actorFrom(ctx context.Context) (*int, string) {
+	if user := auth.UserFromContext(ctx); user != nil {
+		id := user.ID
+		return &id, user.Email
+	}
+	return nil, ""
+}
+
+// ChangeStatus - переход скважины в состояние change.To: состояние
+// скважины, запись истории, новая версия и запись журнала аудита
+// сохраняются одной транзакцией
+func (r *wellRepo) ChangeStatus(ctx context.Context, well *entity.Well, change *entity.StatusChange, entry *entity.AuditEntry) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx,
+		`UPDATE wells SET status = $2, updated_at = NOW() WHERE id = $1 RETURNING updated_at`,
+		well.ID, change.To,
+	).Scan(&well.Updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("no rows affected (well not found)")
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.saveStatusChange(ctx, tx, change); err != nil {
+		return err
+	}
+	well.Status, well.StatusSince = change.To, &change.Date
+	if err := r.saveRevision(ctx, tx, well); err != nil {
+		return err
+	}
+	if err := r.saveAuditEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *wellRepo) saveStatusChange(ctx context.Context, tx pgx.Tx, change *entity.StatusChange) error {
+	change.ActorID, change.Actor = actorFrom(ctx)
+	query := `
+		INSERT INTO well_status_history (well_id, from_status, to_status, reason, changed_on, actor_id, actor)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, changed_on, created_at
+	`
+	return tx.QueryRow(ctx, query,
+		change.WellID, change.From, change.To, change.Reason, change.Date, change.ActorID, change.Actor,
+	).Scan(&change.ID, &change.Date, &change.Created)
+}
+
+// StatusHistory - переходы скважины по датам, начиная с начального состояния
+func (r *wellRepo) StatusHistory(ctx context.Context, id int) ([]*entity.StatusChange, error) {
+	query := `
+		SELECT id, well_id, from_status, to_status, reason, changed_on, actor_id, actor, created_at
+		FROM well_status_history
+		WHERE well_id = $1
+		ORDER BY changed_on, id
+	`
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to list well status history", "well_id", id, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*entity.StatusChange
+	for rows.Next() {
+		c := &entity.StatusChange{}
+		err := rows.Scan(&c.ID, &c.WellID, &c.From, &c.To, &c.Reason, &c.Date, &c.ActorID, &c.Actor, &c.Created)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, c)
+	}
+	return history, rows.Err()
+}
+
+// GetByIDAsOf - скважина в версии, действовавшей в момент at
+func (r *wellRepo) GetByIDAsOf(ctx context.Context, id int, at time.Time) (*entity.Well, error) {
+	query := `
+		SELECT data FROM well_revisions
+		WHERE well_id = $1 AND created_at <= $2
+		ORDER BY revision DESC
+		LIMIT 1
+	`
+	var data []byte
+	err := r.db.QueryRow(ctx, query, id, at).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	well := &entity.Well{}
+	if err := json.Unmarshal(data, well); err != nil {
+		return nil, fmt.Errorf("failed to decode well revision: %w", err)
+	}
+	well.ID = id
+	return well, nil
+}
+
+const wellRevisionColumns = `well_id, revision, data, actor_id, actor, created_at`
+
+func scanWellRevision(row pgx.Row) (*entity.WellRevision, error) {
+	rev := &entity.WellRevision{Well: &entity.Well{}}
+	var data []byte
+	err := row.Scan(&rev.WellID, &rev.Revision, &data, &rev.ActorID, &rev.Actor, &rev.Created)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, rev.Well); err != nil {
+		return nil, fmt.Errorf("failed to decode revision %d of well %d: %w", rev.Revision, rev.WellID, err)
+	}
+	rev.Well.ID = rev.WellID
+	return rev, nil
+}
+
+// Revisions - версии скважины, новые первыми
+func (r *wellRepo) Revisions(ctx context.Context, id int) ([]*entity.WellRevision, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+wellRevisionColumns+` FROM well_revisions WHERE well_id = $1 ORDER BY revision DESC`, id)
+	if err != nil {
+		r.logger.Error("failed to list well revisions", "well_id", id, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*entity.WellRevision
+	for rows.Next() {
+		rev, err := scanWellRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// GetRevision - версия revision скважины
+func (r *wellRepo) GetRevision(ctx context.Context, id, revision int) (*entity.WellRevision, error) {
+	return scanWellRevision(r.db.QueryRow(ctx,
+		`SELECT `+wellRevisionColumns+` FROM well_revisions WHERE well_id = $1 AND revision = $2`, id, revision))
+}
+
+// Delete - удаление скважины по ID вместе с записью журнала аудита
+func (r *wellRepo) Delete(ctx context.Context, id int, entry *entity.AuditEntry) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `DELETE FROM wells WHERE id = $1`
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := tx.Exec(ctx, query, id)
 	if err != nil {
 		slog.Error("failed to delete well", "id", id, "error", err)
 		return err
@@ -163,18 +440,233 @@ func (r *wellRepo) Delete(ctx context.Context, id int) error {
 	if rowsAffected == 0 {
 		return errors.New("well not found")
 	}
-	return nil
+	if err := r.saveAuditEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// wellStatusSinceExpr - дата перехода скважины в текущее состояние
+const wellStatusSinceExpr = `(SELECT MAX(changed_on) FROM well_status_history h WHERE h.well_id = wells.id)`
+
+const wellStatusSince = wellStatusSinceExpr + ` AS status_since`
+
+// Столбцы, загружаемые для списков скважин
+const wellListColumns = `id, name, location, gammag, tempust, depth, pbuf, q, wgf, rog,
+	qmin, liquid_loading, status, pad_id, created_at, updated_at, ` + wellStatusSince
+
+// wellSortColumn - выражение сортировки списка и его тип в СУБД: значение
+// курсора передается текстом и приводится к этому типу
+type wellSortColumn struct {
+	expr string
+	typ  string
+}
+
+// wellSortColumns - выражения сортировки по ключам entity.WellSortKeys;
+// выражения не содержат NULL, иначе сравнение с курсором теряет строки
+var wellSortColumns = map[string]wellSortColumn{
+	"name":         {"name", "text"},
+	"location":     {"location", "text"},
+	"status":       {"status", "text"},
+	"status_since": {"COALESCE(" + wellStatusSinceExpr + ", '-infinity')", "date"},
+	"pbuf":         {"pbuf", "double precision"},
+	"q":            {"q", "double precision"},
+	"depth":        {"depth", "double precision"},
+	"qmin":         {"qmin", "double precision"},
+	"created":      {"COALESCE(created_at, '-infinity')", "timestamp"},
+	"updated":      {"COALESCE(updated_at, '-infinity')", "timestamp"},
+}
+
+// List - страница списка скважин по условиям запроса. Страницы выбираются
+// по ключу (значение сортировки, id) от курсора, а не смещением, поэтому
+// добавление и удаление скважин не сдвигает соседние страницы.
+func (r *wellRepo) List(ctx context.Context, q entity.WellQuery) (*entity.WellPage, error) {
+	sortKey, col, err := wellSort(q)
+	if err != nil {
+		return nil, err
+	}
+	conditions, args := wellConditions(q)
+
+	page := &entity.WellPage{}
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM wells`+wellWhere(conditions), args...).Scan(&page.Total); err != nil {
+		r.logger.Error("failed to count wells", "error", err)
+		return nil, err
+	}
+
+	// Предыдущая страница выбирается в обратном порядке от курсора и разворачивается
+	backward := q.Before != nil
+	cursor := q.After
+	if backward {
+		cursor = q.Before
+	}
+	op, dir := ">", "ASC"
+	if q.Desc != backward {
+		op, dir = "<", "DESC"
+	}
+	if cursor != nil {
+		args = append(args, cursor.Value, cursor.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d::text::%s, $%d)",
+			col.expr, op, len(args)-1, col.typ, len(args)))
+	}
+
+	query := `
+		SELECT ` + wellListColumns + `, (` + col.expr + `)::text
+		FROM wells` + wellWhere(conditions) + fmt.Sprintf("\n\t\tORDER BY %s %s, id %s", col.expr, dir, dir)
+	if q.Limit > 0 {
+		// Лишняя строка показывает, есть ли следующая страница
+		args = append(args, q.Limit+1)
+		query += fmt.Sprintf("\n\t\tLIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to list wells", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		well, err := scanWellListRow(rows, &key)
+		if err != nil {
+			return nil, err
+		}
+		page.Wells = append(page.Wells, well)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	more := q.Limit > 0 && len(page.Wells) > q.Limit
+	if more {
+		page.Wells, keys = page.Wells[:q.Limit], keys[:q.Limit]
+	}
+	if backward {
+		slices.Reverse(page.Wells)
+		slices.Reverse(keys)
+	}
+	if q.Limit > 0 && len(page.Wells) > 0 {
+		at := func(i int) string {
+			return entity.WellCursor{Sort: sortKey, Value: keys[i], ID: page.Wells[i].ID}.Encode()
+		}
+		// Страница перед курсором всегда имеет продолжение - страницу, с которой пришли
+		if more && !backward || backward {
+			page.Next = at(len(keys) - 1)
+		}
+		if more && backward || q.After != nil {
+			page.Prev = at(0)
+		}
+	}
+	return page, nil
+}
+
+// Each - все скважины по условиям запроса в порядке сортировки, целиком
+// (столбцы wellColumns). Строки читаются из курсора СУБД по одной и передаются
+// в fn, поэтому большой список не загружается в память; курсоры страниц
+// и Limit не учитываются. Ошибка fn прерывает выборку.
+func (r *wellRepo) Each(ctx context.Context, q entity.WellQuery, fn func(*entity.Well) error) error {
+	_, col, err := wellSort(q)
+	if err != nil {
+		return err
+	}
+	conditions, args := wellConditions(q)
+	dir := "ASC"
+	if q.Desc {
+		dir = "DESC"
+	}
+	query := `
+		SELECT ` + wellColumns + `
+		FROM wells` + wellWhere(conditions) + fmt.Sprintf("\n\t\tORDER BY %s %s, id %s", col.expr, dir, dir)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to list wells", "error", err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		well, err := scanWell(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(well); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// wellSort - ключ и выражение сортировки запроса; пустой ключ - name
+func wellSort(q entity.WellQuery) (string, wellSortColumn, error) {
+	key := q.Sort
+	if key == "" {
+		key = "name"
+	}
+	col, ok := wellSortColumns[key]
+	if !ok {
+		return "", col, fmt.Errorf("unknown sort key %q", key)
+	}
+	return key, col, nil
+}
+
+// wellConditions - условия отбора скважин запроса и их параметры
+func wellConditions(q entity.WellQuery) ([]string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if q.Search != "" {
+		where(`(name ILIKE '%%' || $%[1]d || '%%' ESCAPE '\' OR location ILIKE '%%' || $%[1]d || '%%' ESCAPE '\')`,
+			likeEscaper.Replace(q.Search))
+	}
+	if q.Status != "" {
+		where("status = $%d", q.Status)
+	}
+	for _, f := range []struct {
+		column string
+		rng    entity.Range
+	}{{"q", q.Q}, {"pbuf", q.Pbuf}, {"depth", q.Depth}} {
+		if f.rng.Min != nil {
+			where(f.column+" >= $%d", *f.rng.Min)
+		}
+		if f.rng.Max != nil {
+			where(f.column+" <= $%d", *f.rng.Max)
+		}
+	}
+	return conditions, args
 }
 
-// List - получение списка всех скважин (с пагинацией)
-func (r *wellRepo) List(ctx context.Context, limit, offset int) ([]*entity.Well, error) {
+// likeEscaper экранирует символы шаблона LIKE, чтобы строка поиска
+// сравнивалась буквально
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func wellWhere(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "\n\t\tWHERE " + strings.Join(conditions, " AND ")
+}
+
+// ListByPad - скважины куста по названию
+func (r *wellRepo) ListByPad(ctx context.Context, padID int) ([]*entity.Well, error) {
 	query := `
-		SELECT id, name, location, pbuf, status, created, updated
-		FROM wells 
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
+		SELECT ` + wellListColumns + `
+		FROM wells
+		WHERE pad_id = $1
+		ORDER BY name
 	`
-	rows, err := r.db.Query(ctx, query, limit, offset)
+	return r.queryList(ctx, query, padID)
+}
+
+func (r *wellRepo) queryList(ctx context.Context, query string, args ...any) ([]*entity.Well, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -182,16 +674,7 @@ func (r *wellRepo) List(ctx context.Context, limit, offset int) ([]*entity.Well,
 
 	var wells []*entity.Well
 	for rows.Next() {
-		well := &entity.Well{}
-		err := rows.Scan(
-			&well.ID,
-			&well.Name,
-			&well.Location,
-			&well.Pbuf,
-			&well.Status,
-			&well.Created,
-			&well.Updated,
-		)
+		well, err := scanWellListRow(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -203,3 +686,32 @@ func (r *wellRepo) List(ctx context.Context, limit, offset int) ([]*entity.Well,
 	}
 	return wells, nil
 }
+
+// scanWellListRow читает строку со столбцами wellListColumns;
+// extra - столбцы, выбранные после них
+func scanWellListRow(rows pgx.Rows, extra ...any) (*entity.Well, error) {
+	well := &entity.Well{}
+	dest := append([]any{
+		&well.ID,
+		&well.Name,
+		&well.Location,
+		&well.GammaG,
+		&well.TempUst,
+		&well.Depth,
+		&well.Pbuf,
+		&well.Q,
+		&well.WGF,
+		&well.Rog,
+		&well.Qmin,
+		&well.LiquidLoading,
+		&well.Status,
+		&well.PadID,
+		&well.Created,
+		&well.Updated,
+		&well.StatusSince,
+	}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return well, nil
+}
@@ -1,45 +1,181 @@
 // repository/well_repo_test.go
 package repository_test
 
-/*func TestWellRepository(t *testing.T) {
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository"
+	"gas_wells/internal/service"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// testWell - скважина в добыче с параметрами для расчетов
+func testWell(name string, q, pbuf float64) *entity.Well {
+	return &entity.Well{
+		Name: name, Location: "Северное", GammaG: 0.6, Temp: 360, TempUst: 290, Depth: 3000,
+		Pbuf: pbuf, Ppl: 20e6, Q: q, Roughness: 2e-5, Diameter: 0.062, Status: entity.StatusProducing,
+	}
+}
+
+func TestWellRepositoryEach(t *testing.T) {
 	ctx := context.Background()
+	repo := repository.NewWellRepo(testDB(t), logger.New("test"))
 
-	// Запуск PostgreSQL в контейнере
-	pgContainer, err := postgres.RunContainer(ctx,
-		testcontainers.WithImage("postgres:16"),
-		postgres.WithDatabase("test_db"),
-		postgres.WithUsername("user"),
-		postgres.WithPassword("password"),
-	)
-	assert.NoError(t, err)
-	defer pgContainer.Terminate(ctx)
-
-	connStr, err := pgContainer.ConnectionString(ctx)
-	assert.NoError(t, err)
-
-	// Подключение к БД
-	pool, err := pgxpool.New(ctx, connStr)
-	assert.NoError(t, err)
-	defer pool.Close()
-
-	// Миграции (используйте golang-migrate)
-	// ...
-
-	repo := repository.NewWellRepo(pool, log)
-
-	t.Run("Create and GetByID", func(t *testing.T) {
-		well := &entity.Well{
-			Name: "Test Well",
-			Temp: 100.5,
-			Pbuf: 20.0,
-			Pmax: 85.0,
-		}
-		err := repo.Create(ctx, well)
-		assert.NoError(t, err)
-		assert.NotZero(t, well.ID)
+	for _, w := range []*entity.Well{
+		testWell("101", 150e3, 10e6),
+		testWell("102", 80e3, 9e6),
+		testWell("103", 5e3, 8e6),
+	} {
+		require.NoError(t, repo.Create(ctx, w, nil))
+	}
 
-		found, err := repo.GetByID(ctx, well.ID)
-		assert.NoError(t, err)
-		assert.Equal(t, well.Name, found.Name)
+	qMin := 10e3
+	var names []string
+	err := repo.Each(ctx, entity.WellQuery{Q: entity.Range{Min: &qMin}, Sort: "q"}, func(w *entity.Well) error {
+		names = append(names, w.Name)
+		assert.Equal(t, 0.062, w.Diameter, "скважина загружается целиком")
+		assert.NotNil(t, w.StatusSince)
+		return nil
 	})
-}*/
+	require.NoError(t, err)
+	assert.Equal(t, []string{"102", "101"}, names)
+}
+
+// TestExportWellsFromDB - выгрузка через репозиторий PostgreSQL:
+// отбор и порядок строк задает SQL, а не фильтр в памяти
+func TestExportWellsFromDB(t *testing.T) {
+	ctx := context.Background()
+	log := logger.New("test")
+	s := service.NewWellService(repository.NewWellRepo(testDB(t), log), nil, log)
+
+	for _, w := range []*entity.Well{
+		testWell("101", 150e3, 10e6),
+		testWell("102", 80e3, 9e6),
+		testWell("103", 0, 8e6),
+	} {
+		_, err := s.CreateWell(ctx, w)
+		require.NoError(t, err)
+	}
+
+	book, err := s.ExportWells(ctx, entity.WellQuery{Search: "10", Sort: "pbuf"})
+	require.NoError(t, err)
+	defer book.Close()
+	var buf bytes.Buffer
+	_, err = book.WriteTo(&buf)
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+	wells, err := f.GetRows("Скважины")
+	require.NoError(t, err)
+	require.Len(t, wells, 4)
+	assert.Equal(t, "103", wells[1][1])
+	assert.Equal(t, "101", wells[3][1])
+
+	results, err := f.GetRows("Расчет")
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	header := map[string]int{}
+	for i, h := range results[0] {
+		header[h] = i
+	}
+	assert.NotEmpty(t, results[3][header["Pзаб, Па"]], "сохраненное расчетное Pz")
+}
+
+// TestWellRepositoryListPages - страницы по курсору при совпадающих значениях
+// сортировки: порядок задает id, строки не теряются и не повторяются,
+// а переход назад возвращает ту же страницу
+func TestWellRepositoryListPages(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewWellRepo(testDB(t), logger.New("test"))
+
+	// Дебиты 100, 100, 100, 200, 200, 300, 300 тыс. м³/сут
+	var ids []int
+	for i, q := range []float64{100e3, 100e3, 100e3, 200e3, 200e3, 300e3, 300e3} {
+		w := testWell(fmt.Sprintf("%d", 101+i), q, 10e6)
+		require.NoError(t, repo.Create(ctx, w, nil))
+		ids = append(ids, w.ID)
+	}
+
+	for _, desc := range []bool{false, true} {
+		q := entity.WellQuery{Sort: "q", Desc: desc, Limit: 2}
+		var pages []*entity.WellPage
+		var seen []int
+		for {
+			page, err := repo.List(ctx, q)
+			require.NoError(t, err)
+			assert.Equal(t, len(ids), page.Total)
+			pages = append(pages, page)
+			for _, w := range page.Wells {
+				seen = append(seen, w.ID)
+			}
+			if page.Next == "" {
+				break
+			}
+			q.After, err = entity.DecodeWellCursor(page.Next, "q")
+			require.NoError(t, err)
+			require.Less(t, len(pages), len(ids), "список страниц конечен")
+		}
+
+		want := slices.Clone(ids)
+		if desc {
+			slices.Reverse(want)
+		}
+		assert.Equal(t, want, seen, "desc=%v", desc)
+		require.Len(t, pages, 4)
+		assert.Empty(t, pages[0].Prev)
+		assert.Len(t, pages[3].Wells, 1)
+
+		// Назад от каждой страницы - предыдущая страница целиком
+		for i := len(pages) - 1; i > 0; i-- {
+			before, err := entity.DecodeWellCursor(pages[i].Prev, "q")
+			require.NoError(t, err)
+			page, err := repo.List(ctx, entity.WellQuery{Sort: "q", Desc: desc, Limit: 2, Before: before})
+			require.NoError(t, err)
+			assert.Equal(t, wellIDs(pages[i-1].Wells), wellIDs(page.Wells), "desc=%v, page %d", desc, i)
+			assert.Equal(t, pages[i-1].Prev == "", page.Prev == "")
+		}
+	}
+}
+
+func TestWellRepositorySearch(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewWellRepo(testDB(t), logger.New("test"))
+
+	for _, name := range []string{"100%", "1000", "A_1", "AB1", `C\1`} {
+		require.NoError(t, repo.Create(ctx, testWell(name, 100e3, 10e6), nil))
+	}
+
+	// Символы шаблона LIKE в строке поиска сравниваются буквально
+	for search, want := range map[string][]string{
+		"0%":  {"100%"},
+		"a_":  {"A_1"},
+		`c\`:  {`C\1`},
+		"100": {"100%", "1000"},
+	} {
+		page, err := repo.List(ctx, entity.WellQuery{Search: search})
+		require.NoError(t, err)
+		var names []string
+		for _, w := range page.Wells {
+			names = append(names, w.Name)
+		}
+		assert.ElementsMatch(t, want, names, search)
+	}
+}
+
+func wellIDs(wells []*entity.Well) []int {
+	ids := make([]int, len(wells))
+	for i, w := range wells {
+		ids[i] = w.ID
+	}
+	return ids
+}
@@ -0,0 +1,170 @@
+// internal/repository/welltest_repo.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type wellTestRepo struct {
+	db     *pgxpool.Pool
+	logger logger.Logger
+}
+
+func NewWellTestRepo(db *pgxpool.Pool, log logger.Logger) WellTestRepository {
+	return &wellTestRepo{
+		db:     db,
+		logger: log.With("layer", "repository"),
+	}
+}
+
+// Create - сохранение исследования вместе с режимами в одной транзакции
+func (r *wellTestRepo) Create(ctx context.Context, test *entity.WellTest) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO well_tests (well_id, test_date, test_type)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(ctx, query, test.WellID, test.TestDate, test.Type).
+		Scan(&test.ID, &test.Created)
+	if err != nil {
+		return err
+	}
+
+	for i, p := range test.Points {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO well_test_points (test_id, seq, q, pz, ppl)
+			VALUES ($1, $2, $3, $4, $5)
+		`, test.ID, i, p.Q, p.Pz, p.Ppl)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetByID - получение исследования по ID
+func (r *wellTestRepo) GetByID(ctx context.Context, id int) (*entity.WellTest, error) {
+	query := `
+		SELECT id, well_id, test_date, test_type, created_at
+		FROM well_tests WHERE id = $1
+	`
+	test := &entity.WellTest{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&test.ID,
+		&test.WellID,
+		&test.TestDate,
+		&test.Type,
+		&test.Created,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadPoints(ctx, []*entity.WellTest{test}); err != nil {
+		return nil, err
+	}
+	return test, nil
+}
+
+// ListByWell - исследования скважины, новые первыми
+func (r *wellTestRepo) ListByWell(ctx context.Context, wellID int) ([]*entity.WellTest, error) {
+	query := `
+		SELECT id, well_id, test_date, test_type, created_at
+		FROM well_tests
+		WHERE well_id = $1
+		ORDER BY test_date DESC, id DESC
+	`
+	rows, err := r.db.Query(ctx, query, wellID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tests []*entity.WellTest
+	for rows.Next() {
+		test := &entity.WellTest{}
+		err := rows.Scan(
+			&test.ID,
+			&test.WellID,
+			&test.TestDate,
+			&test.Type,
+			&test.Created,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, test)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadPoints(ctx, tests); err != nil {
+		return nil, err
+	}
+	return tests, nil
+}
+
+// Delete - удаление исследования (режимы удаляются каскадно)
+func (r *wellTestRepo) Delete(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM well_tests WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("failed to delete well test", "id", id, "error", err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("well test not found")
+	}
+	return nil
+}
+
+// loadPoints - загрузка режимов для набора исследований одним запросом
+func (r *wellTestRepo) loadPoints(ctx context.Context, tests []*entity.WellTest) error {
+	if len(tests) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(tests))
+	byID := make(map[int]*entity.WellTest, len(tests))
+	for i, t := range tests {
+		ids[i] = t.ID
+		byID[t.ID] = t
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT test_id, q, pz, ppl
+		FROM well_test_points
+		WHERE test_id = ANY($1)
+		ORDER BY test_id, seq
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var testID int
+		var p entity.WellTestPoint
+		if err := rows.Scan(&testID, &p.Q, &p.Pz, &p.Ppl); err != nil {
+			return err
+		}
+		byID[testID].Points = append(byID[testID].Points, p)
+	}
+	return rows.Err()
+}
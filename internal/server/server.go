@@ -1,6 +1,7 @@
 package server
 
 import (
+	"gas_wells/internal/entity"
 	"gas_wells/internal/handler"
 	"gas_wells/internal/pkg/logger"
 	"net/http"
@@ -25,21 +26,138 @@ func New(logger logger.Logger) *Server {
 	return s
 }
 
-func (s *Server) SetupRoutes(wellHandler *handler.WellHandler) {
+// Handlers - обработчики HTTP-запросов приложения
+type Handlers struct {
+	Well        *handler.WellHandler
+	WellTest    *handler.WellTestHandler
+	Measurement *handler.MeasurementHandler
+	Production  *handler.ProductionHandler
+	MatBal      *handler.MaterialBalanceHandler
+	Field       *handler.FieldHandler
+	Network     *handler.NetworkHandler
+	Docs        *handler.DocsHandler
+	Auth        *handler.AuthHandler
+	User        *handler.UserHandler
+	Audit       *handler.AuditHandler
+}
+
+func (s *Server) SetupRoutes(h Handlers) {
 	s.router.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/wells", http.StatusFound)
 	})
 
-	s.router.Route("/wells", func(r chi.Router) {
-		r.Get("/", wellHandler.ListWells)
-		r.Get("/create", wellHandler.CreateWellForm)
-		r.Post("/", wellHandler.CreateWell)
-		r.Get("/{id}", wellHandler.GetWell)
-		r.Get("/{id}/edit", wellHandler.EditWellForm)
-		r.Put("/{id}", wellHandler.UpdateWell)
-		r.Delete("/{id}", wellHandler.DeleteWell)
+	// Вход и обновление токенов доступны без аутентификации
+	s.router.Route("/auth", func(r chi.Router) {
+		r.Get("/login", h.Auth.LoginForm)
+		r.Post("/login", h.Auth.Login)
+		r.Post("/refresh", h.Auth.Refresh)
+		r.Post("/logout", h.Auth.Logout)
+		r.With(h.Auth.RequireAuth).Get("/me", h.Auth.Me)
+	})
+
+	// Остальные страницы и API - только после входа. Просмотр доступен
+	// всем ролям, остальные операции требуют права роли (entity.Permission).
+	edit := h.Auth.Require(entity.PermEdit)
+	calc := h.Auth.Require(entity.PermCalculate)
+	remove := h.Auth.Require(entity.PermDelete)
+	s.router.Group(func(app chi.Router) {
+		app.Use(h.Auth.RequireAuth)
+
+		app.Route("/wells", func(r chi.Router) {
+			r.Get("/", h.Well.ListWells)
+			r.With(calc).Get("/export.xlsx", h.Well.ExportWells)
+			r.With(edit).Get("/create", h.Well.CreateWellForm)
+			r.With(edit).Post("/", h.Well.CreateWell)
+			r.Get("/{id}", h.Well.GetWell)
+			r.With(edit).Get("/{id}/edit", h.Well.EditWellForm)
+			r.With(edit).Put("/{id}", h.Well.UpdateWell)
+			r.With(edit).Post("/{id}", h.Well.UpdateWell) // HTML-формы не поддерживают PUT
+			r.With(remove).Delete("/{id}", h.Well.DeleteWell)
+			r.With(calc).Get("/{id}/ipr", h.Well.InflowPerformance)
+			r.With(calc).Get("/{id}/properties", h.Well.GasProperties)
+			r.With(calc).Get("/{id}/nodal", h.Well.NodalAnalysis)
+			r.With(calc).Get("/{id}/choke", h.Well.ChokeAnalysis)
+			r.With(calc).Get("/{id}/tubing", h.Well.TubingSensitivity)
+			r.With(calc).Get("/{id}/tubing.xlsx", h.Well.ExportTubingSensitivity)
+			r.With(calc).Get("/{id}/sweep", h.Well.Sweep)
+			r.With(calc).Get("/{id}/sweep.csv", h.Well.ExportSweepCSV)
+			r.With(calc).Get("/{id}/sweep.xlsx", h.Well.ExportSweepExcel)
+
+			r.Route("/{id}/tests", func(r chi.Router) {
+				r.Get("/", h.WellTest.ListTests)
+				r.With(edit).Post("/", h.WellTest.CreateTest)
+				r.Get("/{testID}", h.WellTest.GetTest)
+				r.With(edit).Delete("/{testID}", h.WellTest.DeleteTest)
+				r.With(edit).Post("/{testID}/apply", h.WellTest.ApplyFit)
+			})
+
+			r.Route("/{id}/measurements", func(r chi.Router) {
+				r.Get("/", h.Measurement.ListMeasurements)
+				r.With(edit).Post("/", h.Measurement.AddMeasurements)
+				r.Get("/latest", h.Measurement.CurrentMeasurement)
+				r.With(edit).Delete("/{measurementID}", h.Measurement.DeleteMeasurement)
+			})
+
+			r.Route("/{id}/production", func(r chi.Router) {
+				r.Get("/", h.Production.ListRecords)
+				r.With(edit).Post("/", h.Production.AddRecords)
+				r.With(edit).Delete("/{recordID}", h.Production.DeleteRecord)
+			})
+			r.With(calc).Get("/{id}/decline", h.Production.DeclineAnalysis)
+
+			r.Route("/{id}/surveys", func(r chi.Router) {
+				r.Get("/", h.MatBal.ListSurveys)
+				r.With(edit).Post("/", h.MatBal.AddSurvey)
+				r.With(edit).Delete("/{surveyID}", h.MatBal.DeleteSurvey)
+			})
+			r.With(calc).Get("/{id}/material-balance", h.MatBal.MaterialBalance)
+			r.Get("/{id}/history", h.Audit.WellHistory)
+			r.Get("/{id}/lifecycle", h.Well.Lifecycle)
+			r.With(edit).Post("/{id}/status", h.Well.ChangeStatus)
+			r.Get("/{id}/as-of", h.Well.WellAsOf)
+			r.Get("/{id}/revisions", h.Well.Revisions)
+			r.Get("/{id}/revisions/diff", h.Well.RevisionDiff)
+			r.Get("/{id}/revisions/{revision}", h.Well.GetRevision)
+			r.With(edit).Post("/{id}/revisions/{revision}/restore", h.Well.RestoreRevision)
+		})
+
+		app.Route("/fields", func(r chi.Router) {
+			r.Get("/", h.Field.ListFields)
+			r.With(edit).Post("/", h.Field.CreateField)
+			r.Get("/{id}", h.Field.GetField)
+			r.With(remove).Delete("/{id}", h.Field.DeleteField)
+			r.With(edit).Post("/{id}/pads", h.Field.CreatePad)
+		})
+		app.Get("/pads/{id}", h.Field.GetPad)
+		app.With(remove).Delete("/pads/{id}", h.Field.DeletePad)
+		app.Route("/pads/{id}/network", func(r chi.Router) {
+			r.Get("/", h.Network.GetNetwork)
+			r.With(calc).Get("/solve", h.Network.SolveNetwork)
+			r.With(edit).Post("/nodes", h.Network.AddNode)
+			r.With(edit).Delete("/nodes/{nodeID}", h.Network.DeleteNode)
+			r.With(edit).Post("/segments", h.Network.AddSegment)
+			r.With(edit).Delete("/segments/{segmentID}", h.Network.DeleteSegment)
+		})
+
+		// JSON API: только application/json, HTML-страницы не затрагиваются
+		app.Route("/api/v1", func(r chi.Router) {
+			r.Use(handler.JSONOnly, h.Auth.RequireByMethod)
+			h.Well.RegisterAPIRoutes(r)
+		})
+
+		app.Get("/audit", h.Audit.ListAudit)
+
+		app.Route("/users", func(r chi.Router) {
+			r.Use(h.Auth.Require(entity.PermManageUsers))
+			r.Get("/", h.User.ListUsers)
+			r.Post("/", h.User.CreateUser)
+			r.Post("/{id}/role", h.User.SetRole)
+		})
 	})
 
+	s.router.Get("/api/openapi.json", h.Docs.OpenAPI)
+	s.router.Get("/api/docs", h.Docs.Docs)
+
 	s.router.NotFound(func(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "404 Page Not Found", http.StatusNotFound)
 	})
@@ -0,0 +1,197 @@
+// internal/server/server_test.go
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"gas_wells/internal/config"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/handler"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/server"
+	"gas_wells/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenAPICoversRoutes проверяет, что каждый маршрут из SetupRoutes
+// описан в документе OpenAPI и что в документе нет лишних маршрутов
+func TestOpenAPICoversRoutes(t *testing.T) {
+	srv := server.New(logger.New("test"))
+	srv.SetupRoutes(server.Handlers{Docs: handler.NewDocsHandler(nil, logger.New("test"))})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+		Schemas struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					Description string `json:"description"`
+					Unit        string `json:"x-unit"`
+				} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&spec))
+	assert.True(t, strings.HasPrefix(spec.OpenAPI, "3."))
+
+	routes, ok := srv.Handler().(chi.Routes)
+	require.True(t, ok)
+	registered := make(map[string]bool)
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if route == "/static/*" {
+			return nil
+		}
+		if len(route) > 1 {
+			route = strings.TrimSuffix(route, "/")
+		}
+		key := strings.ToLower(method) + " " + route
+		registered[key] = true
+		_, ok := spec.Paths[route][strings.ToLower(method)]
+		assert.True(t, ok, "route %s is missing from the OpenAPI document", key)
+		return nil
+	})
+	require.NoError(t, err)
+
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			assert.True(t, registered[method+" "+path], "documented route %s %s is not registered", method, path)
+		}
+	}
+
+	// У числовых полей скважины есть описание, у размерных - единица измерения
+	well := spec.Schemas.Schemas["entity.Well"]
+	require.NotEmpty(t, well.Properties)
+	for name, prop := range well.Properties {
+		assert.NotEmpty(t, prop.Description, "well field %s has no description", name)
+	}
+	assert.Equal(t, "Па", well.Properties["pbuf"].Unit)
+	assert.Equal(t, "К", well.Properties["temp"].Unit)
+	assert.Equal(t, "м³/сут", well.Properties["q"].Unit)
+	assert.Contains(t, spec.Schemas.Schemas, "Error")
+}
+
+// userStore - пользователи в памяти
+type userStore struct{ users []*entity.User }
+
+func (s *userStore) Create(_ context.Context, user *entity.User) error {
+	user.ID = len(s.users) + 1
+	s.users = append(s.users, user)
+	return nil
+}
+
+func (s *userStore) GetByEmail(_ context.Context, email string) (*entity.User, error) {
+	for _, u := range s.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *userStore) GetByID(_ context.Context, id int) (*entity.User, error) {
+	if id < 1 || id > len(s.users) {
+		return nil, nil
+	}
+	return s.users[id-1], nil
+}
+
+func (s *userStore) List(context.Context) ([]*entity.User, error) { return s.users, nil }
+
+func (s *userStore) UpdateRole(_ context.Context, id int, role string) error {
+	s.users[id-1].Role = role
+	return nil
+}
+
+// tokenStore - refresh-токены не хранятся: тест использует только access-токены
+type tokenStore struct{}
+
+func (tokenStore) Create(context.Context, *entity.RefreshToken) error { return nil }
+func (tokenStore) Get(context.Context, string) (*entity.RefreshToken, error) {
+	return nil, nil
+}
+func (tokenStore) Revoke(context.Context, string) (bool, error) { return false, nil }
+func (tokenStore) Rotate(context.Context, string) (bool, error) { return false, nil }
+func (tokenStore) RevokeAll(context.Context, int) error         { return nil }
+
+// TestRoutePermissions сверяет права из документа OpenAPI (x-permission)
+// с проверками, подключенными в SetupRoutes: каждая операция без входа
+// отклоняется, а роль без нужного права получает 403
+func TestRoutePermissions(t *testing.T) {
+	ctx := context.Background()
+	log := logger.New("test")
+	authService := service.NewAuthService(&userStore{}, tokenStore{},
+		config.JWTConfig{Secret: "test-secret", AccessTTL: time.Hour, RefreshTTL: time.Hour}, log)
+
+	tokens := make(map[*entity.User]string)
+	for _, role := range entity.Roles {
+		user, err := authService.CreateUser(ctx, &entity.User{Email: role + "@example.com", Username: role, Role: role}, "Secret#123")
+		require.NoError(t, err)
+		session, err := authService.Login(ctx, user.Email, "Secret#123")
+		require.NoError(t, err)
+		tokens[user] = session.Tokens.AccessToken
+	}
+
+	srv := server.New(log)
+	srv.SetupRoutes(server.Handlers{
+		Docs: handler.NewDocsHandler(nil, log),
+		Auth: handler.NewAuthHandler(authService, nil, false, log),
+	})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var spec struct {
+		Paths map[string]map[string]struct {
+			Security   *[]json.RawMessage `json:"security"`
+			Permission entity.Permission  `json:"x-permission"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&spec))
+
+	// Обработчики, кроме входа и документации, не заданы: разрешенный запрос
+	// завершается паникой, которую перехватывает middleware.Recoverer (500)
+	serve := func(method, path, token string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Accept", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+	param := regexp.MustCompile(`\{\w+\}`)
+	for route, ops := range spec.Paths {
+		path := param.ReplaceAllString(route, "1")
+		for method, op := range ops {
+			if op.Security != nil && len(*op.Security) == 0 {
+				continue
+			}
+			method = strings.ToUpper(method)
+			assert.Equal(t, http.StatusUnauthorized, serve(method, path, ""), "%s %s without sign-in", method, route)
+
+			permission := op.Permission
+			if permission == "" {
+				permission = entity.PermRead
+			}
+			for user, token := range tokens {
+				denied := serve(method, path, token) == http.StatusForbidden
+				assert.Equal(t, !user.Can(permission), denied, "%s %s for role %s (%s)", method, route, user.Role, permission)
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// internal/service/audit.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository"
+	"reflect"
+	"strings"
+)
+
+// ErrInvalidAuditFilter - фильтр журнала аудита задан неверно
+var ErrInvalidAuditFilter = errors.New("invalid audit filter")
+
+// Размер страницы журнала аудита
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// wellAuditSkip - поля скважины, не попадающие в журнал: ключ, служебные
+// отметки времени и расчетные признаки, которые не хранятся в БД
+var wellAuditSkip = map[string]bool{
+	"id": true, "created": true, "updated": true, "status_since": true, "warnings": true, "hydrate_risk": true,
+}
+
+// AuditService - журнал изменений объектов: кто, когда и какие поля изменил
+type AuditService struct {
+	repo   repository.AuditRepository
+	logger logger.Logger
+}
+
+func NewAuditService(repo repository.AuditRepository, log logger.Logger) *AuditService {
+	return &AuditService{
+		repo:   repo,
+		logger: log.With("layer", "service"),
+	}
+}
+
+// List возвращает записи журнала по фильтру, новые первыми
+func (s *AuditService) List(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	if filter.Limit <= 0 || filter.Limit > maxAuditLimit {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	if filter.Action != "" && filter.Action != entity.AuditCreate &&
+		filter.Action != entity.AuditUpdate && filter.Action != entity.AuditDelete {
+		return nil, fmt.Errorf("%w: unknown audit action %q", ErrInvalidAuditFilter, filter.Action)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidAuditFilter)
+	}
+
+	entries, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return entries, nil
+}
+
+// WellHistory - изменения скважины, новые первыми
+func (s *AuditService) WellHistory(ctx context.Context, wellID int) ([]*entity.AuditEntry, error) {
+	return s.List(ctx, entity.AuditFilter{EntityType: entity.AuditWell, EntityID: wellID, Limit: maxAuditLimit})
+}
+
+// diffFields сравнивает экспортируемые поля двух значений одного типа
+// (указателей на структуру) по JSON-именам. nil в before означает создание
+// объекта, nil в after - удаление: в изменения попадают все поля.
+func diffFields(before, after interface{}, skip map[string]bool) []entity.FieldChange {
+	bv, av := reflect.ValueOf(before), reflect.ValueOf(after)
+	created, deleted := !bv.IsValid() || bv.IsNil(), !av.IsValid() || av.IsNil()
+	var t reflect.Type
+	switch {
+	case !created:
+		t = bv.Type().Elem()
+	case !deleted:
+		t = av.Type().Elem()
+	default:
+		return nil
+	}
+
+	changes := []entity.FieldChange{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" || name == "" || skip[name] {
+			continue
+		}
+		change := entity.FieldChange{Field: name}
+		if !created {
+			change.Old = plainValue(bv.Elem().Field(i))
+		}
+		if !deleted {
+			change.New = plainValue(av.Elem().Field(i))
+		}
+		if created || deleted || !reflect.DeepEqual(change.Old, change.New) {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// plainValue - значение поля без указателя; nil для пустого указателя
+func plainValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
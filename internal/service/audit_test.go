@@ -0,0 +1,109 @@
+// internal/service/audit_test.go
+package service_test
+
+import (
+	"context"
+	"errors"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/auth"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository/repotest"
+	"gas_wells/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWellAudit(t *testing.T) {
+	log := logger.New("test")
+	auditRepo := &repotest.AuditRepo{Now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}
+	audit := service.NewAuditService(auditRepo, log)
+	wells := service.NewWellService(&repotest.WellRepo{Wells: map[int]*entity.Well{}, Audit: auditRepo}, audit, log)
+
+	ctx := auth.WithUser(context.Background(), &entity.User{ID: 7, Email: "eng@example.com", Role: entity.RoleEngineer})
+	well, err := wells.CreateWell(ctx, &entity.Well{
+		Name: "101", GammaG: 0.6, Temp: 360, TempUst: 290, Depth: 3000,
+		Pbuf: 10e6, Ppl: 20e6, Q: 150e3, Roughness: 2e-5, Diameter: 0.062,
+	})
+	require.NoError(t, err)
+
+	update := *well
+	update.Depth = 3100
+	update.Location = "Куст 5"
+	_, err = wells.UpdateWell(ctx, &update)
+	require.NoError(t, err)
+
+	// Сохранение без изменений не попадает в журнал
+	same := update
+	_, err = wells.UpdateWell(ctx, &same)
+	require.NoError(t, err)
+
+	// Служебное удаление без пользователя
+	require.NoError(t, wells.DeleteWell(context.Background(), well.ID))
+
+	history, err := audit.WellHistory(context.Background(), well.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	deleted, updated, created := history[0], history[1], history[2]
+
+	assert.Equal(t, entity.AuditCreate, created.Action)
+	assert.Equal(t, "eng@example.com", created.Actor)
+	require.NotNil(t, created.ActorID)
+	assert.Equal(t, 7, *created.ActorID)
+	assert.Equal(t, "101", created.EntityName)
+	for _, c := range created.Changes {
+		assert.Nil(t, c.Old, c.Field)
+		assert.NotContains(t, []string{"id", "created", "updated"}, c.Field)
+	}
+
+	assert.Equal(t, entity.AuditUpdate, updated.Action)
+	changed := map[string]entity.FieldChange{}
+	for _, c := range updated.Changes {
+		changed[c.Field] = c
+	}
+	assert.Equal(t, entity.FieldChange{Field: "depth", Old: 3000.0, New: 3100.0}, changed["depth"])
+	assert.Equal(t, "Куст 5", changed["location"].New)
+	assert.NotContains(t, changed, "name")
+
+	assert.Equal(t, entity.AuditDelete, deleted.Action)
+	assert.Empty(t, deleted.Actor)
+	assert.Nil(t, deleted.ActorID)
+	for _, c := range deleted.Changes {
+		assert.Nil(t, c.New, c.Field)
+	}
+
+	t.Run("filters", func(t *testing.T) {
+		entries, err := audit.List(context.Background(), entity.AuditFilter{Action: entity.AuditUpdate})
+		require.NoError(t, err)
+		assert.Equal(t, []*entity.AuditEntry{updated}, entries)
+
+		entries, err = audit.List(context.Background(), entity.AuditFilter{Actor: "eng@"})
+		require.NoError(t, err)
+		assert.Equal(t, []*entity.AuditEntry{updated, created}, entries)
+
+		entries, err = audit.List(context.Background(), entity.AuditFilter{From: created.Created.Add(time.Second), Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []*entity.AuditEntry{deleted}, entries)
+
+		_, err = audit.List(context.Background(), entity.AuditFilter{Action: "rename"})
+		assert.ErrorIs(t, err, service.ErrInvalidAuditFilter)
+		_, err = audit.List(context.Background(), entity.AuditFilter{From: deleted.Created, To: created.Created})
+		assert.ErrorIs(t, err, service.ErrInvalidAuditFilter)
+	})
+}
+
+func TestWellAuditFailure(t *testing.T) {
+	log := logger.New("test")
+	auditRepo := &repotest.AuditRepo{Err: errors.New("audit_log is unavailable")}
+	wells := service.NewWellService(&repotest.WellRepo{Wells: map[int]*entity.Well{}, Audit: auditRepo},
+		service.NewAuditService(auditRepo, log), log)
+
+	// Изменение без записи в журнале не выполняется
+	_, err := wells.CreateWell(context.Background(), &entity.Well{
+		Name: "101", GammaG: 0.6, Temp: 360, TempUst: 290, Depth: 3000,
+		Pbuf: 10e6, Ppl: 20e6, Q: 150e3, Roughness: 2e-5, Diameter: 0.062,
+	})
+	assert.ErrorContains(t, err, "audit_log is unavailable")
+}
@@ -1 +1,296 @@
+// internal/service/auth.go
 package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/config"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/auth"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/pkg/validation"
+	"gas_wells/internal/repository"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrInvalidCredentials - неверный email или пароль
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrInvalidToken - токен не прошел проверку, истек или отозван
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrEmailTaken - пользователь с таким email уже есть
+	ErrEmailTaken = errors.New("email is already registered")
+	// ErrUserNotFound - пользователь не найден
+	ErrUserNotFound = errors.New("user not found")
+	// ErrLastAdmin - нельзя лишить прав последнего администратора
+	ErrLastAdmin = errors.New("at least one admin is required")
+)
+
+// refreshReuseGrace - время после обмена refresh-токена, в течение которого
+// он еще принимается: страницы, открытые одновременно, обновляют сессию
+// параллельно одним и тем же токеном из cookie
+const refreshReuseGrace = 10 * time.Second
+
+// dummyHash сравнивается с паролем при неизвестном email, чтобы время
+// ответа не выдавало, зарегистрирован ли адрес
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+type AuthService struct {
+	users  repository.UserRepository
+	tokens repository.RefreshTokenRepository
+	cfg    config.JWTConfig
+	logger logger.Logger
+	now    func() time.Time
+}
+
+func NewAuthService(users repository.UserRepository, tokens repository.RefreshTokenRepository, cfg config.JWTConfig, log logger.Logger) *AuthService {
+	return &AuthService{
+		users:  users,
+		tokens: tokens,
+		cfg:    cfg,
+		logger: log.With("layer", "service"),
+		now:    time.Now,
+	}
+}
+
+// TokenPair - access- и refresh-токены с их сроками действия
+type TokenPair struct {
+	AccessToken    string    `json:"access_token"`
+	RefreshToken   string    `json:"refresh_token"`
+	TokenType      string    `json:"token_type"` // Bearer
+	ExpiresIn      int       `json:"expires_in"` // Срок действия access-токена, с
+	AccessExpires  time.Time `json:"access_expires"`
+	RefreshExpires time.Time `json:"refresh_expires"`
+}
+
+// Session - пользователь и выданные ему токены
+type Session struct {
+	User   *entity.User `json:"user"`
+	Tokens TokenPair    `json:"tokens"`
+}
+
+// CreateUser добавляет пользователя с паролем password
+func (s *AuthService) CreateUser(ctx context.Context, user *entity.User, password string) (*entity.User, error) {
+	user.Email = strings.ToLower(strings.TrimSpace(user.Email))
+	user.Username = strings.TrimSpace(user.Username)
+	if user.Role == "" {
+		user.Role = entity.RoleViewer
+	}
+
+	switch {
+	case !validation.IsEmail(user.Email):
+		return nil, errors.New("invalid email address")
+	case !validation.NotBlank(user.Username):
+		return nil, errors.New("username is required")
+	case !entity.ValidRole(user.Role):
+		return nil, fmt.Errorf("unknown role %q", user.Role)
+	case !validation.IsPassword(password):
+		return nil, errors.New("password must be at least 8 characters with upper and lower case letters, a digit and a symbol")
+	}
+
+	existing, err := s.users.GetByEmail(ctx, user.Email)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrEmailTaken
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.PasswordHash = string(hash)
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return user, nil
+}
+
+// EnsureAdmin создает администратора, если пользователя с таким email нет.
+// Используется для первого входа в систему.
+func (s *AuthService) EnsureAdmin(ctx context.Context, email, password string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	existing, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	if existing != nil {
+		return nil
+	}
+	name, _, _ := strings.Cut(email, "@")
+	_, err = s.CreateUser(ctx, &entity.User{Email: email, Username: name, Role: entity.RoleAdmin}, password)
+	if err == nil {
+		s.logger.Info("admin user created", "email", email)
+	}
+	return err
+}
+
+// ListUsers - все пользователи
+func (s *AuthService) ListUsers(ctx context.Context) ([]*entity.User, error) {
+	users, err := s.users.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return users, nil
+}
+
+// SetRole меняет роль пользователя. Роль проверяется при каждом запросе,
+// поэтому новые права действуют сразу, без повторного входа.
+func (s *AuthService) SetRole(ctx context.Context, id int, role string) (*entity.User, error) {
+	if !entity.ValidRole(role) {
+		return nil, fmt.Errorf("unknown role %q", role)
+	}
+	users, err := s.users.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+
+	var user *entity.User
+	admins := 0
+	for _, u := range users {
+		if u.ID == id {
+			user = u
+		}
+		if u.Role == entity.RoleAdmin {
+			admins++
+		}
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Role == entity.RoleAdmin && role != entity.RoleAdmin && admins == 1 {
+		return nil, ErrLastAdmin
+	}
+
+	if err := s.users.UpdateRole(ctx, id, role); err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	s.logger.Info("user role changed", "user_id", id, "from", user.Role, "to", role)
+	user.Role = role
+	return user, nil
+}
+
+// Login проверяет email и пароль и выдает пару токенов
+func (s *AuthService) Login(ctx context.Context, email, password string) (*Session, error) {
+	user, err := s.users.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if user == nil {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return s.issue(ctx, user)
+}
+
+// Refresh обменивает refresh-токен на новую пару; старый токен отзывается.
+// Токен, обмененный не раньше чем refreshReuseGrace назад, обменивается
+// еще раз - это параллельное обновление сессии. Иное повторное предъявление
+// отозванного токена означает его утечку - в этом случае отзываются
+// все токены пользователя.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*Session, error) {
+	claims, err := s.parse(refreshToken, auth.TokenRefresh)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.tokens.Rotate(ctx, claims.ID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if !rotated {
+		token, err := s.tokens.Get(ctx, claims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("repository error: %w", err)
+		}
+		if token == nil || token.RotatedAt == nil || s.now().Sub(*token.RotatedAt) > refreshReuseGrace {
+			s.logger.Warn("refresh token reuse detected", "user_id", claims.Subject)
+			if err := s.tokens.RevokeAll(ctx, claims.Subject); err != nil {
+				return nil, fmt.Errorf("repository error: %w", err)
+			}
+			return nil, ErrInvalidToken
+		}
+	}
+
+	user, err := s.users.GetByID(ctx, claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if user == nil {
+		return nil, ErrInvalidToken
+	}
+	return s.issue(ctx, user)
+}
+
+// Logout отзывает refresh-токен. Недействительный токен не считается ошибкой.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	claims, err := s.parse(refreshToken, auth.TokenRefresh)
+	if err != nil {
+		return nil
+	}
+	if _, err := s.tokens.Revoke(ctx, claims.ID); err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	return nil
+}
+
+// Authenticate возвращает пользователя по access-токену
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (*entity.User, error) {
+	claims, err := s.parse(accessToken, auth.TokenAccess)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.users.GetByID(ctx, claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if user == nil {
+		return nil, ErrInvalidToken
+	}
+	return user, nil
+}
+
+func (s *AuthService) parse(token, typ string) (*auth.Claims, error) {
+	claims, err := auth.Parse(token, []byte(s.cfg.Secret), s.now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Type != typ {
+		return nil, fmt.Errorf("%w: %s token expected", ErrInvalidToken, typ)
+	}
+	return claims, nil
+}
+
+// issue выдает пару токенов и сохраняет refresh-токен
+func (s *AuthService) issue(ctx context.Context, user *entity.User) (*Session, error) {
+	now := s.now()
+	access := auth.NewClaims(user.ID, user.Role, auth.TokenAccess, now, s.cfg.AccessTTL)
+	refresh := auth.NewClaims(user.ID, user.Role, auth.TokenRefresh, now, s.cfg.RefreshTTL)
+
+	pair := TokenPair{
+		TokenType:      "Bearer",
+		ExpiresIn:      int(s.cfg.AccessTTL.Seconds()),
+		AccessExpires:  access.Expires(),
+		RefreshExpires: refresh.Expires(),
+	}
+	var err error
+	if pair.AccessToken, err = auth.Sign(access, []byte(s.cfg.Secret)); err != nil {
+		return nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+	if pair.RefreshToken, err = auth.Sign(refresh, []byte(s.cfg.Secret)); err != nil {
+		return nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	err = s.tokens.Create(ctx, &entity.RefreshToken{ID: refresh.ID, UserID: user.ID, Expires: refresh.Expires()})
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return &Session{User: user, Tokens: pair}, nil
+}
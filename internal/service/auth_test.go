@@ -0,0 +1,206 @@
+// internal/service/auth_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/config"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUserRepo - пользователи в памяти
+type memoryUserRepo struct {
+	users []*entity.User
+}
+
+func (r *memoryUserRepo) Create(_ context.Context, user *entity.User) error {
+	user.ID = len(r.users) + 1
+	clone := *user
+	r.users = append(r.users, &clone)
+	return nil
+}
+
+func (r *memoryUserRepo) GetByEmail(_ context.Context, email string) (*entity.User, error) {
+	for _, u := range r.users {
+		if strings.EqualFold(u.Email, email) {
+			clone := *u
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepo) GetByID(_ context.Context, id int) (*entity.User, error) {
+	if id < 1 || id > len(r.users) {
+		return nil, nil
+	}
+	clone := *r.users[id-1]
+	return &clone, nil
+}
+
+func (r *memoryUserRepo) List(_ context.Context) ([]*entity.User, error) {
+	users := make([]*entity.User, 0, len(r.users))
+	for _, u := range r.users {
+		clone := *u
+		users = append(users, &clone)
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepo) UpdateRole(_ context.Context, id int, role string) error {
+	r.users[id-1].Role = role
+	return nil
+}
+
+// memoryTokenRepo - refresh-токены в памяти
+type memoryTokenRepo struct {
+	tokens map[string]*entity.RefreshToken
+}
+
+func (r *memoryTokenRepo) Create(_ context.Context, token *entity.RefreshToken) error {
+	clone := *token
+	r.tokens[token.ID] = &clone
+	return nil
+}
+
+func (r *memoryTokenRepo) Get(_ context.Context, id string) (*entity.RefreshToken, error) {
+	return r.tokens[id], nil
+}
+
+func (r *memoryTokenRepo) Revoke(_ context.Context, id string) (bool, error) {
+	token, ok := r.tokens[id]
+	if !ok || token.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.RevokedAt = &now
+	return true, nil
+}
+
+func (r *memoryTokenRepo) Rotate(ctx context.Context, id string) (bool, error) {
+	revoked, err := r.Revoke(ctx, id)
+	if revoked {
+		r.tokens[id].RotatedAt = r.tokens[id].RevokedAt
+	}
+	return revoked, err
+}
+
+func (r *memoryTokenRepo) RevokeAll(ctx context.Context, userID int) error {
+	for id, token := range r.tokens {
+		if token.UserID == userID {
+			_, _ = r.Revoke(ctx, id)
+			token.RotatedAt = nil
+		}
+	}
+	return nil
+}
+
+// age сдвигает отметки обмена токенов на d назад
+func (r *memoryTokenRepo) age(d time.Duration) {
+	for _, token := range r.tokens {
+		if token.RotatedAt != nil {
+			at := token.RotatedAt.Add(-d)
+			token.RotatedAt = &at
+		}
+	}
+}
+
+func TestAuthService(t *testing.T) {
+	ctx := context.Background()
+	tokens := &memoryTokenRepo{tokens: map[string]*entity.RefreshToken{}}
+	svc := service.NewAuthService(&memoryUserRepo{}, tokens, config.JWTConfig{
+		Secret: "test-secret", AccessTTL: 15 * time.Minute, RefreshTTL: time.Hour,
+	}, logger.New("test"))
+
+	require.NoError(t, svc.EnsureAdmin(ctx, "Admin@Example.com", "Secret#123"))
+	require.NoError(t, svc.EnsureAdmin(ctx, "admin@example.com", "Other#1234"), "existing admin is kept")
+	_, err := svc.CreateUser(ctx, &entity.User{Email: "ADMIN@example.com", Username: "dup"}, "Secret#123")
+	assert.ErrorIs(t, err, service.ErrEmailTaken)
+	_, err = svc.CreateUser(ctx, &entity.User{Email: "user@example.com", Username: "user"}, "weak")
+	assert.Error(t, err)
+
+	_, err = svc.Login(ctx, "admin@example.com", "Other#1234")
+	assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+	_, err = svc.Login(ctx, "nobody@example.com", "Secret#123")
+	assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+
+	session, err := svc.Login(ctx, "admin@example.com", "Secret#123")
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoleAdmin, session.User.Role)
+	assert.Equal(t, "Bearer", session.Tokens.TokenType)
+	assert.Equal(t, 900, session.Tokens.ExpiresIn)
+
+	user, err := svc.Authenticate(ctx, session.Tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", user.Username)
+	_, err = svc.Authenticate(ctx, session.Tokens.RefreshToken)
+	assert.ErrorIs(t, err, service.ErrInvalidToken, "refresh token is not an access token")
+	_, err = svc.Refresh(ctx, session.Tokens.AccessToken)
+	assert.ErrorIs(t, err, service.ErrInvalidToken, "access token is not a refresh token")
+
+	// Обмен отзывает старый refresh-токен
+	rotated, err := svc.Refresh(ctx, session.Tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, session.Tokens.RefreshToken, rotated.Tokens.RefreshToken)
+
+	// Параллельное обновление сессии тем же токеном сразу после обмена
+	// получает свою пару и не завершает сессию
+	parallel, err := svc.Refresh(ctx, session.Tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, rotated.Tokens.RefreshToken, parallel.Tokens.RefreshToken)
+
+	// Повторное предъявление старого токена позже отзывает и новые
+	tokens.age(time.Minute)
+	_, err = svc.Refresh(ctx, session.Tokens.RefreshToken)
+	assert.ErrorIs(t, err, service.ErrInvalidToken)
+	_, err = svc.Refresh(ctx, rotated.Tokens.RefreshToken)
+	assert.ErrorIs(t, err, service.ErrInvalidToken)
+	_, err = svc.Refresh(ctx, parallel.Tokens.RefreshToken)
+	assert.ErrorIs(t, err, service.ErrInvalidToken)
+
+	// После выхода refresh-токен не принимается
+	session, err = svc.Login(ctx, "admin@example.com", "Secret#123")
+	require.NoError(t, err)
+	require.NoError(t, svc.Logout(ctx, session.Tokens.RefreshToken))
+	require.NoError(t, svc.Logout(ctx, "garbage"))
+	_, err = svc.Refresh(ctx, session.Tokens.RefreshToken)
+	assert.ErrorIs(t, err, service.ErrInvalidToken)
+}
+
+func TestAuthServiceRoles(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewAuthService(&memoryUserRepo{}, &memoryTokenRepo{tokens: map[string]*entity.RefreshToken{}},
+		config.JWTConfig{Secret: "test-secret", AccessTTL: time.Minute, RefreshTTL: time.Hour}, logger.New("test"))
+
+	require.NoError(t, svc.EnsureAdmin(ctx, "admin@example.com", "Secret#123"))
+	viewer, err := svc.CreateUser(ctx, &entity.User{Email: "viewer@example.com", Username: "viewer"}, "Secret#123")
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoleViewer, viewer.Role, "new users are viewers by default")
+	_, err = svc.CreateUser(ctx, &entity.User{Email: "x@example.com", Username: "x", Role: "root"}, "Secret#123")
+	assert.Error(t, err)
+
+	engineer, err := svc.SetRole(ctx, viewer.ID, entity.RoleEngineer)
+	require.NoError(t, err)
+	assert.True(t, engineer.Can(entity.PermEdit))
+	assert.False(t, engineer.Can(entity.PermDelete))
+
+	_, err = svc.SetRole(ctx, 1, entity.RoleViewer)
+	assert.ErrorIs(t, err, service.ErrLastAdmin)
+	_, err = svc.SetRole(ctx, 99, entity.RoleViewer)
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+	_, err = svc.SetRole(ctx, viewer.ID, "root")
+	assert.Error(t, err)
+
+	// При втором администраторе первого можно понизить
+	_, err = svc.SetRole(ctx, viewer.ID, entity.RoleAdmin)
+	require.NoError(t, err)
+	_, err = svc.SetRole(ctx, 1, entity.RoleViewer)
+	assert.NoError(t, err)
+}
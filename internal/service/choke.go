@@ -0,0 +1,83 @@
+// internal/service/choke.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+)
+
+// ChokeQuery - параметры расчета штуцера; нулевые значения берутся из данных скважины
+type ChokeQuery struct {
+	Diameter float64 // Диаметр штуцера, м
+	Pline    float64 // Давление после штуцера, Па
+	Rate     float64 // Целевой дебит для подбора штуцера, м³/сут
+}
+
+// ChokeAnalysis - расход через установленный штуцер и штуцер,
+// необходимый для целевого дебита
+type ChokeAnalysis struct {
+	WellID int                       `json:"well_id"`
+	Pbuf   float64                   `json:"pbuf"`
+	Pline  float64                   `json:"pline"`
+	Flow   *calculations.ChokeResult `json:"flow,omitempty"` // Установленный штуцер
+	Size   *calculations.ChokeResult `json:"size,omitempty"` // Подобранный штуцер
+}
+
+// ChokeAnalysis рассчитывает течение газа через штуцер скважины
+func (s *WellService) ChokeAnalysis(ctx context.Context, id int, q ChokeQuery) (*ChokeAnalysis, error) {
+	well, err := s.GetWell(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.chokeAnalysis(well, q)
+}
+
+// WellChoke рассчитывает штуцер по данным скважины: расход через
+// установленный штуцер и штуцер для текущего дебита
+func (s *WellService) WellChoke(well *entity.Well) (*ChokeAnalysis, error) {
+	return s.chokeAnalysis(well, ChokeQuery{})
+}
+
+func (s *WellService) chokeAnalysis(well *entity.Well, q ChokeQuery) (*ChokeAnalysis, error) {
+	if q.Diameter <= 0 {
+		q.Diameter = well.Choke
+	}
+	if q.Pline <= 0 {
+		q.Pline = well.Pline
+	}
+	if q.Rate <= 0 {
+		q.Rate = well.Q
+	}
+	if q.Pline <= 0 {
+		return nil, fmt.Errorf("%w: line pressure is required", ErrInvalidWell)
+	}
+	if q.Diameter <= 0 && q.Rate <= 0 {
+		return nil, fmt.Errorf("%w: choke diameter or target rate is required", ErrInvalidWell)
+	}
+
+	in := calculations.ChokeInput{
+		Diameter: q.Diameter,
+		P1:       well.Pbuf,
+		P2:       q.Pline,
+		Temp:     well.TempUst,
+		GammaG:   well.GammaG,
+	}
+	res := &ChokeAnalysis{WellID: well.ID, Pbuf: well.Pbuf, Pline: q.Pline}
+	if q.Diameter > 0 {
+		flow, err := calculations.ChokeFlow(in)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+		}
+		res.Flow = &flow
+	}
+	if q.Rate > 0 {
+		size, err := calculations.ChokeSize(in, q.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+		}
+		res.Size = &size
+	}
+	return res, nil
+}
@@ -0,0 +1,30 @@
+// internal/service/choke_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChokeAnalysis(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	// У скважины не задано давление в шлейфе
+	_, err := s.ChokeAnalysis(ctx, 1, service.ChokeQuery{})
+	assert.Error(t, err)
+
+	res, err := s.ChokeAnalysis(ctx, 1, service.ChokeQuery{Pline: 7e6, Diameter: 0.01})
+	require.NoError(t, err)
+	require.NotNil(t, res.Flow)
+	require.NotNil(t, res.Size)
+	assert.Equal(t, 3e6, res.Flow.DeltaP)
+	assert.False(t, res.Flow.Critical)
+	// Размер подбирается под текущий дебит скважины
+	assert.Equal(t, 150e3, res.Size.Rate)
+	assert.Equal(t, res.Flow.Rate < 150e3, res.Size.Diameter > 0.01)
+}
@@ -0,0 +1,237 @@
+// internal/service/field_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository"
+	"strings"
+)
+
+var (
+	// ErrFieldNotFound возвращается, если месторождение не существует
+	ErrFieldNotFound = errors.New("field not found")
+	// ErrPadNotFound возвращается, если куст не существует
+	ErrPadNotFound = errors.New("pad not found")
+	// ErrInvalidField - данные месторождения или куста не прошли проверку
+	ErrInvalidField = errors.New("invalid field data")
+	// ErrFieldExists - месторождение с таким названием уже есть
+	ErrFieldExists = errors.New("field with this name already exists")
+	// ErrPadExists - куст с таким названием на месторождении уже есть
+	ErrPadExists = errors.New("pad with this name already exists")
+)
+
+type FieldService struct {
+	repo   repository.FieldRepository
+	wells  repository.WellRepository
+	logger logger.Logger
+}
+
+func NewFieldService(repo repository.FieldRepository, wells repository.WellRepository, log logger.Logger) *FieldService {
+	return &FieldService{
+		repo:   repo,
+		wells:  wells,
+		logger: log.With("layer", "service"),
+	}
+}
+
+// FieldOverview - месторождение со сводкой по кустам
+type FieldOverview struct {
+	Field   *entity.Field          `json:"field"`
+	Summary *entity.GroupSummary   `json:"summary"`
+	Pads    []*entity.GroupSummary `json:"pads"`
+}
+
+// PadOverview - куст со сводкой и списком скважин
+type PadOverview struct {
+	Pad     *entity.Pad          `json:"pad"`
+	Field   *entity.Field        `json:"field"`
+	Summary *entity.GroupSummary `json:"summary"`
+	Wells   []*entity.Well       `json:"wells"`
+}
+
+// PadChoice - куст с названием месторождения для выбора в формах
+type PadChoice struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Field string `json:"field"`
+}
+
+// CreateField создает месторождение
+func (s *FieldService) CreateField(ctx context.Context, field *entity.Field) (*entity.Field, error) {
+	field.Name = strings.TrimSpace(field.Name)
+	if field.Name == "" {
+		return nil, fmt.Errorf("%w: field name is required", ErrInvalidField)
+	}
+	err := s.repo.CreateField(ctx, field)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, ErrFieldExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return field, nil
+}
+
+// GetField возвращает месторождение по ID
+func (s *FieldService) GetField(ctx context.Context, id int) (*entity.Field, error) {
+	field, err := s.repo.GetField(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if field == nil {
+		return nil, ErrFieldNotFound
+	}
+	return field, nil
+}
+
+// DeleteField удаляет месторождение и его кусты
+func (s *FieldService) DeleteField(ctx context.Context, id int) error {
+	if _, err := s.GetField(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteField(ctx, id)
+}
+
+// CreatePad создает куст на месторождении
+func (s *FieldService) CreatePad(ctx context.Context, pad *entity.Pad) (*entity.Pad, error) {
+	if _, err := s.GetField(ctx, pad.FieldID); err != nil {
+		return nil, err
+	}
+	pad.Name = strings.TrimSpace(pad.Name)
+	if pad.Name == "" {
+		return nil, fmt.Errorf("%w: pad name is required", ErrInvalidField)
+	}
+	err := s.repo.CreatePad(ctx, pad)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, ErrPadExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return pad, nil
+}
+
+// GetPad возвращает куст по ID
+func (s *FieldService) GetPad(ctx context.Context, id int) (*entity.Pad, error) {
+	pad, err := s.repo.GetPad(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if pad == nil {
+		return nil, ErrPadNotFound
+	}
+	return pad, nil
+}
+
+// DeletePad удаляет куст; скважины куста остаются без привязки
+func (s *FieldService) DeletePad(ctx context.Context, id int) error {
+	if _, err := s.GetPad(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeletePad(ctx, id)
+}
+
+// Overview возвращает сводку по всем месторождениям
+func (s *FieldService) Overview(ctx context.Context) ([]*entity.GroupSummary, error) {
+	summaries, err := s.repo.FieldSummaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return summaries, nil
+}
+
+// FieldOverview возвращает месторождение со сводкой по кустам
+func (s *FieldService) FieldOverview(ctx context.Context, id int) (*FieldOverview, error) {
+	field, err := s.GetField(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pads, err := s.repo.PadSummaries(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return &FieldOverview{
+		Field:   field,
+		Summary: mergeSummaries(field.ID, field.Name, pads),
+		Pads:    pads,
+	}, nil
+}
+
+// PadOverview возвращает куст со сводкой и списком скважин
+func (s *FieldService) PadOverview(ctx context.Context, id int) (*PadOverview, error) {
+	pad, err := s.GetPad(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	field, err := s.GetField(ctx, pad.FieldID)
+	if err != nil {
+		return nil, err
+	}
+	wells, err := s.wells.ListByPad(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return &PadOverview{
+		Pad:     pad,
+		Field:   field,
+		Summary: summarizeWells(pad.ID, pad.Name, wells),
+		Wells:   wells,
+	}, nil
+}
+
+// PadChoices возвращает все кусты с названиями месторождений
+func (s *FieldService) PadChoices(ctx context.Context) ([]PadChoice, error) {
+	fields, err := s.repo.ListFields(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	names := make(map[int]string, len(fields))
+	for _, f := range fields {
+		names[f.ID] = f.Name
+	}
+
+	pads, err := s.repo.ListPads(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	choices := make([]PadChoice, 0, len(pads))
+	for _, p := range pads {
+		choices = append(choices, PadChoice{ID: p.ID, Name: p.Name, Field: names[p.FieldID]})
+	}
+	return choices, nil
+}
+
+// summarizeWells рассчитывает сводку по списку скважин
+func summarizeWells(id int, name string, wells []*entity.Well) *entity.GroupSummary {
+	g := &entity.GroupSummary{ID: id, Name: name, Wells: len(wells), ByStatus: make(map[string]int)}
+	for _, w := range wells {
+		g.TotalQ += w.Q
+		g.AvgPbuf += w.Pbuf
+		g.ByStatus[w.Status]++
+	}
+	if len(wells) > 0 {
+		g.AvgPbuf /= float64(len(wells))
+	}
+	return g
+}
+
+// mergeSummaries объединяет сводки групп; среднее Pбуф взвешивается по числу скважин
+func mergeSummaries(id int, name string, groups []*entity.GroupSummary) *entity.GroupSummary {
+	total := &entity.GroupSummary{ID: id, Name: name, ByStatus: make(map[string]int)}
+	for _, g := range groups {
+		total.Wells += g.Wells
+		total.TotalQ += g.TotalQ
+		total.AvgPbuf += g.AvgPbuf * float64(g.Wells)
+		for status, n := range g.ByStatus {
+			total.ByStatus[status] += n
+		}
+	}
+	if total.Wells > 0 {
+		total.AvgPbuf /= float64(total.Wells)
+	}
+	return total
+}
@@ -0,0 +1,141 @@
+// internal/service/field_service_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository"
+	"gas_wells/internal/repository/repotest"
+	"gas_wells/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryFieldRepo - месторождения и кусты в памяти; сводки задаются в тесте.
+// Названия месторождений и кустов месторождения уникальны, как в БД.
+type memoryFieldRepo struct {
+	fields    map[int]*entity.Field
+	pads      map[int]*entity.Pad
+	summaries []*entity.GroupSummary
+}
+
+func (r *memoryFieldRepo) CreateField(_ context.Context, f *entity.Field) error {
+	for _, other := range r.fields {
+		if other.Name == f.Name {
+			return repository.ErrDuplicate
+		}
+	}
+	f.ID = len(r.fields) + 1
+	r.fields[f.ID] = f
+	return nil
+}
+
+func (r *memoryFieldRepo) GetField(_ context.Context, id int) (*entity.Field, error) {
+	return r.fields[id], nil
+}
+
+func (r *memoryFieldRepo) ListFields(context.Context) ([]*entity.Field, error) {
+	var fields []*entity.Field
+	for _, f := range r.fields {
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func (r *memoryFieldRepo) DeleteField(_ context.Context, id int) error {
+	delete(r.fields, id)
+	return nil
+}
+
+func (r *memoryFieldRepo) CreatePad(_ context.Context, p *entity.Pad) error {
+	for _, other := range r.pads {
+		if other.FieldID == p.FieldID && other.Name == p.Name {
+			return repository.ErrDuplicate
+		}
+	}
+	p.ID = len(r.pads) + 1
+	r.pads[p.ID] = p
+	return nil
+}
+
+func (r *memoryFieldRepo) GetPad(_ context.Context, id int) (*entity.Pad, error) {
+	return r.pads[id], nil
+}
+
+func (r *memoryFieldRepo) ListPads(_ context.Context, fieldID int) ([]*entity.Pad, error) {
+	var pads []*entity.Pad
+	for _, p := range r.pads {
+		if fieldID == 0 || p.FieldID == fieldID {
+			pads = append(pads, p)
+		}
+	}
+	return pads, nil
+}
+
+func (r *memoryFieldRepo) DeletePad(_ context.Context, id int) error {
+	delete(r.pads, id)
+	return nil
+}
+
+func (r *memoryFieldRepo) FieldSummaries(context.Context) ([]*entity.GroupSummary, error) {
+	return r.summaries, nil
+}
+
+func (r *memoryFieldRepo) PadSummaries(context.Context, int) ([]*entity.GroupSummary, error) {
+	return r.summaries, nil
+}
+
+func TestFieldHierarchy(t *testing.T) {
+	ctx := context.Background()
+	padID := 1
+	wells := &repotest.WellRepo{Wells: map[int]*entity.Well{
+		1: {ID: 1, Name: "1", Pbuf: 8e6, Q: 100e3, Status: entity.StatusProducing, PadID: &padID},
+		2: {ID: 2, Name: "2", Pbuf: 6e6, Q: 50e3, Status: entity.StatusShutIn, PadID: &padID},
+		3: {ID: 3, Name: "3", Pbuf: 9e6, Q: 70e3, Status: entity.StatusProducing},
+	}}
+	fields := &memoryFieldRepo{fields: map[int]*entity.Field{}, pads: map[int]*entity.Pad{}}
+	s := service.NewFieldService(fields, wells, logger.New("test"))
+
+	_, err := s.CreatePad(ctx, &entity.Pad{FieldID: 1, Name: "K1"})
+	assert.ErrorIs(t, err, service.ErrFieldNotFound)
+	_, err = s.CreateField(ctx, &entity.Field{Name: "  "})
+	assert.ErrorIs(t, err, service.ErrInvalidField)
+
+	field, err := s.CreateField(ctx, &entity.Field{Name: "Северное"})
+	require.NoError(t, err)
+	pad, err := s.CreatePad(ctx, &entity.Pad{FieldID: field.ID, Name: "K1"})
+	require.NoError(t, err)
+	_, err = s.CreateField(ctx, &entity.Field{Name: " Северное "})
+	assert.ErrorIs(t, err, service.ErrFieldExists)
+	_, err = s.CreatePad(ctx, &entity.Pad{FieldID: field.ID, Name: "K1"})
+	assert.ErrorIs(t, err, service.ErrPadExists)
+	_, err = s.CreatePad(ctx, &entity.Pad{FieldID: field.ID})
+	assert.ErrorIs(t, err, service.ErrInvalidField)
+
+	overview, err := s.PadOverview(ctx, pad.ID)
+	require.NoError(t, err)
+	assert.Len(t, overview.Wells, 2)
+	assert.Equal(t, 2, overview.Summary.Wells)
+	assert.Equal(t, 150e3, overview.Summary.TotalQ)
+	assert.Equal(t, 7e6, overview.Summary.AvgPbuf)
+	assert.Equal(t, map[string]int{entity.StatusProducing: 1, entity.StatusShutIn: 1}, overview.Summary.ByStatus)
+
+	// Итог по месторождению - среднее Pбуф взвешено по числу скважин
+	fields.summaries = []*entity.GroupSummary{
+		{ID: 1, Wells: 2, TotalQ: 150e3, AvgPbuf: 7e6, ByStatus: map[string]int{entity.StatusProducing: 1, entity.StatusShutIn: 1}},
+		{ID: 2, Wells: 1, TotalQ: 70e3, AvgPbuf: 10e6, ByStatus: map[string]int{entity.StatusProducing: 1}},
+	}
+	fo, err := s.FieldOverview(ctx, field.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, fo.Summary.Wells)
+	assert.Equal(t, 220e3, fo.Summary.TotalQ)
+	assert.InDelta(t, 8e6, fo.Summary.AvgPbuf, 1)
+	assert.Equal(t, 2, fo.Summary.ByStatus[entity.StatusProducing])
+
+	choices, err := s.PadChoices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []service.PadChoice{{ID: pad.ID, Name: "K1", Field: "Северное"}}, choices)
+}
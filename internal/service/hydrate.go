@@ -0,0 +1,42 @@
+// internal/service/hydrate.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+)
+
+// AssessHydrates оценивает риск гидратообразования на устье скважины
+// и заполняет признак well.HydrateRisk
+func (s *WellService) AssessHydrates(well *entity.Well, method calculations.HydrateMethod) (*calculations.HydrateResult, error) {
+	if well.Pbuf <= 0 || well.TempUst <= 0 || well.GammaG <= 0 {
+		return nil, errors.New("wellhead pressure, temperature and gas gravity are required")
+	}
+
+	res, err := calculations.AssessHydrates(calculations.HydrateInput{
+		Pressure:     well.Pbuf,
+		Temp:         well.TempUst,
+		GammaG:       well.GammaG,
+		Q:            well.Q,
+		WGF:          well.WGF,
+		WaterDensity: well.Rog,
+	}, method)
+	if err != nil {
+		return nil, fmt.Errorf("calculation failed: %w", err)
+	}
+
+	well.HydrateRisk = string(res.Risk)
+	return &res, nil
+}
+
+// fillHydrateRisk заполняет признак риска для списка скважин;
+// при недостатке данных признак остается пустым
+func (s *WellService) fillHydrateRisk(wells []*entity.Well) {
+	for _, well := range wells {
+		if _, err := s.AssessHydrates(well, calculations.DefaultHydrateMethod); err != nil {
+			s.logger.Debug("hydrate risk not assessed", "well_id", well.ID, "error", err)
+		}
+	}
+}
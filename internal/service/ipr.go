@@ -0,0 +1,71 @@
+// internal/service/ipr.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+)
+
+// IPRQuery - параметры построения индикаторной кривой
+type IPRQuery struct {
+	PMin     float64 // Нижняя граница забойного давления, Па
+	PMax     float64 // Верхняя граница забойного давления, Па
+	Points   int     // Число точек кривой
+	Drawdown float64 // Депрессия для расчета дебита, Па (0 - не рассчитывать)
+}
+
+// IPRResult - индикаторная кривая скважины
+type IPRResult struct {
+	WellID       int                     `json:"well_id"`
+	A            float64                 `json:"a"`
+	B            float64                 `json:"b"`
+	Ppl          float64                 `json:"ppl"`
+	AOF          float64                 `json:"aof"` // Абсолютно свободный дебит, м³/сут
+	Curve        []calculations.IPRPoint `json:"curve"`
+	Drawdown     float64                 `json:"drawdown,omitempty"`
+	DrawdownRate float64                 `json:"drawdown_rate,omitempty"`
+}
+
+// InflowPerformance строит индикаторную кривую скважины по коэффициентам A и B
+func (s *WellService) InflowPerformance(ctx context.Context, id int, q IPRQuery) (*IPRResult, error) {
+	well, err := s.GetWell(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.inflowPerformance(well, q)
+}
+
+func (s *WellService) inflowPerformance(well *entity.Well, q IPRQuery) (*IPRResult, error) {
+	in := calculations.Inflow{A: well.A, B: well.B, Ppl: well.Ppl}
+
+	curve, err := in.Curve(q.PMin, q.PMax, q.Points)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+	}
+	aof, err := in.AbsoluteOpenFlow()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+	}
+
+	res := &IPRResult{
+		WellID: well.ID,
+		A:      well.A,
+		B:      well.B,
+		Ppl:    well.Ppl,
+		AOF:    aof,
+		Curve:  curve,
+	}
+
+	if q.Drawdown > 0 {
+		rate, err := in.RateAtDrawdown(q.Drawdown)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+		}
+		res.Drawdown = q.Drawdown
+		res.DrawdownRate = rate
+	}
+
+	return res, nil
+}
@@ -0,0 +1,105 @@
+// internal/service/matbal_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository"
+)
+
+// ErrInvalidSurvey - замер пластового давления не прошел проверку или замеров
+// недостаточно для расчета
+var ErrInvalidSurvey = errors.New("invalid pressure survey")
+
+// Давление забрасывания по умолчанию - доля от начального пластового давления
+const defaultAbandonmentRatio = 0.1
+
+type MaterialBalanceService struct {
+	repo   repository.PressureSurveyRepository
+	wells  *WellService
+	logger logger.Logger
+}
+
+func NewMaterialBalanceService(repo repository.PressureSurveyRepository, wells *WellService, log logger.Logger) *MaterialBalanceService {
+	return &MaterialBalanceService{
+		repo:   repo,
+		wells:  wells,
+		logger: log.With("layer", "service"),
+	}
+}
+
+// AddSurvey сохраняет замер пластового давления
+func (s *MaterialBalanceService) AddSurvey(ctx context.Context, survey *entity.PressureSurvey) (*entity.PressureSurvey, error) {
+	if _, err := s.wells.GetWell(ctx, survey.WellID); err != nil {
+		return nil, err
+	}
+	if survey.SurveyDate.IsZero() {
+		return nil, fmt.Errorf("%w: survey date is required", ErrInvalidSurvey)
+	}
+	if survey.Ppl <= 0 {
+		return nil, fmt.Errorf("%w: reservoir pressure must be positive", ErrInvalidSurvey)
+	}
+	if survey.Gp < 0 {
+		return nil, fmt.Errorf("%w: cumulative production must be non-negative", ErrInvalidSurvey)
+	}
+
+	if err := s.repo.Create(ctx, survey); err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return survey, nil
+}
+
+// ListSurveys возвращает замеры пластового давления скважины
+func (s *MaterialBalanceService) ListSurveys(ctx context.Context, wellID int) ([]*entity.PressureSurvey, error) {
+	surveys, err := s.repo.ListByWell(ctx, wellID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return surveys, nil
+}
+
+// DeleteSurvey удаляет замер пластового давления
+func (s *MaterialBalanceService) DeleteSurvey(ctx context.Context, wellID, id int) error {
+	if err := s.repo.Delete(ctx, wellID, id); err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	return nil
+}
+
+// MaterialBalance оценивает начальные запасы газа по графику P/Z от
+// накопленной добычи. Z рассчитывается при пластовой температуре скважины.
+// pAbandon - давление забрасывания, Па (0 - 10% от давления первого замера).
+func (s *MaterialBalanceService) MaterialBalance(ctx context.Context, wellID int, pAbandon float64) (*calculations.MaterialBalanceResult, error) {
+	well, err := s.wells.GetWell(ctx, wellID)
+	if err != nil {
+		return nil, err
+	}
+	if well.Temp <= 0 || well.GammaG <= 0 {
+		return nil, fmt.Errorf("%w: reservoir temperature and gas gravity are required", ErrInvalidWell)
+	}
+	surveys, err := s.ListSurveys(ctx, wellID)
+	if err != nil {
+		return nil, err
+	}
+	if len(surveys) == 0 {
+		return nil, fmt.Errorf("%w: no pressure surveys", ErrInvalidSurvey)
+	}
+
+	points := make([]calculations.SurveyPoint, len(surveys))
+	for i, sv := range surveys {
+		points[i] = calculations.SurveyPoint{P: sv.Ppl, Gp: sv.Gp}
+	}
+	if pAbandon <= 0 {
+		pAbandon = surveys[0].Ppl * defaultAbandonmentRatio
+	}
+
+	res, err := calculations.MaterialBalance(points, well.Temp, well.GammaG, pAbandon, calculations.ZOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+	}
+	return &res, nil
+}
@@ -0,0 +1,156 @@
+// internal/service/matbal_service_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository/repotest"
+	"gas_wells/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySurveyRepo - замеры пластового давления в памяти
+type memorySurveyRepo struct {
+	surveys []*entity.PressureSurvey
+}
+
+func (r *memorySurveyRepo) Create(_ context.Context, survey *entity.PressureSurvey) error {
+	survey.ID = len(r.surveys) + 1
+	r.surveys = append(r.surveys, survey)
+	return nil
+}
+
+func (r *memorySurveyRepo) ListByWell(_ context.Context, wellID int) ([]*entity.PressureSurvey, error) {
+	var res []*entity.PressureSurvey
+	for _, sv := range r.surveys {
+		if sv.WellID == wellID {
+			res = append(res, sv)
+		}
+	}
+	return res, nil
+}
+
+func (r *memorySurveyRepo) Delete(_ context.Context, wellID, id int) error {
+	for i, sv := range r.surveys {
+		if sv.WellID == wellID && sv.ID == id {
+			r.surveys = append(r.surveys[:i], r.surveys[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func newMatBalService(t *testing.T) (*service.MaterialBalanceService, *memorySurveyRepo) {
+	t.Helper()
+	wells := &repotest.WellRepo{Wells: map[int]*entity.Well{
+		1: {ID: 1, Name: "1", GammaG: 0.6, Temp: 360},
+		2: {ID: 2, Name: "2", GammaG: 0.6},
+	}}
+	surveys := &memorySurveyRepo{}
+	ws := service.NewWellService(wells, nil, logger.New("test"))
+	return service.NewMaterialBalanceService(surveys, ws, logger.New("test")), surveys
+}
+
+func TestAddSurveyValidation(t *testing.T) {
+	ctx := context.Background()
+	s, repo := newMatBalService(t)
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.AddSurvey(ctx, &entity.PressureSurvey{WellID: 3, SurveyDate: date, Ppl: 20e6})
+	assert.ErrorIs(t, err, service.ErrWellNotFound)
+
+	invalid := []*entity.PressureSurvey{
+		{WellID: 1, Ppl: 20e6},
+		{WellID: 1, SurveyDate: date},
+		{WellID: 1, SurveyDate: date, Ppl: -1},
+		{WellID: 1, SurveyDate: date, Ppl: 20e6, Gp: -1},
+	}
+	for _, sv := range invalid {
+		_, err := s.AddSurvey(ctx, sv)
+		assert.ErrorIs(t, err, service.ErrInvalidSurvey)
+	}
+	assert.Empty(t, repo.surveys)
+
+	saved, err := s.AddSurvey(ctx, &entity.PressureSurvey{WellID: 1, SurveyDate: date, Ppl: 20e6})
+	require.NoError(t, err)
+	assert.Equal(t, 1, saved.ID)
+	assert.Len(t, repo.surveys, 1)
+}
+
+func TestMaterialBalanceService(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newMatBalService(t)
+
+	_, err := s.MaterialBalance(ctx, 1, 0)
+	assert.ErrorIs(t, err, service.ErrInvalidSurvey, "no surveys")
+
+	// Замеры на прямой P/Z с запасами 5 млрд м³
+	const giip = 5e9
+	pzi := pzAt(t, 25e6, 360, 0.6)
+	date := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	gps := []float64{0, 0.5e9, 1e9, 1.5e9}
+	for i, gp := range gps {
+		p := pressureForPZ(t, pzi*(1-gp/giip), 360, 0.6)
+		_, err := s.AddSurvey(ctx, &entity.PressureSurvey{
+			WellID: 1, SurveyDate: date.AddDate(i, 0, 0), Ppl: p, Gp: gp,
+		})
+		require.NoError(t, err)
+	}
+
+	res, err := s.MaterialBalance(ctx, 1, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, giip, res.GIIP, giip*1e-3)
+	assert.InDelta(t, 1, res.R2, 1e-6)
+	require.Len(t, res.Points, len(gps))
+	for i, pt := range res.Points {
+		assert.Equal(t, gps[i], pt.Gp)
+		assert.InDelta(t, pt.P/pt.Z, pt.PZ, 1)
+	}
+	// Давление забрасывания по умолчанию - 10% от первого замера
+	assert.InDelta(t, res.Points[0].P*0.1, res.Abandonment, 1)
+
+	res, err = s.MaterialBalance(ctx, 1, 2e6)
+	require.NoError(t, err)
+	assert.Equal(t, 2e6, res.Abandonment)
+	points := make([]calculations.SurveyPoint, len(res.Points))
+	for i, pt := range res.Points {
+		points[i] = calculations.SurveyPoint{P: pt.P, Gp: pt.Gp}
+	}
+	want, err := calculations.MaterialBalance(points, 360, 0.6, 2e6, calculations.ZOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, want.RecoveryFactor, res.RecoveryFactor)
+
+	// Без пластовой температуры Z не рассчитать
+	_, err = s.MaterialBalance(ctx, 2, 0)
+	assert.ErrorIs(t, err, service.ErrInvalidWell)
+	_, err = s.MaterialBalance(ctx, 3, 0)
+	assert.ErrorIs(t, err, service.ErrWellNotFound)
+}
+
+func pzAt(t *testing.T, p, temp, gammaG float64) float64 {
+	t.Helper()
+	zr, err := calculations.ZFactor(p, temp, gammaG, calculations.ZOptions{})
+	require.NoError(t, err)
+	return p / zr.Z
+}
+
+// pressureForPZ - давление с заданным P/Z (бисекция)
+func pressureForPZ(t *testing.T, target, temp, gammaG float64) float64 {
+	t.Helper()
+	lo, hi := 1e5, 60e6
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if pzAt(t, mid, temp, gammaG) < target {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
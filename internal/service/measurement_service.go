@@ -0,0 +1,170 @@
+// internal/service/measurement_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository"
+	"time"
+)
+
+var (
+	// ErrInvalidMeasurement - замер не прошел проверку
+	ErrInvalidMeasurement = errors.New("invalid measurement")
+	// ErrInvalidMeasurementQuery - неверный интервал или шаг выборки замеров
+	ErrInvalidMeasurementQuery = errors.New("invalid measurement query")
+)
+
+// Интервал выборки по умолчанию, если границы не заданы
+const defaultMeasurementPeriod = 30 * 24 * time.Hour
+
+// MeasurementQuery - интервал [From, To) и шаг осреднения временного ряда
+type MeasurementQuery struct {
+	From       time.Time
+	To         time.Time
+	Resolution entity.MeasurementResolution
+}
+
+type MeasurementService struct {
+	repo   repository.MeasurementRepository
+	wells  *WellService
+	logger logger.Logger
+}
+
+func NewMeasurementService(repo repository.MeasurementRepository, wells *WellService, log logger.Logger) *MeasurementService {
+	return &MeasurementService{
+		repo:   repo,
+		wells:  wells,
+		logger: log.With("layer", "service"),
+	}
+}
+
+// AddMeasurements сохраняет замеры скважины и обновляет текущие значения
+// Pbuf, Ptb, Q и TempUst в карточке по последним замерам
+func (s *MeasurementService) AddMeasurements(ctx context.Context, wellID int, measurements []*entity.Measurement) (*entity.Well, error) {
+	well, err := s.wells.GetWell(ctx, wellID)
+	if err != nil {
+		return nil, err
+	}
+	if len(measurements) == 0 {
+		return nil, fmt.Errorf("%w: no measurements provided", ErrInvalidMeasurement)
+	}
+	for _, m := range measurements {
+		if err := validateMeasurement(m); err != nil {
+			return nil, fmt.Errorf("%w: measurement at %s: %w", ErrInvalidMeasurement, m.MeasuredAt.Format(time.RFC3339), err)
+		}
+		m.WellID = wellID
+	}
+
+	if err := s.repo.Save(ctx, measurements); err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return s.syncCurrent(ctx, well)
+}
+
+// Measurements возвращает временной ряд замеров: исходный или осредненный
+func (s *MeasurementService) Measurements(ctx context.Context, wellID int, q MeasurementQuery) ([]*entity.Measurement, error) {
+	if _, err := s.wells.GetWell(ctx, wellID); err != nil {
+		return nil, err
+	}
+	if q.To.IsZero() {
+		q.To = time.Now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-defaultMeasurementPeriod)
+	}
+	if !q.From.Before(q.To) {
+		return nil, fmt.Errorf("%w: range start must be before range end", ErrInvalidMeasurementQuery)
+	}
+
+	var (
+		measurements []*entity.Measurement
+		err          error
+	)
+	switch q.Resolution {
+	case entity.ResolutionRaw:
+		measurements, err = s.repo.List(ctx, wellID, q.From, q.To)
+	case entity.ResolutionHour, entity.ResolutionDay:
+		measurements, err = s.repo.Aggregate(ctx, wellID, q.From, q.To, q.Resolution)
+	default:
+		return nil, fmt.Errorf("%w: unknown resolution %q", ErrInvalidMeasurementQuery, q.Resolution)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return measurements, nil
+}
+
+// Current возвращает последние известные значения параметров скважины
+// (nil, если замеров нет)
+func (s *MeasurementService) Current(ctx context.Context, wellID int) (*entity.Measurement, error) {
+	latest, err := s.repo.Latest(ctx, wellID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return latest, nil
+}
+
+// DeleteMeasurement удаляет замер и пересчитывает текущие значения скважины
+func (s *MeasurementService) DeleteMeasurement(ctx context.Context, wellID, id int) error {
+	well, err := s.wells.GetWell(ctx, wellID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, wellID, id); err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	_, err = s.syncCurrent(ctx, well)
+	return err
+}
+
+// syncCurrent переносит последние значения замеров в карточку скважины.
+// Скважина сохраняется (с пересчетом расчетных параметров), только если
+// значения изменились.
+func (s *MeasurementService) syncCurrent(ctx context.Context, well *entity.Well) (*entity.Well, error) {
+	latest, err := s.Current(ctx, well.ID)
+	if err != nil || latest == nil {
+		return well, err
+	}
+
+	changed := false
+	apply := func(dst *float64, src *float64) {
+		if src != nil && *dst != *src {
+			*dst = *src
+			changed = true
+		}
+	}
+	apply(&well.Pbuf, latest.Pbuf)
+	apply(&well.Ptb, latest.Ptb)
+	apply(&well.Q, latest.Q)
+	apply(&well.TempUst, latest.TempUst)
+	if !changed {
+		return well, nil
+	}
+
+	s.logger.Info("updating current well values from measurements",
+		"well_id", well.ID, "measured_at", latest.MeasuredAt)
+	return s.wells.UpdateWell(ctx, well)
+}
+
+func validateMeasurement(m *entity.Measurement) error {
+	if m.MeasuredAt.IsZero() {
+		return errors.New("measurement time is required")
+	}
+	if m.Pbuf == nil && m.Ptb == nil && m.Q == nil && m.TempUst == nil {
+		return errors.New("at least one parameter is required")
+	}
+	if m.Pbuf != nil && *m.Pbuf <= 0 {
+		return errors.New("pressure must be positive")
+	}
+	if (m.Ptb != nil && *m.Ptb < 0) || (m.Q != nil && *m.Q < 0) {
+		return errors.New("annulus pressure and rate must be non-negative")
+	}
+	if m.TempUst != nil && *m.TempUst <= 0 {
+		return errors.New("temperature must be above absolute zero")
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// internal/service/measurement_service_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryMeasurementRepo - хранилище замеров в памяти для тестов сервиса
+type memoryMeasurementRepo struct {
+	items []*entity.Measurement
+}
+
+func (r *memoryMeasurementRepo) Save(_ context.Context, ms []*entity.Measurement) error {
+	for _, m := range ms {
+		m.ID = len(r.items) + 1
+		r.items = append(r.items, m)
+	}
+	sort.Slice(r.items, func(i, j int) bool { return r.items[i].MeasuredAt.Before(r.items[j].MeasuredAt) })
+	return nil
+}
+
+func (r *memoryMeasurementRepo) List(_ context.Context, wellID int, from, to time.Time) ([]*entity.Measurement, error) {
+	var res []*entity.Measurement
+	for _, m := range r.items {
+		if m.WellID == wellID && !m.MeasuredAt.Before(from) && m.MeasuredAt.Before(to) {
+			res = append(res, m)
+		}
+	}
+	return res, nil
+}
+
+func (r *memoryMeasurementRepo) Aggregate(context.Context, int, time.Time, time.Time, entity.MeasurementResolution) ([]*entity.Measurement, error) {
+	return nil, nil
+}
+
+func (r *memoryMeasurementRepo) Latest(_ context.Context, wellID int) (*entity.Measurement, error) {
+	var latest *entity.Measurement
+	for _, m := range r.items {
+		if m.WellID != wellID {
+			continue
+		}
+		if latest == nil {
+			latest = &entity.Measurement{WellID: wellID}
+		}
+		latest.MeasuredAt = m.MeasuredAt
+		for _, f := range []struct{ dst, src **float64 }{
+			{&latest.Pbuf, &m.Pbuf}, {&latest.Ptb, &m.Ptb}, {&latest.Q, &m.Q}, {&latest.TempUst, &m.TempUst},
+		} {
+			if *f.src != nil {
+				*f.dst = *f.src
+			}
+		}
+	}
+	return latest, nil
+}
+
+func (r *memoryMeasurementRepo) Delete(_ context.Context, _, id int) error {
+	for i, m := range r.items {
+		if m.ID == id {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func ptr(v float64) *float64 { return &v }
+
+func TestAddMeasurementsUpdatesCurrentValues(t *testing.T) {
+	ctx := context.Background()
+	wells := newTestService(t)
+	s := service.NewMeasurementService(&memoryMeasurementRepo{}, wells, logger.New("test"))
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	_, err := s.AddMeasurements(ctx, 1, []*entity.Measurement{
+		{MeasuredAt: day.Add(2 * time.Hour), Pbuf: ptr(9e6)},
+		{MeasuredAt: day, Pbuf: ptr(9.5e6), Q: ptr(140e3)},
+	})
+	require.NoError(t, err)
+
+	// Pbuf - из последнего замера, Q - из последнего замера, где он есть
+	well, err := wells.GetWell(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 9e6, well.Pbuf)
+	assert.Equal(t, 140e3, well.Q)
+
+	list, err := s.Measurements(ctx, 1, service.MeasurementQuery{From: day, To: day.Add(24 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.True(t, list[0].MeasuredAt.Equal(day))
+
+	// Удаление последнего замера возвращает предыдущее значение
+	require.NoError(t, s.DeleteMeasurement(ctx, 1, list[1].ID))
+	well, err = wells.GetWell(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 9.5e6, well.Pbuf)
+}
+
+func TestAddMeasurementsValidation(t *testing.T) {
+	ctx := context.Background()
+	s := service.NewMeasurementService(&memoryMeasurementRepo{}, newTestService(t), logger.New("test"))
+	now := time.Now()
+
+	for _, m := range []*entity.Measurement{
+		{Pbuf: ptr(9e6)},
+		{MeasuredAt: now},
+		{MeasuredAt: now, Pbuf: ptr(0)},
+		{MeasuredAt: now, Q: ptr(-1)},
+	} {
+		_, err := s.AddMeasurements(ctx, 1, []*entity.Measurement{m})
+		assert.ErrorIs(t, err, service.ErrInvalidMeasurement)
+	}
+
+	_, err := s.AddMeasurements(ctx, 2, []*entity.Measurement{{MeasuredAt: now, Q: ptr(1)}})
+	assert.ErrorIs(t, err, service.ErrWellNotFound)
+
+	_, err = s.Measurements(ctx, 1, service.MeasurementQuery{Resolution: "week"})
+	assert.ErrorIs(t, err, service.ErrInvalidMeasurementQuery)
+	_, err = s.Measurements(ctx, 1, service.MeasurementQuery{From: now, To: now.Add(-time.Hour)})
+	assert.ErrorIs(t, err, service.ErrInvalidMeasurementQuery)
+}
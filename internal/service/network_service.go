@@ -0,0 +1,310 @@
+// internal/service/network_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository"
+	"math"
+	"strings"
+)
+
+// ErrInvalidNetwork - узел, участок или условия расчета сети куста заданы неверно
+var ErrInvalidNetwork = errors.New("invalid pad network")
+
+type NetworkService struct {
+	repo   repository.NetworkRepository
+	fields *FieldService
+	wells  *WellService
+	logger logger.Logger
+}
+
+func NewNetworkService(repo repository.NetworkRepository, fields *FieldService, wells *WellService, log logger.Logger) *NetworkService {
+	return &NetworkService{
+		repo:   repo,
+		fields: fields,
+		wells:  wells,
+		logger: log.With("layer", "service"),
+	}
+}
+
+// PadNetwork - сеть сбора куста
+type PadNetwork struct {
+	Pad      *entity.Pad           `json:"pad"`
+	Nodes    []*entity.NetworkNode `json:"nodes"`
+	Segments []*entity.PipeSegment `json:"segments"`
+	Wells    map[int]*entity.Well  `json:"-"` // Скважины узлов по ID
+}
+
+// NetworkQuery - условия расчета сети
+type NetworkQuery struct {
+	HeaderPressure float64         // Давление в коллекторе, Па
+	Rates          map[int]float64 // Дебиты скважин, заданные штуцером, по ID скважины
+}
+
+// NetworkWellState - режим скважины в базовом расчете и в сценарии
+type NetworkWellState struct {
+	WellID   int                             `json:"well_id"`
+	Name     string                          `json:"name"`
+	Base     calculations.NetworkWellResult  `json:"base"`
+	Scenario *calculations.NetworkWellResult `json:"scenario,omitempty"`
+}
+
+// NetworkSolution - режим сети куста без ограничений и со штуцированием
+// части скважин; сравнение показывает влияние скважин друг на друга
+type NetworkSolution struct {
+	PadID          int                         `json:"pad_id"`
+	HeaderPressure float64                     `json:"header_pressure"`
+	Base           *calculations.NetworkResult `json:"base"`
+	Scenario       *calculations.NetworkResult `json:"scenario,omitempty"`
+	Wells          []NetworkWellState          `json:"wells"`
+}
+
+// Network возвращает узлы и участки сети сбора куста
+func (s *NetworkService) Network(ctx context.Context, padID int) (*PadNetwork, error) {
+	pad, err := s.fields.GetPad(ctx, padID)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := s.repo.ListNodes(ctx, padID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	segments, err := s.repo.ListSegments(ctx, padID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+
+	wells := make(map[int]*entity.Well)
+	for _, n := range nodes {
+		if n.WellID == nil {
+			continue
+		}
+		well, err := s.wells.GetWell(ctx, *n.WellID)
+		if err != nil {
+			return nil, err
+		}
+		wells[well.ID] = well
+	}
+	return &PadNetwork{Pad: pad, Nodes: nodes, Segments: segments, Wells: wells}, nil
+}
+
+// AddNode добавляет узел в сеть куста
+func (s *NetworkService) AddNode(ctx context.Context, node *entity.NetworkNode) (*entity.NetworkNode, error) {
+	network, err := s.Network(ctx, node.PadID)
+	if err != nil {
+		return nil, err
+	}
+	node.Name = strings.TrimSpace(node.Name)
+	if node.Name == "" {
+		return nil, fmt.Errorf("%w: node name is required", ErrInvalidNetwork)
+	}
+	if node.Kind, err = entity.ParseNetworkNodeKind(string(node.Kind)); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidNetwork, err)
+	}
+
+	switch node.Kind {
+	case entity.NodeWell:
+		if node.WellID == nil {
+			return nil, fmt.Errorf("%w: well is required for a well node", ErrInvalidNetwork)
+		}
+		well, err := s.wells.GetWell(ctx, *node.WellID)
+		if err != nil {
+			return nil, err
+		}
+		if well.PadID == nil || *well.PadID != node.PadID {
+			return nil, fmt.Errorf("%w: well does not belong to this pad", ErrInvalidNetwork)
+		}
+		if _, ok := network.Wells[well.ID]; ok {
+			return nil, fmt.Errorf("%w: well is already connected to the network", ErrInvalidNetwork)
+		}
+	case entity.NodeHeader:
+		for _, n := range network.Nodes {
+			if n.Kind == entity.NodeHeader {
+				return nil, fmt.Errorf("%w: pad network already has a header", ErrInvalidNetwork)
+			}
+		}
+		node.WellID = nil
+	default:
+		node.WellID = nil
+	}
+
+	if err := s.repo.CreateNode(ctx, node); err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return node, nil
+}
+
+// DeleteNode удаляет узел и примыкающие к нему участки
+func (s *NetworkService) DeleteNode(ctx context.Context, padID, id int) error {
+	if err := s.repo.DeleteNode(ctx, padID, id); err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	return nil
+}
+
+// AddSegment добавляет участок трубопровода между узлами сети куста
+func (s *NetworkService) AddSegment(ctx context.Context, seg *entity.PipeSegment) (*entity.PipeSegment, error) {
+	network, err := s.Network(ctx, seg.PadID)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(map[int]*entity.NetworkNode, len(network.Nodes))
+	for _, n := range network.Nodes {
+		nodes[n.ID] = n
+	}
+	from, to := nodes[seg.FromNodeID], nodes[seg.ToNodeID]
+	switch {
+	case from == nil || to == nil:
+		return nil, fmt.Errorf("%w: segment nodes must belong to this pad", ErrInvalidNetwork)
+	case from.ID == to.ID:
+		return nil, fmt.Errorf("%w: segment must connect two different nodes", ErrInvalidNetwork)
+	case from.Kind == entity.NodeHeader:
+		return nil, fmt.Errorf("%w: segment cannot start at the header", ErrInvalidNetwork)
+	case to.Kind == entity.NodeWell:
+		return nil, fmt.Errorf("%w: segment cannot end at a well", ErrInvalidNetwork)
+	}
+	for _, other := range network.Segments {
+		if other.FromNodeID == seg.FromNodeID {
+			return nil, fmt.Errorf("%w: node already has an outlet segment", ErrInvalidNetwork)
+		}
+	}
+	if seg.Length <= 0 {
+		return nil, fmt.Errorf("%w: segment length must be positive", ErrInvalidNetwork)
+	}
+	if seg.Diameter <= 0 {
+		return nil, fmt.Errorf("%w: segment diameter must be positive", ErrInvalidNetwork)
+	}
+	if seg.Roughness < 0 {
+		return nil, fmt.Errorf("%w: segment roughness must be non-negative", ErrInvalidNetwork)
+	}
+	if math.Abs(seg.Elevation) > seg.Length {
+		return nil, fmt.Errorf("%w: segment elevation change exceeds its length", ErrInvalidNetwork)
+	}
+
+	if err := s.repo.CreateSegment(ctx, seg); err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return seg, nil
+}
+
+// DeleteSegment удаляет участок трубопровода
+func (s *NetworkService) DeleteSegment(ctx context.Context, padID, id int) error {
+	if err := s.repo.DeleteSegment(ctx, padID, id); err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	return nil
+}
+
+// Solve рассчитывает установившийся режим сети куста при давлении в
+// коллекторе q.HeaderPressure: дебиты, буферные давления скважин и давления
+// в узлах. Если заданы дебиты отдельных скважин (штуцирование), режим
+// рассчитывается дважды - без ограничений и по сценарию.
+func (s *NetworkService) Solve(ctx context.Context, padID int, q NetworkQuery) (*NetworkSolution, error) {
+	if q.HeaderPressure <= 0 {
+		return nil, fmt.Errorf("%w: header pressure must be positive", ErrInvalidNetwork)
+	}
+	network, err := s.Network(ctx, padID)
+	if err != nil {
+		return nil, err
+	}
+	for id, rate := range q.Rates {
+		if _, ok := network.Wells[id]; !ok {
+			return nil, fmt.Errorf("%w: well %d is not connected to the network", ErrInvalidNetwork, id)
+		}
+		if rate < 0 {
+			return nil, fmt.Errorf("%w: gas rate cannot be negative", ErrInvalidNetwork)
+		}
+	}
+
+	base, err := solvePadNetwork(network, q.HeaderPressure, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &NetworkSolution{PadID: padID, HeaderPressure: q.HeaderPressure, Base: base}
+	if len(q.Rates) > 0 {
+		if res.Scenario, err = solvePadNetwork(network, q.HeaderPressure, q.Rates); err != nil {
+			return nil, err
+		}
+	}
+
+	wellByNode := make(map[int]*entity.Well)
+	for _, n := range network.Nodes {
+		if n.WellID != nil {
+			wellByNode[n.ID] = network.Wells[*n.WellID]
+		}
+	}
+	for i, wr := range base.Wells {
+		well := wellByNode[wr.NodeID]
+		state := NetworkWellState{WellID: well.ID, Name: well.Name, Base: wr}
+		if res.Scenario != nil {
+			state.Scenario = &res.Scenario.Wells[i]
+		}
+		res.Wells = append(res.Wells, state)
+	}
+	return res, nil
+}
+
+// solvePadNetwork собирает расчетную схему сети. Свойства газа в
+// трубопроводах - средние по скважинам сети, температура - устьевая.
+func solvePadNetwork(network *PadNetwork, header float64, rates map[int]float64) (*calculations.NetworkResult, error) {
+	if len(network.Wells) == 0 {
+		return nil, fmt.Errorf("%w: pad network has no wells", ErrInvalidNetwork)
+	}
+	var temp, gammaG, mu float64
+	for _, w := range network.Wells {
+		temp += w.TempUst
+		gammaG += w.GammaG
+		mu += w.Mu
+	}
+	n := float64(len(network.Wells))
+	temp, gammaG, mu = temp/n, gammaG/n, mu/n
+
+	calc := calculations.Network{HeaderPressure: header}
+	headers := 0
+	for _, node := range network.Nodes {
+		cn := calculations.NetworkNode{ID: node.ID}
+		if node.WellID != nil {
+			w := network.Wells[*node.WellID]
+			cn.Well = &calculations.NetworkWell{
+				Wellbore: calculations.WellboreFromWell(*w),
+				Inflow:   calculations.Inflow{A: w.A, B: w.B, Ppl: w.Ppl},
+				Rate:     rates[w.ID],
+			}
+		}
+		if node.Kind == entity.NodeHeader {
+			calc.Header = node.ID
+			headers++
+		}
+		calc.Nodes = append(calc.Nodes, cn)
+	}
+	if headers != 1 {
+		return nil, fmt.Errorf("%w: pad network must have exactly one header", ErrInvalidNetwork)
+	}
+	for _, seg := range network.Segments {
+		calc.Pipes = append(calc.Pipes, calculations.NetworkPipe{
+			ID:   seg.ID,
+			From: seg.FromNodeID,
+			To:   seg.ToNodeID,
+			Pipe: calculations.Pipe{
+				Length:    seg.Length,
+				Diameter:  seg.Diameter,
+				Roughness: seg.Roughness,
+				Elevation: seg.Elevation,
+				Temp:      temp,
+				GammaG:    gammaG,
+				Mu:        mu,
+			},
+		})
+	}
+
+	res, err := calculations.SolveNetwork(calc, calculations.NetworkOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+	}
+	return &res, nil
+}
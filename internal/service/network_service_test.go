@@ -0,0 +1,137 @@
+// internal/service/network_service_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository/repotest"
+	"gas_wells/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryNetworkRepo - сеть сбора в памяти
+type memoryNetworkRepo struct {
+	nodes    []*entity.NetworkNode
+	segments []*entity.PipeSegment
+}
+
+func (r *memoryNetworkRepo) CreateNode(_ context.Context, n *entity.NetworkNode) error {
+	n.ID = len(r.nodes) + 1
+	r.nodes = append(r.nodes, n)
+	return nil
+}
+
+func (r *memoryNetworkRepo) ListNodes(_ context.Context, padID int) ([]*entity.NetworkNode, error) {
+	var nodes []*entity.NetworkNode
+	for _, n := range r.nodes {
+		if n.PadID == padID {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil
+}
+
+func (r *memoryNetworkRepo) DeleteNode(context.Context, int, int) error { return nil }
+
+func (r *memoryNetworkRepo) CreateSegment(_ context.Context, s *entity.PipeSegment) error {
+	s.ID = len(r.segments) + 1
+	r.segments = append(r.segments, s)
+	return nil
+}
+
+func (r *memoryNetworkRepo) ListSegments(_ context.Context, padID int) ([]*entity.PipeSegment, error) {
+	var segments []*entity.PipeSegment
+	for _, s := range r.segments {
+		if s.PadID == padID {
+			segments = append(segments, s)
+		}
+	}
+	return segments, nil
+}
+
+func (r *memoryNetworkRepo) DeleteSegment(context.Context, int, int) error { return nil }
+
+func TestPadNetwork(t *testing.T) {
+	ctx := context.Background()
+	padID := 1
+	well := func(id int, name string, pad *int) *entity.Well {
+		return &entity.Well{
+			ID: id, Name: name, GammaG: 0.6, Temp: 360, TempUst: 290, Depth: 3000,
+			Ppl: 20e6, Roughness: 2e-5, Diameter: 0.062, A: 0.6, B: 0.0028, Mu: 0.012, PadID: pad,
+		}
+	}
+	wellRepo := &repotest.WellRepo{Wells: map[int]*entity.Well{
+		1: well(1, "1", &padID),
+		2: well(2, "2", &padID),
+		3: well(3, "3", nil),
+	}}
+	wells := service.NewWellService(wellRepo, nil, logger.New("test"))
+	fields := service.NewFieldService(
+		&memoryFieldRepo{
+			fields: map[int]*entity.Field{1: {ID: 1, Name: "Северное"}},
+			pads:   map[int]*entity.Pad{1: {ID: 1, FieldID: 1, Name: "K1"}},
+		},
+		wellRepo, logger.New("test"),
+	)
+	s := service.NewNetworkService(&memoryNetworkRepo{}, fields, wells, logger.New("test"))
+
+	node := func(name string, kind entity.NetworkNodeKind, wellID *int) *entity.NetworkNode {
+		n, err := s.AddNode(ctx, &entity.NetworkNode{PadID: padID, Name: name, Kind: kind, WellID: wellID})
+		require.NoError(t, err)
+		return n
+	}
+	id1, id2, id3 := 1, 2, 3
+	header := node("Коллектор", entity.NodeHeader, nil)
+	tee := node("Тройник", entity.NodeJunction, nil)
+	n1 := node("Устье 1", entity.NodeWell, &id1)
+	n2 := node("Устье 2", entity.NodeWell, &id2)
+
+	_, err := s.AddNode(ctx, &entity.NetworkNode{PadID: padID, Name: "Коллектор 2", Kind: entity.NodeHeader})
+	assert.ErrorIs(t, err, service.ErrInvalidNetwork)
+	_, err = s.AddNode(ctx, &entity.NetworkNode{PadID: padID, Name: "Устье 3", Kind: entity.NodeWell, WellID: &id3})
+	assert.ErrorIs(t, err, service.ErrInvalidNetwork)
+
+	segment := func(from, to *entity.NetworkNode, length float64) error {
+		_, err := s.AddSegment(ctx, &entity.PipeSegment{
+			PadID: padID, FromNodeID: from.ID, ToNodeID: to.ID,
+			Length: length, Diameter: 0.1, Roughness: 5e-5,
+		})
+		return err
+	}
+	require.NoError(t, segment(n1, tee, 1500))
+	require.NoError(t, segment(n2, tee, 1500))
+	require.NoError(t, segment(tee, header, 3000))
+	assert.ErrorIs(t, segment(n1, header, 100), service.ErrInvalidNetwork)
+	assert.ErrorIs(t, segment(tee, n2, 100), service.ErrInvalidNetwork)
+
+	_, err = s.Solve(ctx, padID, service.NetworkQuery{})
+	assert.ErrorIs(t, err, service.ErrInvalidNetwork)
+
+	base, err := s.Solve(ctx, padID, service.NetworkQuery{HeaderPressure: 4e6})
+	require.NoError(t, err)
+	require.Len(t, base.Wells, 2)
+	assert.Nil(t, base.Scenario)
+	w1 := base.Wells[0].Base
+	assert.Equal(t, 1, base.Wells[0].WellID)
+	assert.True(t, w1.Flowing)
+	assert.Greater(t, w1.Pbuf, 4e6)
+
+	// Штуцирование скважины 1 снижает давление в шлейфе скважины 2
+	res, err := s.Solve(ctx, padID, service.NetworkQuery{
+		HeaderPressure: 4e6,
+		Rates:          map[int]float64{1: w1.Rate / 2},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, res.Scenario)
+	neighbour := res.Wells[1]
+	assert.True(t, res.Wells[0].Scenario.Choked)
+	assert.Less(t, neighbour.Scenario.Pbuf, neighbour.Base.Pbuf)
+	assert.Greater(t, neighbour.Scenario.Rate, neighbour.Base.Rate)
+
+	_, err = s.Solve(ctx, padID, service.NetworkQuery{HeaderPressure: 4e6, Rates: map[int]float64{3: 1e5}})
+	assert.ErrorIs(t, err, service.ErrInvalidNetwork)
+}
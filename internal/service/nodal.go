@@ -0,0 +1,46 @@
+// internal/service/nodal.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+)
+
+// NodalQuery - параметры узлового анализа
+type NodalQuery struct {
+	Pbuf   float64 // Буферное давление сценария, Па (0 - текущее давление скважины)
+	Points int     // Число точек на кривых
+}
+
+// NodalAnalysis находит рабочую точку скважины - пересечение кривой притока
+// по коэффициентам A и B с кривой лифта по НКТ при заданном буферном давлении
+func (s *WellService) NodalAnalysis(ctx context.Context, id int, q NodalQuery) (*calculations.NodalResult, error) {
+	well, err := s.GetWell(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.nodalAnalysis(well, q)
+}
+
+func (s *WellService) nodalAnalysis(well *entity.Well, q NodalQuery) (*calculations.NodalResult, error) {
+	pbuf := q.Pbuf
+	if pbuf <= 0 {
+		pbuf = well.Pbuf
+	}
+	if pbuf <= 0 {
+		return nil, fmt.Errorf("%w: wellhead pressure is required", ErrInvalidWell)
+	}
+
+	res, err := calculations.SolveNodal(
+		calculations.WellboreFromWell(*well),
+		calculations.Inflow{A: well.A, B: well.B, Ppl: well.Ppl},
+		pbuf,
+		calculations.NodalOptions{Points: q.Points},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+	}
+	return &res, nil
+}
@@ -0,0 +1,175 @@
+// internal/service/production_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository"
+	"time"
+)
+
+const hoursPerDay = 24
+
+// ErrInvalidProduction - история добычи не прошла проверку или недостаточна для анализа
+var ErrInvalidProduction = errors.New("invalid production data")
+
+type ProductionService struct {
+	repo   repository.ProductionRepository
+	wells  *WellService
+	logger logger.Logger
+}
+
+func NewProductionService(repo repository.ProductionRepository, wells *WellService, log logger.Logger) *ProductionService {
+	return &ProductionService{
+		repo:   repo,
+		wells:  wells,
+		logger: log.With("layer", "service"),
+	}
+}
+
+// AddRecords сохраняет записи истории добычи скважины
+func (s *ProductionService) AddRecords(ctx context.Context, wellID int, records []*entity.ProductionRecord) error {
+	if _, err := s.wells.GetWell(ctx, wellID); err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("%w: no production records provided", ErrInvalidProduction)
+	}
+	for _, rec := range records {
+		if rec.Date.IsZero() {
+			return fmt.Errorf("%w: production date is required", ErrInvalidProduction)
+		}
+		if rec.Q < 0 {
+			return fmt.Errorf("%w: rate on %s must be non-negative", ErrInvalidProduction, rec.Date.Format(time.DateOnly))
+		}
+		rec.WellID = wellID
+	}
+
+	if err := s.repo.Save(ctx, records); err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	return nil
+}
+
+// ListRecords возвращает историю добычи скважины
+func (s *ProductionService) ListRecords(ctx context.Context, wellID int) ([]*entity.ProductionRecord, error) {
+	records, err := s.repo.ListByWell(ctx, wellID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return records, nil
+}
+
+// DeleteRecord удаляет запись истории добычи
+func (s *ProductionService) DeleteRecord(ctx context.Context, wellID, id int) error {
+	if err := s.repo.Delete(ctx, wellID, id); err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	return nil
+}
+
+// DeclineQuery - параметры анализа падения добычи
+type DeclineQuery struct {
+	Model         calculations.DeclineModel // Пусто - модель с наименьшей ошибкой
+	EconomicLimit float64                   // Экономический предел, м³/сут (0 - Qmin скважины)
+	Points        int                       // Число точек прогноза
+}
+
+// DatedRate - дебит (м³/сут) на дату
+type DatedRate struct {
+	Date time.Time `json:"date"`
+	Q    float64   `json:"q"`
+}
+
+// DeclineResult - кривая падения Арпса по истории добычи и прогноз
+type DeclineResult struct {
+	WellID        int                       `json:"well_id"`
+	Start         time.Time                 `json:"start"` // Начало истории, t = 0
+	Fit           calculations.DeclineFit   `json:"fit"`
+	Fits          []calculations.DeclineFit `json:"fits"` // Все модели для сравнения
+	History       []DatedRate               `json:"history"`
+	Fitted        []DatedRate               `json:"fitted"`   // Кривая на интервале истории
+	Forecast      []DatedRate               `json:"forecast"` // От последней даты до предела
+	EconomicLimit float64                   `json:"economic_limit"`
+	LimitDate     *time.Time                `json:"limit_date,omitempty"`
+	Remaining     float64                   `json:"remaining"` // Остаточные извлекаемые запасы, м³
+}
+
+// DeclineAnalysis подбирает кривую Арпса по истории добычи скважины
+// и строит прогноз до экономического предела
+func (s *ProductionService) DeclineAnalysis(ctx context.Context, wellID int, q DeclineQuery) (*DeclineResult, error) {
+	well, err := s.wells.GetWell(ctx, wellID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.ListRecords(ctx, wellID)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: production history is empty", ErrInvalidProduction)
+	}
+
+	limit := q.EconomicLimit
+	if limit <= 0 {
+		limit = well.Qmin
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: economic limit is required: set it explicitly or calculate Qmin", ErrInvalidProduction)
+	}
+
+	res := &DeclineResult{
+		WellID:        wellID,
+		Start:         records[0].Date,
+		EconomicLimit: limit,
+	}
+	points := make([]calculations.RatePoint, len(records))
+	for i, rec := range records {
+		points[i] = calculations.RatePoint{T: res.days(rec.Date), Q: rec.Q}
+		res.History = append(res.History, DatedRate{Date: rec.Date, Q: rec.Q})
+	}
+
+	for _, m := range []calculations.DeclineModel{
+		calculations.DeclineExponential, calculations.DeclineHyperbolic, calculations.DeclineHarmonic,
+	} {
+		if fit, err := calculations.FitDecline(points, m); err == nil {
+			res.Fits = append(res.Fits, fit)
+		}
+	}
+	res.Fit, err = calculations.FitDecline(points, q.Model)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+	}
+
+	tLast := points[len(points)-1].T
+	for _, p := range points {
+		res.Fitted = append(res.Fitted, DatedRate{Date: res.date(p.T), Q: res.Fit.Rate(p.T)})
+	}
+
+	forecast, err := calculations.ForecastDecline(res.Fit, tLast, limit, q.Points)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+	}
+	for _, p := range forecast.Curve {
+		res.Forecast = append(res.Forecast, DatedRate{Date: res.date(p.T), Q: p.Q})
+	}
+	limitDate := res.date(forecast.TimeToLimit)
+	res.LimitDate = &limitDate
+	res.Remaining = forecast.Remaining
+
+	return res, nil
+}
+
+// days - число суток от начала истории до даты d
+func (r *DeclineResult) days(d time.Time) float64 {
+	return d.Sub(r.Start).Hours() / hoursPerDay
+}
+
+// date - дата через t суток от начала истории
+func (r *DeclineResult) date(t float64) time.Time {
+	return r.Start.Add(time.Duration(t * hoursPerDay * float64(time.Hour)))
+}
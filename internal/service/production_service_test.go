@@ -0,0 +1,89 @@
+// internal/service/production_service_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryProductionRepo - история добычи в памяти для тестов сервиса
+type memoryProductionRepo struct {
+	records []*entity.ProductionRecord
+}
+
+func (r *memoryProductionRepo) Save(_ context.Context, records []*entity.ProductionRecord) error {
+	r.records = append(r.records, records...)
+	return nil
+}
+
+func (r *memoryProductionRepo) ListByWell(_ context.Context, wellID int) ([]*entity.ProductionRecord, error) {
+	var res []*entity.ProductionRecord
+	for _, rec := range r.records {
+		if rec.WellID == wellID {
+			res = append(res, rec)
+		}
+	}
+	return res, nil
+}
+
+func (r *memoryProductionRepo) Delete(context.Context, int, int) error { return nil }
+
+func TestDeclineAnalysis(t *testing.T) {
+	ctx := context.Background()
+	wells := newTestService(t)
+	s := service.NewProductionService(&memoryProductionRepo{}, wells, logger.New("test"))
+
+	_, err := s.DeclineAnalysis(ctx, 1, service.DeclineQuery{})
+	assert.ErrorIs(t, err, service.ErrInvalidProduction, "empty history")
+	for _, rec := range []*entity.ProductionRecord{{Q: 100e3}, {Date: time.Now(), Q: -1}} {
+		assert.ErrorIs(t, s.AddRecords(ctx, 1, []*entity.ProductionRecord{rec}), service.ErrInvalidProduction)
+	}
+
+	// Экспоненциальное падение 0.1% в сутки
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var records []*entity.ProductionRecord
+	for m := 0; m < 12; m++ {
+		date := start.AddDate(0, m, 0)
+		days := date.Sub(start).Hours() / 24
+		records = append(records, &entity.ProductionRecord{Date: date, Q: 200e3 * math.Exp(-0.001*days)})
+	}
+	require.NoError(t, s.AddRecords(ctx, 1, records))
+
+	res, err := s.DeclineAnalysis(ctx, 1, service.DeclineQuery{
+		Model:         calculations.DeclineExponential,
+		EconomicLimit: 50e3,
+	})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.001, res.Fit.Di, 1e-6)
+	assert.Len(t, res.Fits, 3)
+	assert.Len(t, res.Fitted, 12)
+
+	// ln(4)/0.001 ≈ 1386 сут от начала истории
+	require.NotNil(t, res.LimitDate)
+	assert.WithinDuration(t, start.AddDate(0, 0, 1386), *res.LimitDate, 48*time.Hour)
+	last := records[11].Q
+	assert.InDelta(t, (last-50e3)/0.001, res.Remaining, 1e3)
+
+	// Без явного предела используется Qmin скважины, рассчитываемый при сохранении
+	_, err = s.DeclineAnalysis(ctx, 1, service.DeclineQuery{})
+	assert.ErrorIs(t, err, service.ErrInvalidProduction)
+
+	well, err := wells.GetWell(ctx, 1)
+	require.NoError(t, err)
+	well, err = wells.UpdateWell(ctx, well)
+	require.NoError(t, err)
+	require.Positive(t, well.Qmin)
+
+	res, err = s.DeclineAnalysis(ctx, 1, service.DeclineQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, well.Qmin, res.EconomicLimit)
+}
@@ -0,0 +1,72 @@
+// internal/service/properties.go
+package service
+
+import (
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+	"math"
+)
+
+// WellGasPropertiesResult - свойства газа на устье и на забое скважины
+type WellGasPropertiesResult struct {
+	Wellhead   *calculations.GasProperties `json:"wellhead,omitempty"`
+	Bottomhole *calculations.GasProperties `json:"bottomhole,omitempty"`
+}
+
+// WellGasProperties рассчитывает свойства газа при устьевых (Pbuf, TempUst)
+// и забойных (Pz, Temp) условиях
+func (s *WellService) WellGasProperties(well *entity.Well) (*WellGasPropertiesResult, error) {
+	if well.GammaG <= 0 {
+		return nil, fmt.Errorf("%w: gas gravity is required", ErrInvalidWell)
+	}
+
+	res := &WellGasPropertiesResult{}
+	if well.Pbuf > 0 && well.TempUst > 0 {
+		props, err := calculations.GasPropertiesAt(well.Pbuf, well.TempUst, well.GammaG, calculations.ZOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+		}
+		res.Wellhead = &props
+	}
+	if well.Pz > 0 && well.Temp > 0 {
+		props, err := calculations.GasPropertiesAt(well.Pz, well.Temp, well.GammaG, calculations.ZOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
+		}
+		res.Bottomhole = &props
+	}
+	if res.Wellhead == nil && res.Bottomhole == nil {
+		return nil, fmt.Errorf("%w: wellhead or bottomhole conditions are required", ErrInvalidWell)
+	}
+
+	return res, nil
+}
+
+// fillViscosity рассчитывает вязкость газа по Ли-Гонсалесу-Икину при
+// средних по стволу условиях, если Pz замерено, иначе - при устьевых:
+// расчетное Pz само зависит от вязкости, и повторное сохранение
+// без изменений давало бы другие значения.
+// Вязкость, заданная вручную (MuManual), не пересчитывается.
+func (s *WellService) fillViscosity(well *entity.Well) {
+	if well.MuManual && well.Mu > 0 {
+		return
+	}
+	if well.GammaG <= 0 || well.Pbuf <= 0 || well.TempUst <= 0 {
+		return
+	}
+
+	p, t := well.Pbuf, well.TempUst
+	if well.PzManual && well.Pz > 0 && well.Temp > 0 {
+		p = (well.Pbuf + well.Pz) / 2
+		t = (well.TempUst + well.Temp) / 2
+	}
+
+	props, err := calculations.GasPropertiesAt(p, t, well.GammaG, calculations.ZOptions{})
+	if err != nil {
+		s.logger.Warn("gas viscosity calculation failed", "well", well.Name, "error", err)
+		return
+	}
+	well.MuManual = false
+	well.Mu = math.Round(props.Viscosity*1e5) / 1e5
+}
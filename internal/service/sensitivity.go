@@ -0,0 +1,104 @@
+// internal/service/sensitivity.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+)
+
+// DefaultTubingDiameters - внутренние диаметры стандартных НКТ 60, 73 и 89 мм, м
+var DefaultTubingDiameters = []float64{0.0503, 0.062, 0.076}
+
+// TubingCase - результат расчета для одного диаметра НКТ
+type TubingCase struct {
+	Diameter      float64 `json:"diameter"`       // Внутренний диаметр, м
+	Flowing       bool    `json:"flowing"`        // Скважина фонтанирует
+	Rate          float64 `json:"rate"`           // Дебит в рабочей точке, м³/сут
+	Pwf           float64 `json:"pwf"`            // Забойное давление, Па
+	Friction      float64 `json:"friction"`       // Потери давления на трение в НКТ, Па
+	CriticalRate  float64 `json:"critical_rate"`  // Критический дебит выноса жидкости, м³/сут
+	AboveCritical bool    `json:"above_critical"` // Дебит выше критического
+	Error         string  `json:"error,omitempty"`
+}
+
+// TubingSensitivityResult - сравнение вариантов диаметра НКТ
+type TubingSensitivityResult struct {
+	WellID int          `json:"well_id"`
+	Pbuf   float64      `json:"pbuf"`
+	Model  string       `json:"qmin_model"`
+	Cases  []TubingCase `json:"cases"`
+}
+
+// TubingSensitivity рассчитывает рабочий дебит, потери на трение и запас
+// по выносу жидкости для каждого из диаметров НКТ при текущем буферном давлении
+func (s *WellService) TubingSensitivity(ctx context.Context, id int, diameters []float64) (*TubingSensitivityResult, error) {
+	well, err := s.GetWell(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(diameters) == 0 {
+		diameters = DefaultTubingDiameters
+	}
+	model, err := calculations.ParseUnloadingModel(well.QminModel)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidWell, err)
+	}
+
+	res := &TubingSensitivityResult{
+		WellID: well.ID,
+		Pbuf:   well.Pbuf,
+		Model:  string(model),
+		Cases:  make([]TubingCase, 0, len(diameters)),
+	}
+	for _, d := range diameters {
+		c, err := s.tubingCase(*well, d, model)
+		if err != nil {
+			c.Error = err.Error()
+		}
+		res.Cases = append(res.Cases, c)
+	}
+	return res, nil
+}
+
+func (s *WellService) tubingCase(well entity.Well, d float64, model calculations.UnloadingModel) (TubingCase, error) {
+	c := TubingCase{Diameter: d}
+	if d <= 0 {
+		return c, fmt.Errorf("%w: tubing diameter must be positive", ErrInvalidWell)
+	}
+	well.Diameter = d
+
+	// Кривые не нужны - достаточно рабочей точки
+	nodal, err := s.nodalAnalysis(&well, NodalQuery{Points: 2})
+	if err != nil {
+		return c, err
+	}
+	c.Flowing = nodal.Flowing
+	c.Rate = nodal.Rate
+	c.Pwf = nodal.Pwf
+
+	if nodal.Flowing {
+		lift, err := calculations.FlowingBottomholePressure(
+			calculations.WellboreFromWell(well), well.Pbuf, nodal.Rate, calculations.TraverseOptions{})
+		if err != nil {
+			return c, fmt.Errorf("%w: %w", ErrCalculation, err)
+		}
+		c.Friction = lift.Friction
+	}
+
+	crit, err := calculations.CriticalUnloadingRate(calculations.UnloadingInput{
+		Pressure:      well.Pbuf,
+		Temp:          well.TempUst,
+		Diameter:      d,
+		GammaG:        well.GammaG,
+		LiquidDensity: well.Rog,
+	}, model)
+	if err != nil {
+		return c, fmt.Errorf("%w: %w", ErrCalculation, err)
+	}
+	c.CriticalRate = crit.Rate
+	c.AboveCritical = c.Flowing && c.Rate >= crit.Rate
+
+	return c, nil
+}
@@ -0,0 +1,53 @@
+// internal/service/sensitivity_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTubingSensitivity(t *testing.T) {
+	s := newTestService(t)
+	res, err := s.TubingSensitivity(context.Background(), 1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 10e6, res.Pbuf)
+	assert.NotEmpty(t, res.Model)
+	require.Len(t, res.Cases, len(service.DefaultTubingDiameters))
+
+	// Больший диаметр - меньше трение, больше дебит и критический дебит
+	for i, c := range res.Cases {
+		require.Empty(t, c.Error)
+		assert.Equal(t, service.DefaultTubingDiameters[i], c.Diameter)
+		assert.True(t, c.Flowing)
+		assert.Positive(t, c.Friction)
+		assert.Equal(t, c.Rate >= c.CriticalRate, c.AboveCritical)
+		if i > 0 {
+			assert.Greater(t, c.Rate, res.Cases[i-1].Rate)
+			assert.Greater(t, c.CriticalRate, res.Cases[i-1].CriticalRate)
+		}
+	}
+
+	// Исходная скважина не изменилась
+	well, err := s.GetWell(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0.062, well.Diameter)
+}
+
+func TestTubingSensitivityInvalid(t *testing.T) {
+	s := newTestService(t)
+
+	// Ошибка варианта не прерывает расчет остальных
+	res, err := s.TubingSensitivity(context.Background(), 1, []float64{0.062, -0.01})
+	require.NoError(t, err)
+	require.Len(t, res.Cases, 2)
+	assert.Empty(t, res.Cases[0].Error)
+	assert.NotEmpty(t, res.Cases[1].Error)
+	assert.False(t, res.Cases[1].Flowing)
+
+	_, err = s.TubingSensitivity(context.Background(), 2, nil)
+	assert.ErrorIs(t, err, service.ErrWellNotFound)
+}
@@ -0,0 +1,229 @@
+// internal/service/sweep.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+)
+
+// ErrInvalidSweep - параметры варьирования заданы неверно
+var ErrInvalidSweep = errors.New("invalid sweep query")
+
+const (
+	defaultSweepSteps = 10
+	maxSweepSteps     = 50
+	maxSweepCells     = 900
+)
+
+// SweepAxis - варьируемый параметр скважины: имя поля в JSON (pbuf, ppl, wgf...)
+// и равномерная сетка значений от From до To в единицах entity.Well
+type SweepAxis struct {
+	Param string  `json:"param"`
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Steps int     `json:"steps"` // Число значений, включая границы
+}
+
+// Values возвращает значения параметра на сетке
+func (a SweepAxis) Values() []float64 {
+	if a.Steps < 2 {
+		return []float64{a.From}
+	}
+	values := make([]float64, a.Steps)
+	for i := range values {
+		values[i] = a.From + (a.To-a.From)*float64(i)/float64(a.Steps-1)
+	}
+	return values
+}
+
+// SweepQuery - одно- или двумерная сетка параметров
+type SweepQuery struct {
+	X SweepAxis
+	Y *SweepAxis // nil - одномерный расчет
+}
+
+// SweepCell - расчетные показатели скважины в узле сетки
+type SweepCell struct {
+	X             float64  `json:"x"`
+	Y             float64  `json:"y,omitempty"`
+	Pz            float64  `json:"pz"`             // Забойное давление по НКТ, Па
+	Mu            float64  `json:"mu"`             // Вязкость газа, мПа·с
+	Qmin          float64  `json:"qmin"`           // Критический дебит выноса жидкости, м³/сут
+	LiquidLoading bool     `json:"liquid_loading"` // Q < Qmin
+	Flowing       bool     `json:"flowing"`        // Скважина фонтанирует (узловой анализ)
+	Rate          float64  `json:"rate"`           // Дебит в рабочей точке, м³/сут
+	Pwf           float64  `json:"pwf"`            // Забойное давление в рабочей точке, Па
+	Warnings      []string `json:"warnings,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// SweepResult - матрица результатов: Cells[i][j] соответствует YValues[i] и XValues[j].
+// В одномерном расчете матрица состоит из одной строки.
+type SweepResult struct {
+	WellID  int           `json:"well_id"`
+	X       SweepAxis     `json:"x"`
+	Y       *SweepAxis    `json:"y,omitempty"`
+	XValues []float64     `json:"x_values"`
+	YValues []float64     `json:"y_values,omitempty"`
+	Cells   [][]SweepCell `json:"cells"`
+}
+
+// SweepParam - входной параметр скважины, доступный для варьирования
+type SweepParam struct {
+	Name  string `json:"name"`  // Имя поля entity.Well в JSON
+	Title string `json:"title"` // Название для пользователя
+	Unit  string `json:"unit"`  // Единица измерения (как в entity.Well)
+
+	field func(*entity.Well) *float64
+}
+
+// sweepParams - входные параметры скважины; расчетные величины (Pz, Qmin)
+// и не влияющие на расчет (Ptb, Pmax) не варьируются
+var sweepParams = []SweepParam{
+	{"gamma", "Относительная плотность газа", "", func(w *entity.Well) *float64 { return &w.GammaG }},
+	{"temp", "Температура пласта", "К", func(w *entity.Well) *float64 { return &w.Temp }},
+	{"tempust", "Температура на устье", "К", func(w *entity.Well) *float64 { return &w.TempUst }},
+	{"depth", "Глубина скважины", "м", func(w *entity.Well) *float64 { return &w.Depth }},
+	{"pbuf", "Буферное давление", "Па", func(w *entity.Well) *float64 { return &w.Pbuf }},
+	{"ppl", "Пластовое давление", "Па", func(w *entity.Well) *float64 { return &w.Ppl }},
+	{"q", "Дебит газа", "м³/сут", func(w *entity.Well) *float64 { return &w.Q }},
+	{"roughness", "Шероховатость НКТ", "м", func(w *entity.Well) *float64 { return &w.Roughness }},
+	{"diameter", "Диаметр НКТ", "м", func(w *entity.Well) *float64 { return &w.Diameter }},
+	{"a", "Коэффициент A", "МПа²·сут/тыс.м³", func(w *entity.Well) *float64 { return &w.A }},
+	{"b", "Коэффициент B", "(МПа·сут/тыс.м³)²", func(w *entity.Well) *float64 { return &w.B }},
+	{"mu", "Вязкость газа", "мПа·с", func(w *entity.Well) *float64 { return &w.Mu }},
+	{"wgf", "Водогазовый фактор", "см³/м³", func(w *entity.Well) *float64 { return &w.WGF }},
+	{"rog", "Плотность воды", "кг/м³", func(w *entity.Well) *float64 { return &w.Rog }},
+	{"hw", "Высота столба ГЖС", "м", func(w *entity.Well) *float64 { return &w.Hw }},
+	{"choke", "Диаметр штуцера", "м", func(w *entity.Well) *float64 { return &w.Choke }},
+	{"pline", "Давление в шлейфе", "Па", func(w *entity.Well) *float64 { return &w.Pline }},
+}
+
+// SweepParams возвращает параметры скважины, доступные для варьирования
+func SweepParams() []SweepParam {
+	return sweepParams
+}
+
+// sweepParam возвращает варьируемый параметр по имени в JSON
+func sweepParam(name string) (SweepParam, bool) {
+	for _, p := range sweepParams {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return SweepParam{}, false
+}
+
+// setWellParam присваивает значение входному параметру скважины по имени в JSON
+func setWellParam(well *entity.Well, name string, value float64) error {
+	p, ok := sweepParam(name)
+	if !ok {
+		return fmt.Errorf("unknown well parameter %q", name)
+	}
+	*p.field(well) = value
+	return nil
+}
+
+// Sweep варьирует один или два параметра скважины по сетке и для каждого узла
+// выполняет полный расчет: вязкость, забойное давление, Qmin и узловой анализ.
+// Сохраненная скважина не изменяется - расчет идет на копиях.
+func (s *WellService) Sweep(ctx context.Context, id int, q SweepQuery) (*SweepResult, error) {
+	well, err := s.GetWell(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.sweep(well, q)
+}
+
+func (s *WellService) sweep(well *entity.Well, q SweepQuery) (*SweepResult, error) {
+	axes := []*SweepAxis{&q.X}
+	if q.Y != nil {
+		if q.Y.Param == q.X.Param {
+			return nil, fmt.Errorf("%w: sweep parameters must differ", ErrInvalidSweep)
+		}
+		axes = append(axes, q.Y)
+	}
+	cells := 1
+	for _, a := range axes {
+		if _, ok := sweepParam(a.Param); !ok {
+			return nil, fmt.Errorf("%w: unknown well parameter %q", ErrInvalidSweep, a.Param)
+		}
+		if a.Steps == 0 {
+			a.Steps = defaultSweepSteps
+		}
+		if a.Steps < 1 || a.Steps > maxSweepSteps {
+			return nil, fmt.Errorf("%w: sweep steps must be between 1 and %d", ErrInvalidSweep, maxSweepSteps)
+		}
+		cells *= a.Steps
+	}
+	if cells > maxSweepCells {
+		return nil, fmt.Errorf("%w: sweep grid is too large: %d cells, max %d", ErrInvalidSweep, cells, maxSweepCells)
+	}
+
+	res := &SweepResult{
+		WellID:  well.ID,
+		X:       q.X,
+		Y:       q.Y,
+		XValues: q.X.Values(),
+	}
+	yValues := []float64{0}
+	if q.Y != nil {
+		res.YValues = q.Y.Values()
+		yValues = res.YValues
+	}
+
+	res.Cells = make([][]SweepCell, len(yValues))
+	for i, y := range yValues {
+		res.Cells[i] = make([]SweepCell, len(res.XValues))
+		for j, x := range res.XValues {
+			res.Cells[i][j] = s.sweepCell(*well, q, x, y)
+		}
+	}
+	return res, nil
+}
+
+// sweepCell рассчитывает копию скважины с измененными параметрами
+func (s *WellService) sweepCell(well entity.Well, q SweepQuery, x, y float64) SweepCell {
+	cell := SweepCell{X: x, Y: y}
+
+	// Расчетные величины пересчитываются, вязкость - если не варьируется явно
+	well.Pz, well.Qmin = 0, 0
+	well.PzManual = false
+	if !well.MuManual {
+		well.Mu = 0
+	}
+	well.Warnings = nil
+
+	_ = setWellParam(&well, q.X.Param, x)
+	if q.Y != nil {
+		_ = setWellParam(&well, q.Y.Param, y)
+	}
+	if q.X.Param == "mu" || (q.Y != nil && q.Y.Param == "mu") {
+		well.MuManual = true
+	}
+
+	if err := s.calculateWellParameters(&well); err != nil {
+		cell.Error = err.Error()
+		return cell
+	}
+	cell.Pz = well.Pz
+	cell.Mu = well.Mu
+	cell.Qmin = well.Qmin
+	cell.LiquidLoading = well.LiquidLoading
+	cell.Warnings = well.Warnings
+
+	// Узловой анализ возможен только при известных коэффициентах притока
+	if well.A > 0 && well.Ppl > 0 {
+		nodal, err := s.nodalAnalysis(&well, NodalQuery{Points: 2})
+		if err != nil {
+			cell.Error = err.Error()
+			return cell
+		}
+		cell.Flowing = nodal.Flowing
+		cell.Rate = nodal.Rate
+		cell.Pwf = nodal.Pwf
+	}
+	return cell
+}
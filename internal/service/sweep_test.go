@@ -0,0 +1,92 @@
+// internal/service/sweep_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository/repotest"
+	"gas_wells/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService(t *testing.T) *service.WellService {
+	t.Helper()
+	repo := &repotest.WellRepo{Wells: map[int]*entity.Well{
+		1: {
+			ID: 1, Name: "1", GammaG: 0.6, Temp: 360, TempUst: 290, Depth: 3000,
+			Pbuf: 10e6, Ppl: 20e6, Q: 150e3, Roughness: 2e-5, Diameter: 0.062,
+			A: 0.6, B: 0.0028, Rog: 1000,
+		},
+	}}
+	return service.NewWellService(repo, nil, logger.New("test"))
+}
+
+func TestSweep1D(t *testing.T) {
+	s := newTestService(t)
+	res, err := s.Sweep(context.Background(), 1, service.SweepQuery{
+		X: service.SweepAxis{Param: "pbuf", From: 4e6, To: 12e6, Steps: 5},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Cells, 1)
+	require.Len(t, res.Cells[0], 5)
+	assert.Equal(t, []float64{4e6, 6e6, 8e6, 10e6, 12e6}, res.XValues)
+
+	// Снижение буферного давления увеличивает рабочий дебит
+	for j := 1; j < len(res.Cells[0]); j++ {
+		prev, cur := res.Cells[0][j-1], res.Cells[0][j]
+		require.Empty(t, cur.Error)
+		assert.True(t, cur.Flowing)
+		assert.Less(t, cur.Rate, prev.Rate)
+	}
+}
+
+func TestSweep2D(t *testing.T) {
+	s := newTestService(t)
+	res, err := s.Sweep(context.Background(), 1, service.SweepQuery{
+		X: service.SweepAxis{Param: "pbuf", From: 6e6, To: 10e6, Steps: 3},
+		Y: &service.SweepAxis{Param: "diameter", From: 0.0503, To: 0.076, Steps: 2},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Cells, 2)
+	require.Len(t, res.Cells[1], 3)
+	assert.Equal(t, 0.076, res.Cells[1][0].Y)
+
+	// Больший диаметр - меньше трение и больше критический дебит
+	assert.Greater(t, res.Cells[1][0].Rate, res.Cells[0][0].Rate)
+	assert.Greater(t, res.Cells[1][0].Qmin, res.Cells[0][0].Qmin)
+
+	// Исходная скважина не изменилась
+	well, err := s.GetWell(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 10e6, well.Pbuf)
+	assert.Equal(t, 0.062, well.Diameter)
+}
+
+func TestSweepInvalid(t *testing.T) {
+	s := newTestService(t)
+	// Варьируются только входные параметры: расчетные Pz, Qmin
+	// и нечисловые поля недоступны
+	for _, param := range []string{"name", "pz", "qmin", "pmax"} {
+		_, err := s.Sweep(context.Background(), 1, service.SweepQuery{X: service.SweepAxis{Param: param}})
+		assert.ErrorIs(t, err, service.ErrInvalidSweep, param)
+	}
+	for _, p := range service.SweepParams() {
+		assert.NotEmpty(t, p.Title, p.Name)
+	}
+
+	_, err := s.Sweep(context.Background(), 1, service.SweepQuery{X: service.SweepAxis{Param: "mu", From: 0.01, To: 0.02, Steps: 2}})
+	require.NoError(t, err)
+
+	_, err = s.Sweep(context.Background(), 1, service.SweepQuery{
+		X: service.SweepAxis{Param: "pbuf", Steps: 50},
+		Y: &service.SweepAxis{Param: "ppl", Steps: 50},
+	})
+	assert.ErrorIs(t, err, service.ErrInvalidSweep)
+
+	_, err = s.Sweep(context.Background(), 2, service.SweepQuery{X: service.SweepAxis{Param: "pbuf"}})
+	assert.ErrorIs(t, err, service.ErrWellNotFound)
+}
@@ -0,0 +1,208 @@
+// internal/service/well_export.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/auth"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/pkg/exporter"
+	"math"
+	"time"
+)
+
+// MaxExportWells - наибольшее число скважин в одной выгрузке: книга
+// собирается целиком до начала ответа и должна уложиться в тайм-аут запроса
+const MaxExportWells = 5000
+
+// ErrExportTooLarge - по условиям запроса отобрано больше MaxExportWells скважин
+var ErrExportTooLarge = fmt.Errorf("export is limited to %d wells, narrow the filters", MaxExportWells)
+
+// wellResultColumns - столбцы листа расчетных результатов выгрузки
+var wellResultColumns = []exporter.Column{
+	{Key: "id", Header: "ID", Width: 6},
+	{Key: "name", Header: "Название", Width: 14},
+	{Key: "status", Header: "Состояние", Width: 14},
+	{Key: "status_days", Header: "В состоянии, сут", NumFmt: exporter.FmtInt, Width: 11},
+	{Key: "pz", Header: "Pзаб, Па", NumFmt: exporter.FmtInt, Width: 13},
+	{Key: "pz_source", Header: "Источник Pзаб", Width: 10},
+	{Key: "z", Header: "Z на устье", NumFmt: exporter.FmtDecimal3, Width: 9},
+	{Key: "qmin", Header: "Qmin, м³/сут", NumFmt: exporter.FmtInt, Width: 11},
+	{Key: "qmin_model", Header: "Модель Qmin", Width: 10},
+	{Key: "q_qmin", Header: "Q/Qmin", NumFmt: "0.00", Width: 8},
+	{Key: "liquid_loading", Header: "Риск самозадавливания", Width: 10},
+	{Key: "hydrate_temp", Header: "Tгидр, К", NumFmt: exporter.FmtDecimal1, Width: 9},
+	{Key: "subcooling", Header: "Переохлаждение, К", NumFmt: exporter.FmtDecimal1, Width: 11},
+	{Key: "hydrate_risk", Header: "Риск гидратов", Width: 10},
+	{Key: "methanol_rate", Header: "Расход метанола, кг/сут", NumFmt: exporter.FmtDecimal1, Width: 11},
+}
+
+var exportMetaColumns = []exporter.Column{
+	{Key: "param", Header: "Параметр", Width: 28},
+	{Key: "value", Header: "Значение", Width: 40},
+}
+
+// ExportWells выгружает скважины по условиям запроса в книгу Excel: лист
+// с полями скважин, лист расчетных результатов и лист сведений о выгрузке
+// (дата, пользователь, условия отбора). Скважины читаются и записываются
+// потоком; страницы запроса (курсоры, Limit) не учитываются. Больше
+// MaxExportWells скважин не выгружается (ErrExportTooLarge).
+// Книгу нужно закрыть после записи.
+func (s *WellService) ExportWells(ctx context.Context, q entity.WellQuery) (*exporter.StreamWorkbook, error) {
+	if q.Status != "" && !entity.ValidWellStatus(q.Status) {
+		return nil, fmt.Errorf("unknown well status %q", q.Status)
+	}
+	if q.Sort != "" && !entity.ValidWellSortKey(q.Sort) {
+		return nil, fmt.Errorf("unknown sort key %q", q.Sort)
+	}
+
+	count := q
+	count.After, count.Before, count.Limit = nil, nil, 1
+	page, err := s.repo.List(ctx, count)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if page.Total > MaxExportWells {
+		return nil, ErrExportTooLarge
+	}
+
+	book, err := exporter.NewStreamWorkbook()
+	if err != nil {
+		return nil, err
+	}
+	ok := false
+	defer func() {
+		if !ok {
+			book.Close()
+		}
+	}()
+
+	wells, err := book.AddSheet("Скважины", exporter.WellColumns(), true)
+	if err != nil {
+		return nil, err
+	}
+	results, err := book.AddSheet("Расчет", wellResultColumns, true)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := book.AddSheet("Выгрузка", exportMetaColumns, false)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	exported := 0
+	err = s.repo.Each(ctx, q, func(well *entity.Well) error {
+		// Скважины, добавленные после подсчета, тоже не превышают предел
+		if exported++; exported > MaxExportWells {
+			return ErrExportTooLarge
+		}
+		if err := wells.WriteRow(exporter.WellRow(well)...); err != nil {
+			return err
+		}
+		return results.WriteRow(s.wellResultRow(well, now)...)
+	})
+	if errors.Is(err, ErrExportTooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+
+	user := "—"
+	if u := auth.UserFromContext(ctx); u != nil {
+		user = u.Email
+	}
+	rows := [][]interface{}{
+		{"Дата выгрузки", now.Format("02.01.2006 15:04:05")},
+		{"Пользователь", user},
+		{"Скважин", exported},
+	}
+	rows = append(rows, describeWellQuery(q)...)
+	for _, row := range rows {
+		if err := meta.WriteRow(row...); err != nil {
+			return nil, err
+		}
+	}
+
+	ok = true
+	return book, nil
+}
+
+// wellResultRow - расчетные показатели скважины по порядку wellResultColumns.
+// Pz и Qmin берутся сохраненными (они пересчитываются при сохранении
+// скважины), расчет по стволу при выгрузке не повторяется. Показатель,
+// для которого недостаточно данных, остается пустым.
+func (s *WellService) wellResultRow(well *entity.Well, now time.Time) []interface{} {
+	var days interface{}
+	if well.StatusSince != nil {
+		days = math.Floor(now.Sub(*well.StatusSince).Hours() / 24)
+	}
+
+	var pz, pzSource interface{}
+	if well.Pz > 0 {
+		pz, pzSource = well.Pz, "расчет"
+		if well.PzManual {
+			pzSource = "замер"
+		}
+	}
+
+	var z interface{}
+	if well.Pbuf > 0 && well.TempUst > 0 && well.GammaG > 0 {
+		if props, err := calculations.GasPropertiesAt(well.Pbuf, well.TempUst, well.GammaG, calculations.ZOptions{}); err == nil {
+			z = props.Z
+		}
+	}
+
+	var ratio interface{}
+	if well.Qmin > 0 {
+		ratio = well.Q / well.Qmin
+	}
+
+	var hydrateTemp, subcooling, methanol interface{}
+	if res, err := s.AssessHydrates(well, calculations.DefaultHydrateMethod); err == nil {
+		hydrateTemp, subcooling, methanol = res.HydrateTemp, res.Subcooling, res.MethanolRate
+	}
+
+	return []interface{}{
+		well.ID, well.Name, entity.WellStatusName(well.Status), days,
+		pz, pzSource, z, well.Qmin, well.QminModel, ratio, well.LiquidLoading,
+		hydrateTemp, subcooling, well.HydrateRisk, methanol,
+	}
+}
+
+// describeWellQuery - условия отбора и сортировка запроса для листа сведений
+func describeWellQuery(q entity.WellQuery) [][]interface{} {
+	rows := [][]interface{}{}
+	if q.Search != "" {
+		rows = append(rows, []interface{}{"Поиск", q.Search})
+	}
+	if q.Status != "" {
+		rows = append(rows, []interface{}{"Состояние", entity.WellStatusName(q.Status)})
+	}
+	for _, f := range []struct {
+		name string
+		rng  entity.Range
+	}{{"Q, м³/сут", q.Q}, {"Pбуф, Па", q.Pbuf}, {"Глубина, м", q.Depth}} {
+		if f.rng.Min != nil {
+			rows = append(rows, []interface{}{f.name + ", от", *f.rng.Min})
+		}
+		if f.rng.Max != nil {
+			rows = append(rows, []interface{}{f.name + ", до", *f.rng.Max})
+		}
+	}
+	if len(rows) == 0 {
+		rows = append(rows, []interface{}{"Условия отбора", "все скважины"})
+	}
+
+	sortKey, order := q.Sort, "по возрастанию"
+	if sortKey == "" {
+		sortKey = "name"
+	}
+	if q.Desc {
+		order = "по убыванию"
+	}
+	return append(rows, []interface{}{"Сортировка", sortKey + ", " + order})
+}
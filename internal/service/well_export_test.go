@@ -0,0 +1,90 @@
+// internal/service/well_export_test.go
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/auth"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository/repotest"
+	"gas_wells/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExportWells(t *testing.T) {
+	ctx := context.Background()
+	repo := &repotest.WellRepo{Wells: map[int]*entity.Well{}}
+	s := service.NewWellService(repo, nil, logger.New("test"))
+
+	for _, w := range []*entity.Well{
+		{Name: "101", GammaG: 0.6, Temp: 360, TempUst: 290, Depth: 3000, Pbuf: 10e6, Q: 150e3, Roughness: 2e-5, Diameter: 0.062},
+		{Name: "102", GammaG: 0.6, Diameter: 0.062, Pbuf: 8e6, Status: entity.StatusShutIn},
+		{Name: "103", GammaG: 0.6, Temp: 350, TempUst: 285, Depth: 2800, Pbuf: 9e6, Q: 20e3, Roughness: 2e-5, Diameter: 0.076},
+	} {
+		_, err := s.CreateWell(ctx, w)
+		require.NoError(t, err)
+	}
+
+	ctx = auth.WithUser(ctx, &entity.User{ID: 1, Email: "engineer@example.com", Role: entity.RoleEngineer})
+	qMin := 10e3
+	book, err := s.ExportWells(ctx, entity.WellQuery{Status: entity.StatusProducing, Q: entity.Range{Min: &qMin}, Sort: "q", Desc: true})
+	require.NoError(t, err)
+	defer book.Close()
+	var buf bytes.Buffer
+	_, err = book.WriteTo(&buf)
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"Скважины", "Расчет", "Выгрузка"}, f.GetSheetList())
+
+	wells, err := f.GetRows("Скважины")
+	require.NoError(t, err)
+	require.Len(t, wells, 3, "заголовок и две скважины в добыче")
+	assert.Equal(t, "101", wells[1][1])
+	assert.Equal(t, "103", wells[2][1])
+
+	results, err := f.GetRows("Расчет")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	header := map[string]int{}
+	for i, h := range results[0] {
+		header[h] = i
+	}
+	require.Contains(t, header, "Pзаб, Па")
+	assert.NotEmpty(t, results[1][header["Pзаб, Па"]])
+	assert.Equal(t, "расчет", results[1][header["Источник Pзаб"]])
+
+	meta, err := f.GetRows("Выгрузка")
+	require.NoError(t, err)
+	values := map[string]string{}
+	for _, row := range meta[1:] {
+		values[row[0]] = row[1]
+	}
+	assert.Equal(t, "engineer@example.com", values["Пользователь"])
+	assert.Equal(t, "2", values["Скважин"])
+	assert.Equal(t, entity.WellStatusName(entity.StatusProducing), values["Состояние"])
+	assert.Equal(t, "10000", values["Q, м³/сут, от"])
+	assert.Equal(t, "q, по убыванию", values["Сортировка"])
+	assert.NotEmpty(t, values["Дата выгрузки"])
+
+	_, err = s.ExportWells(ctx, entity.WellQuery{Sort: "color"})
+	assert.Error(t, err)
+}
+
+func TestExportWellsLimit(t *testing.T) {
+	repo := &repotest.WellRepo{Wells: map[int]*entity.Well{}}
+	for id := 1; id <= service.MaxExportWells+1; id++ {
+		repo.Wells[id] = &entity.Well{ID: id, Name: "W", Status: entity.StatusProducing}
+	}
+	s := service.NewWellService(repo, nil, logger.New("test"))
+
+	_, err := s.ExportWells(context.Background(), entity.WellQuery{})
+	assert.ErrorIs(t, err, service.ErrExportTooLarge)
+}
@@ -0,0 +1,89 @@
+// internal/service/well_lifecycle.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTransition - переход в это состояние из текущего не допускается
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// WellLifecycle - состояние скважины, допустимые переходы и история
+type WellLifecycle struct {
+	Status    string                  `json:"status"`
+	Since     *time.Time              `json:"since"` // Дата перехода в текущее состояние
+	Next      []string                `json:"next"`  // Состояния, в которые допустим переход
+	History   []*entity.StatusChange  `json:"history"`
+	Durations []entity.StatusDuration `json:"durations"` // Время в каждом состоянии по сегодня
+}
+
+// ChangeStatus переводит скважину в состояние change.To. Причина и дата
+// перехода обязательны; дата не может быть в будущем или раньше
+// предыдущего перехода.
+func (s *WellService) ChangeStatus(ctx context.Context, id int, change *entity.StatusChange) (*entity.Well, error) {
+	well, err := s.GetWell(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	change.WellID, change.From = id, well.Status
+	change.Reason = strings.TrimSpace(change.Reason)
+	switch {
+	case !entity.ValidWellStatus(change.To):
+		return nil, fmt.Errorf("%w: unknown well status %q", ErrInvalidWell, change.To)
+	case !entity.CanTransition(change.From, change.To):
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, change.From, change.To)
+	case change.Reason == "":
+		return nil, fmt.Errorf("%w: transition reason is required", ErrInvalidWell)
+	case change.Date.IsZero():
+		return nil, fmt.Errorf("%w: transition date is required", ErrInvalidWell)
+	case change.Date.After(time.Now()):
+		return nil, fmt.Errorf("%w: transition date cannot be in the future", ErrInvalidWell)
+	}
+	if well.StatusSince != nil && change.Date.Before(*well.StatusSince) {
+		return nil, fmt.Errorf("%w: transition date cannot precede the previous transition on %s",
+			ErrInvalidWell, well.StatusSince.Format("2006-01-02"))
+	}
+
+	after := *well
+	after.Status = change.To
+	entry := s.auditEntry(entity.AuditUpdate, well, &after)
+	if err := s.repo.ChangeStatus(ctx, well, change, entry); err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	s.logger.Info("well status changed", "well_id", id, "from", change.From, "to", change.To)
+	return well, nil
+}
+
+// Lifecycle возвращает состояние скважины, историю переходов
+// и время, проведенное в каждом состоянии
+func (s *WellService) Lifecycle(ctx context.Context, id int) (*WellLifecycle, error) {
+	well, err := s.GetWell(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.repo.StatusHistory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if history == nil {
+		history = []*entity.StatusChange{}
+	}
+
+	next := entity.NextStatuses(well.Status)
+	if next == nil {
+		next = []string{}
+	}
+	return &WellLifecycle{
+		Status:    well.Status,
+		Since:     well.StatusSince,
+		Next:      next,
+		History:   history,
+		Durations: entity.TimeInStatus(history, time.Now()),
+	}, nil
+}
@@ -0,0 +1,72 @@
+// internal/service/well_lifecycle_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository/repotest"
+	"gas_wells/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWellLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := &repotest.WellRepo{Wells: map[int]*entity.Well{}}
+	s := service.NewWellService(repo, nil, logger.New("test"))
+
+	day := func(n int) time.Time {
+		return time.Now().Truncate(24*time.Hour).AddDate(0, 0, n)
+	}
+	well, err := s.CreateWell(ctx, &entity.Well{
+		Name: "101", Status: entity.StatusDrilling, Diameter: 0.062, Pbuf: 10e6, Created: day(-100),
+	})
+	require.NoError(t, err)
+
+	change := func(to, reason string, date time.Time) error {
+		_, err := s.ChangeStatus(ctx, well.ID, &entity.StatusChange{To: to, Reason: reason, Date: date})
+		return err
+	}
+
+	// Из бурения нельзя сразу в добычу
+	assert.ErrorIs(t, change(entity.StatusProducing, "Пуск", day(-60)), service.ErrInvalidTransition)
+	assert.ErrorIs(t, change("unknown", "Пуск", day(-60)), service.ErrInvalidWell)
+	assert.ErrorIs(t, change(entity.StatusCompleted, " ", day(-60)), service.ErrInvalidWell, "причина обязательна")
+	assert.ErrorIs(t, change(entity.StatusCompleted, "Освоение", day(1)), service.ErrInvalidWell, "дата в будущем")
+	assert.ErrorIs(t, change(entity.StatusCompleted, "Освоение", day(-120)), service.ErrInvalidWell, "дата раньше предыдущего перехода")
+
+	require.NoError(t, change(entity.StatusCompleted, "Освоение", day(-60)))
+	require.NoError(t, change(entity.StatusProducing, "Пуск в работу", day(-40)))
+
+	// Правка скважины не меняет состояние в обход перехода
+	edit := *repo.Wells[well.ID]
+	edit.Status = entity.StatusShutIn
+	_, err = s.UpdateWell(ctx, &edit)
+	assert.ErrorIs(t, err, service.ErrInvalidTransition)
+	edit.Status = ""
+	updated, err := s.UpdateWell(ctx, &edit)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusProducing, updated.Status)
+
+	lifecycle, err := s.Lifecycle(ctx, well.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusProducing, lifecycle.Status)
+	assert.Equal(t, []string{entity.StatusShutIn, entity.StatusWorkover}, lifecycle.Next)
+	require.Len(t, lifecycle.History, 3)
+	assert.Equal(t, entity.StatusCompleted, lifecycle.History[2].From)
+
+	days := map[string]float64{}
+	for _, d := range lifecycle.Durations {
+		days[d.Status] = d.Days
+	}
+	assert.InDelta(t, 40, days[entity.StatusDrilling], 0.01)
+	assert.InDelta(t, 20, days[entity.StatusCompleted], 0.01)
+	assert.InDelta(t, 40, days[entity.StatusProducing], 1)
+
+	_, err = s.Lifecycle(ctx, 42)
+	assert.ErrorIs(t, err, service.ErrWellNotFound)
+}
@@ -0,0 +1,96 @@
+// internal/service/well_revisions.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"time"
+)
+
+// ErrRevisionNotFound - версии скважины с таким номером (или на эту дату) нет
+var ErrRevisionNotFound = errors.New("well revision not found")
+
+// RevisionDiff - различия двух версий скважины
+type RevisionDiff struct {
+	From    *entity.WellRevision `json:"from"`
+	To      *entity.WellRevision `json:"to"`
+	Changes []entity.FieldChange `json:"changes"` // Поля, различающиеся в версиях From и To
+}
+
+// WellAsOf возвращает параметры скважины на момент at
+func (s *WellService) WellAsOf(ctx context.Context, id int, at time.Time) (*entity.Well, error) {
+	if _, err := s.GetWell(ctx, id); err != nil {
+		return nil, err
+	}
+	well, err := s.repo.GetByIDAsOf(ctx, id, at)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if well == nil {
+		return nil, fmt.Errorf("%w: well %d has no revision at %s", ErrRevisionNotFound, id, at.Format(time.RFC3339))
+	}
+	return well, nil
+}
+
+// WellRevisions возвращает версии скважины, новые первыми
+func (s *WellService) WellRevisions(ctx context.Context, id int) ([]*entity.WellRevision, error) {
+	if _, err := s.GetWell(ctx, id); err != nil {
+		return nil, err
+	}
+	revisions, err := s.repo.Revisions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return revisions, nil
+}
+
+// WellRevision возвращает версию revision скважины
+func (s *WellService) WellRevision(ctx context.Context, id, revision int) (*entity.WellRevision, error) {
+	if _, err := s.GetWell(ctx, id); err != nil {
+		return nil, err
+	}
+	rev, err := s.repo.GetRevision(ctx, id, revision)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if rev == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return rev, nil
+}
+
+// CompareRevisions сравнивает версии from и to скважины. Расчетные поля
+// сравниваются так же, как в журнале изменений.
+func (s *WellService) CompareRevisions(ctx context.Context, id, from, to int) (*RevisionDiff, error) {
+	diff := &RevisionDiff{}
+	var err error
+	if diff.From, err = s.WellRevision(ctx, id, from); err != nil {
+		return nil, err
+	}
+	if diff.To, err = s.WellRevision(ctx, id, to); err != nil {
+		return nil, err
+	}
+	diff.Changes = diffFields(diff.From.Well, diff.To.Well, wellAuditSkip)
+	return diff, nil
+}
+
+// RestoreRevision возвращает скважине параметры версии revision.
+// Восстановление - обычное сохранение: расчетные параметры пересчитываются,
+// создается новая версия и запись в журнале изменений. Состояние скважины
+// не восстанавливается - оно меняется только переходом (ChangeStatus).
+func (s *WellService) RestoreRevision(ctx context.Context, id, revision int) (*entity.Well, error) {
+	rev, err := s.WellRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+	well := *rev.Well
+	well.ID, well.Status = id, ""
+	restored, err := s.UpdateWell(ctx, &well)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("well revision restored", "well_id", id, "revision", revision)
+	return restored, nil
+}
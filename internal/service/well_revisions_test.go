@@ -0,0 +1,74 @@
+// internal/service/well_revisions_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository/repotest"
+	"gas_wells/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWellRevisions(t *testing.T) {
+	ctx := context.Background()
+	repo := &repotest.WellRepo{Wells: map[int]*entity.Well{}}
+	s := service.NewWellService(repo, nil, logger.New("test"))
+
+	well, err := s.CreateWell(ctx, &entity.Well{
+		Name: "101", GammaG: 0.6, Temp: 360, TempUst: 290, Depth: 3000,
+		Pbuf: 10e6, Ppl: 20e6, Q: 150e3, Roughness: 2e-5, Diameter: 0.062,
+	})
+	require.NoError(t, err)
+	pz := well.Pz
+
+	// Ошибочная правка: буферное давление завышено
+	bad := *well
+	bad.Pbuf = 14e6
+	_, err = s.UpdateWell(ctx, &bad)
+	require.NoError(t, err)
+
+	revisions, err := s.WellRevisions(ctx, well.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Revision)
+	assert.Equal(t, 14e6, revisions[0].Well.Pbuf)
+
+	// Состояние на момент между версиями - первая версия
+	asOf, err := s.WellAsOf(ctx, well.ID, revisions[1].Created.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 10e6, asOf.Pbuf)
+	_, err = s.WellAsOf(ctx, well.ID, revisions[1].Created.Add(-time.Minute))
+	assert.ErrorIs(t, err, service.ErrRevisionNotFound)
+
+	diff, err := s.CompareRevisions(ctx, well.ID, 1, 2)
+	require.NoError(t, err)
+	changed := map[string]entity.FieldChange{}
+	for _, c := range diff.Changes {
+		changed[c.Field] = c
+	}
+	assert.Equal(t, entity.FieldChange{Field: "pbuf", Old: 10e6, New: 14e6}, changed["pbuf"])
+	assert.Contains(t, changed, "qmin", "расчетный Qmin меняется вместе с Pbuf")
+	assert.NotContains(t, changed, "name")
+
+	restored, err := s.RestoreRevision(ctx, well.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 10e6, restored.Pbuf)
+	assert.InDelta(t, pz, restored.Pz, 1)
+
+	// Восстановление - новая версия, прежние не меняются
+	revisions, err = s.WellRevisions(ctx, well.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, 10e6, revisions[0].Well.Pbuf)
+	assert.Equal(t, 14e6, revisions[1].Well.Pbuf)
+
+	_, err = s.RestoreRevision(ctx, well.ID, 7)
+	assert.ErrorIs(t, err, service.ErrRevisionNotFound)
+	_, err = s.WellRevisions(ctx, 42)
+	assert.ErrorIs(t, err, service.ErrWellNotFound)
+}
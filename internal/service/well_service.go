@@ -6,19 +6,38 @@ import (
 	"errors"
 	"fmt"
 	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
 	"gas_wells/internal/pkg/logger"
 	"gas_wells/internal/repository"
 	"math"
 )
 
+// ErrWellNotFound возвращается, если скважина с указанным ID не существует
+var ErrWellNotFound = errors.New("well not found")
+
+// Ошибки исходных данных: текст можно показать пользователю
+var (
+	// ErrInvalidWell - данные скважины не прошли проверку
+	ErrInvalidWell = errors.New("invalid well data")
+	// ErrCalculation - расчет по данным скважины или запроса невозможен
+	ErrCalculation = errors.New("calculation failed")
+)
+
+// Допустимое относительное расхождение введенного и расчетного Pz
+const pzMismatchTolerance = 0.05
+
 type WellService struct {
 	repo   repository.WellRepository
+	audit  *AuditService
 	logger logger.Logger
 }
 
-func NewWellService(repo repository.WellRepository, log logger.Logger) *WellService {
+// NewWellService создает сервис скважин. Изменения скважин записываются
+// в журнал audit; nil - без журнала (расчетные сервисы в тестах).
+func NewWellService(repo repository.WellRepository, audit *AuditService, log logger.Logger) *WellService {
 	return &WellService{
 		repo:   repo,
+		audit:  audit,
 		logger: log.With("layer", "service"),
 	}
 }
@@ -27,24 +46,29 @@ func NewWellService(repo repository.WellRepository, log logger.Logger) *WellServ
 func (s *WellService) CreateWell(ctx context.Context, well *entity.Well) (*entity.Well, error) {
 	// Валидация входных данных
 	if well.Name == "" {
-		return nil, errors.New("well name cannot be empty")
+		return nil, fmt.Errorf("%w: well name cannot be empty", ErrInvalidWell)
 	}
 	if well.Diameter <= 0 {
-		return nil, errors.New("pressure must be positive")
+		return nil, fmt.Errorf("%w: tubing diameter must be positive", ErrInvalidWell)
 	}
 	if well.Temp <= -273.15 {
-		return nil, errors.New("temperature cannot be below absolute zero")
+		return nil, fmt.Errorf("%w: temperature cannot be below absolute zero", ErrInvalidWell)
+	}
+	// Скважины обычно вносятся для расчетов режима, поэтому по умолчанию - в добыче
+	if well.Status == "" {
+		well.Status = entity.StatusProducing
+	}
+	if !entity.ValidWellStatus(well.Status) {
+		return nil, fmt.Errorf("%w: unknown well status %q", ErrInvalidWell, well.Status)
 	}
 
 	// Выполняем расчеты
-	result, err := s.calculateWellParameters(well.Pbuf, well.Temp)
-	if err != nil {
-		return nil, fmt.Errorf("calculation failed: %w", err)
+	if err := s.calculateWellParameters(well); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
 	}
-	well.Pmax = result
 
 	// Сохраняем в БД
-	if err := s.repo.Create(ctx, well); err != nil {
+	if err := s.repo.Create(ctx, well, s.auditEntry(entity.AuditCreate, nil, well)); err != nil {
 		return nil, fmt.Errorf("repository error: %w", err)
 	}
 
@@ -62,7 +86,7 @@ func (s *WellService) GetWell(ctx context.Context, id int) (*entity.Well, error)
 		return nil, fmt.Errorf("repository error: %w", err)
 	}
 	if well == nil {
-		return nil, errors.New("well not found")
+		return nil, ErrWellNotFound
 	}
 
 	return well, nil
@@ -76,25 +100,26 @@ func (s *WellService) UpdateWell(ctx context.Context, well *entity.Well) (*entit
 		return nil, fmt.Errorf("repository error: %w", err)
 	}
 	if existing == nil {
-		return nil, errors.New("well not found")
+		return nil, ErrWellNotFound
 	}
 
 	// Валидация
 	if well.Pbuf <= 0 {
-		return nil, errors.New("pressure must be positive")
+		return nil, fmt.Errorf("%w: pressure must be positive", ErrInvalidWell)
 	}
+	// Состояние меняется только переходом с причиной и датой (ChangeStatus)
+	if well.Status != "" && well.Status != existing.Status {
+		return nil, fmt.Errorf("%w: status can only be changed by a transition with a reason and date", ErrInvalidTransition)
+	}
+	well.Status, well.StatusSince = existing.Status, existing.StatusSince
 
-	// Пересчитываем параметры при изменении давления/температуры
-	if well.Pbuf != existing.Pbuf || well.Temp != existing.Temp {
-		result, err := s.calculateWellParameters(well.Pbuf, well.Temp)
-		if err != nil {
-			return nil, fmt.Errorf("calculation failed: %w", err)
-		}
-		well.Pmax = result
+	// Пересчитываем расчетные параметры
+	if err := s.calculateWellParameters(well); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCalculation, err)
 	}
 
 	// Обновляем в БД
-	if err := s.repo.Update(ctx, well); err != nil {
+	if err := s.repo.Update(ctx, well, s.auditEntry(entity.AuditUpdate, existing, well)); err != nil {
 		return nil, fmt.Errorf("repository error: %w", err)
 	}
 
@@ -108,34 +133,171 @@ func (s *WellService) DeleteWell(ctx context.Context, id int) error {
 	}
 
 	// Проверяем существование
-	if _, err := s.repo.GetByID(ctx, id); err != nil {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	if existing == nil {
+		return ErrWellNotFound
+	}
+
+	if err := s.repo.Delete(ctx, id, s.auditEntry(entity.AuditDelete, existing, nil)); err != nil {
 		return fmt.Errorf("repository error: %w", err)
 	}
+	return nil
+}
+
+// auditEntry - запись журнала аудита об изменении скважины. Репозиторий
+// сохраняет ее в транзакции изменения, поэтому изменение без записи
+// в журнале невозможно. nil - журнал не ведется или поля не изменились.
+func (s *WellService) auditEntry(action string, before, after *entity.Well) *entity.AuditEntry {
+	if s.audit == nil {
+		return nil
+	}
+	changes := diffFields(before, after, wellAuditSkip)
+	if action == entity.AuditUpdate && len(changes) == 0 {
+		return nil
+	}
 
-	return s.repo.Delete(ctx, id)
+	well := after
+	if well == nil {
+		well = before
+	}
+	return &entity.AuditEntry{
+		EntityType: entity.AuditWell,
+		EntityID:   well.ID,
+		EntityName: well.Name,
+		Action:     action,
+		Changes:    changes,
+	}
 }
 
-// ListWells возвращает список скважин с пагинацией
-func (s *WellService) ListWells(ctx context.Context, limit int, offset int) ([]*entity.Well, error) {
-	wells, err := s.repo.List(ctx, limit, offset)
+// ListWells возвращает страницу списка скважин по условиям запроса
+func (s *WellService) ListWells(ctx context.Context, q entity.WellQuery) (*entity.WellPage, error) {
+	if q.Status != "" && !entity.ValidWellStatus(q.Status) {
+		return nil, fmt.Errorf("unknown well status %q", q.Status)
+	}
+	if q.Sort != "" && !entity.ValidWellSortKey(q.Sort) {
+		return nil, fmt.Errorf("unknown sort key %q", q.Sort)
+	}
+
+	page, err := s.repo.List(ctx, q)
 	if err != nil {
 		return nil, fmt.Errorf("repository error: %w", err)
 	}
+	if page.Wells == nil {
+		page.Wells = []*entity.Well{}
+	}
+	s.fillHydrateRisk(page.Wells)
 
-	return wells, nil
+	return page, nil
 }
 
 // calculateWellParameters содержит бизнес-логику расчетов
-func (s *WellService) calculateWellParameters(pressure, temperature float64) (float64, error) {
-	// Пример сложной бизнес-логики
-	if pressure > 1000 {
-		return 0, errors.New("pressure exceeds maximum allowed value")
+func (s *WellService) calculateWellParameters(well *entity.Well) error {
+	s.fillViscosity(well)
+	if err := s.fillBottomholePressure(well); err != nil {
+		return err
+	}
+	return s.fillCriticalRate(well)
+}
+
+// fillBottomholePressure рассчитывает забойное давление. Если Pz не задано
+// по замеру (PzManual), оно заполняется расчетным значением; замеренное Pz,
+// расходящееся с расчетом больше чем на pzMismatchTolerance, дает предупреждение.
+func (s *WellService) fillBottomholePressure(well *entity.Well) error {
+	// Недостаточно данных для расчета - оставляем Pz как есть
+	if well.Depth <= 0 || well.GammaG <= 0 || well.Temp <= 0 || well.TempUst <= 0 {
+		return nil
+	}
+
+	res, err := s.BottomholePressure(well)
+	if err != nil {
+		// Ошибка расчета не блокирует сохранение введенных данных
+		s.logger.Warn("bottomhole pressure calculation failed", "well", well.Name, "error", err)
+		well.Warnings = append(well.Warnings, "Не удалось рассчитать забойное давление: "+err.Error())
+		return nil
+	}
+	if res == nil {
+		return nil
+	}
+
+	calculated := math.Round(res.Pressure)
+	if !well.PzManual || well.Pz <= 0 {
+		well.Pz = calculated
+		well.PzManual = false
+		return nil
+	}
+
+	if diff := math.Abs(well.Pz-calculated) / calculated; diff > pzMismatchTolerance {
+		s.logger.Warn("bottomhole pressure mismatch",
+			"well", well.Name, "entered", well.Pz, "calculated", calculated)
+		well.Warnings = append(well.Warnings, fmt.Sprintf(
+			"Введенное забойное давление %.0f Па отличается от расчетного %.0f Па на %.1f%%",
+			well.Pz, calculated, diff*100))
+	}
+	return nil
+}
+
+// BottomholePressure рассчитывает забойное давление скважины: при наличии
+// дебита - по буферному давлению через НКТ, иначе - статическое по
+// затрубному (или буферному) давлению. Возвращает nil, если давление на устье не задано.
+func (s *WellService) BottomholePressure(well *entity.Well) (*calculations.TraverseResult, error) {
+	wb := calculations.WellboreFromWell(*well)
+	opts := calculations.TraverseOptions{}
+
+	var (
+		res calculations.TraverseResult
+		err error
+	)
+	switch {
+	case well.Q > 0 && well.Pbuf > 0:
+		res, err = calculations.FlowingBottomholePressure(wb, well.Pbuf, well.Q, opts)
+	case well.Ptb > 0:
+		res, err = calculations.StaticBottomholePressure(wb, well.Ptb, opts)
+	case well.Pbuf > 0:
+		res, err = calculations.StaticBottomholePressure(wb, well.Pbuf, opts)
+	default:
+		return nil, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
 
-	// Формула расчета (пример)
-	const efficiencyFactor = 0.85
-	result := pressure * temperature * efficiencyFactor
+// fillCriticalRate рассчитывает критический дебит выноса жидкости Qmin
+// по условиям на устье выбранной капельной моделью и выставляет признак
+// риска самозадавливания, если текущий дебит ниже критического
+func (s *WellService) fillCriticalRate(well *entity.Well) error {
+	model, err := calculations.ParseUnloadingModel(well.QminModel)
+	if err != nil {
+		return err
+	}
+	well.QminModel = string(model)
+
+	// Недостаточно данных - остается введенное вручную значение Qmin
+	if well.Pbuf > 0 && well.TempUst > 0 && well.Diameter > 0 && well.GammaG > 0 {
+		res, err := calculations.CriticalUnloadingRate(calculations.UnloadingInput{
+			Pressure:      well.Pbuf,
+			Temp:          well.TempUst,
+			Diameter:      well.Diameter,
+			GammaG:        well.GammaG,
+			LiquidDensity: well.Rog,
+		}, model)
+		if err != nil {
+			s.logger.Warn("critical unloading rate calculation failed", "well", well.Name, "error", err)
+			well.Warnings = append(well.Warnings, "Не удалось рассчитать критический дебит: "+err.Error())
+		} else {
+			well.Qmin = math.Round(res.Rate)
+		}
+	}
 
-	// Округляем до 2 знаков после запятой
-	return math.Round(result*100) / 100, nil
+	well.LiquidLoading = well.Q > 0 && well.Qmin > 0 && well.Q < well.Qmin
+	if well.LiquidLoading {
+		well.Warnings = append(well.Warnings, fmt.Sprintf(
+			"Риск самозадавливания: дебит %.0f м³/сут ниже критического %.0f м³/сут (модель %s)",
+			well.Q, well.Qmin, well.QminModel))
+	}
+	return nil
 }
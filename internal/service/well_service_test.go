@@ -0,0 +1,65 @@
+// internal/service/well_service_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository/repotest"
+	"gas_wells/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBottomholePressureRecalculation(t *testing.T) {
+	ctx := context.Background()
+	repo := &repotest.WellRepo{Wells: map[int]*entity.Well{}}
+	s := service.NewWellService(repo, nil, logger.New("test"))
+
+	well, err := s.CreateWell(ctx, &entity.Well{
+		Name: "101", GammaG: 0.6, Temp: 360, TempUst: 290, Depth: 3000,
+		Pbuf: 10e6, Ppl: 20e6, Q: 150e3, Roughness: 2e-5, Diameter: 0.062,
+	})
+	require.NoError(t, err)
+	assert.False(t, well.PzManual)
+	assert.Greater(t, well.Pz, well.Pbuf)
+	assert.Empty(t, well.Warnings)
+	calculated := well.Pz
+
+	// Расчетное Pz пересчитывается при изменении устьевых условий
+	// и не сравнивается со своим прежним значением
+	changed := *well
+	changed.Pbuf = 12e6
+	changed.Warnings = nil
+	updated, err := s.UpdateWell(ctx, &changed)
+	require.NoError(t, err)
+	assert.Greater(t, updated.Pz, calculated)
+	assert.False(t, updated.PzManual)
+	assert.Empty(t, updated.Warnings)
+
+	// Замеренное Pz сохраняется, расхождение с расчетом - предупреждение
+	measured := *updated
+	measured.Pz, measured.PzManual = calculated, true
+	measured.Warnings = nil
+	updated, err = s.UpdateWell(ctx, &measured)
+	require.NoError(t, err)
+	assert.Equal(t, calculated, updated.Pz)
+	assert.True(t, updated.PzManual)
+	assert.Len(t, updated.Warnings, 1)
+}
+
+func TestWellGasPropertiesErrors(t *testing.T) {
+	s := newTestService(t)
+
+	_, err := s.WellGasProperties(&entity.Well{Pbuf: 10e6, TempUst: 290})
+	assert.ErrorIs(t, err, service.ErrInvalidWell)
+	_, err = s.WellGasProperties(&entity.Well{GammaG: 0.6})
+	assert.ErrorIs(t, err, service.ErrInvalidWell)
+
+	props, err := s.WellGasProperties(&entity.Well{GammaG: 0.6, Pbuf: 10e6, TempUst: 290})
+	require.NoError(t, err)
+	assert.NotNil(t, props.Wellhead)
+	assert.Nil(t, props.Bottomhole)
+}
@@ -0,0 +1,155 @@
+// internal/service/welltest_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/calculations"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/repository"
+)
+
+var (
+	// ErrWellTestNotFound возвращается, если исследование не существует
+	ErrWellTestNotFound = errors.New("well test not found")
+	// ErrInvalidWellTest - данные исследования не прошли проверку
+	ErrInvalidWellTest = errors.New("invalid well test")
+)
+
+type WellTestService struct {
+	repo   repository.WellTestRepository
+	wells  *WellService
+	logger logger.Logger
+}
+
+func NewWellTestService(repo repository.WellTestRepository, wells *WellService, log logger.Logger) *WellTestService {
+	return &WellTestService{
+		repo:   repo,
+		wells:  wells,
+		logger: log.With("layer", "service"),
+	}
+}
+
+// WellTestResult - исследование вместе с результатом обработки
+type WellTestResult struct {
+	*entity.WellTest
+	Fit      *calculations.InflowFit `json:"fit,omitempty"`
+	FitError string                  `json:"fit_error,omitempty"`
+}
+
+// CreateTest сохраняет исследование скважины
+func (s *WellTestService) CreateTest(ctx context.Context, test *entity.WellTest) (*entity.WellTest, error) {
+	if _, err := s.wells.GetWell(ctx, test.WellID); err != nil {
+		return nil, err
+	}
+	if test.Type != entity.WellTestBackPressure && test.Type != entity.WellTestIsochronal {
+		return nil, fmt.Errorf("%w: unknown well test type %q", ErrInvalidWellTest, test.Type)
+	}
+	if test.TestDate.IsZero() {
+		return nil, fmt.Errorf("%w: test date is required", ErrInvalidWellTest)
+	}
+	if len(test.Points) < 2 {
+		return nil, fmt.Errorf("%w: at least two test points are required", ErrInvalidWellTest)
+	}
+	for _, p := range test.Points {
+		if p.Q <= 0 || p.Pz <= 0 || p.Ppl <= p.Pz {
+			return nil, fmt.Errorf("%w: each point requires Q > 0 and 0 < Pz < Ppl", ErrInvalidWellTest)
+		}
+	}
+
+	if err := s.repo.Create(ctx, test); err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	return test, nil
+}
+
+// GetTest возвращает исследование с результатом обработки
+func (s *WellTestService) GetTest(ctx context.Context, id int) (*WellTestResult, error) {
+	test, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if test == nil {
+		return nil, ErrWellTestNotFound
+	}
+	return s.withFit(test), nil
+}
+
+// ListTests возвращает исследования скважины с результатами обработки
+func (s *WellTestService) ListTests(ctx context.Context, wellID int) ([]*WellTestResult, error) {
+	if _, err := s.wells.GetWell(ctx, wellID); err != nil {
+		return nil, err
+	}
+	tests, err := s.repo.ListByWell(ctx, wellID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+
+	results := make([]*WellTestResult, 0, len(tests))
+	for _, t := range tests {
+		results = append(results, s.withFit(t))
+	}
+	return results, nil
+}
+
+// DeleteTest удаляет исследование скважины wellID
+func (s *WellTestService) DeleteTest(ctx context.Context, wellID, id int) error {
+	test, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	if test == nil || test.WellID != wellID {
+		return ErrWellTestNotFound
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+	return nil
+}
+
+// ApplyFit записывает коэффициенты A и B, найденные по исследованию,
+// в карточку скважины
+func (s *WellTestService) ApplyFit(ctx context.Context, wellID, testID int) (*entity.Well, error) {
+	res, err := s.GetTest(ctx, testID)
+	if err != nil {
+		return nil, err
+	}
+	if res.WellID != wellID {
+		return nil, ErrWellTestNotFound
+	}
+	if res.Fit == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCalculation, res.FitError)
+	}
+	if !res.Fit.Valid {
+		return nil, fmt.Errorf("%w: fitted coefficients are not physical (A must be positive, B non-negative)", ErrInvalidWellTest)
+	}
+
+	well, err := s.wells.GetWell(ctx, res.WellID)
+	if err != nil {
+		return nil, err
+	}
+	well.A = res.Fit.A
+	well.B = res.Fit.B
+
+	s.logger.Info("applying inflow coefficients from well test",
+		"well_id", well.ID, "test_id", testID, "a", well.A, "b", well.B, "r2", res.Fit.R2)
+	return s.wells.UpdateWell(ctx, well)
+}
+
+func (s *WellTestService) withFit(test *entity.WellTest) *WellTestResult {
+	points := make([]calculations.TestPoint, len(test.Points))
+	for i, p := range test.Points {
+		points[i] = calculations.TestPoint{Q: p.Q, Pz: p.Pz, Ppl: p.Ppl}
+	}
+
+	res := &WellTestResult{WellTest: test}
+	fit, err := calculations.FitInflow(points)
+	if err != nil {
+		res.FitError = err.Error()
+		return res
+	}
+	res.Fit = &fit
+	return res
+}
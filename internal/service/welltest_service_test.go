@@ -0,0 +1,95 @@
+// internal/service/welltest_service_test.go
+package service_test
+
+import (
+	"context"
+	"gas_wells/internal/entity"
+	"gas_wells/internal/pkg/logger"
+	"gas_wells/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryWellTestRepo - исследования скважин в памяти
+type memoryWellTestRepo struct {
+	tests map[int]*entity.WellTest
+}
+
+func (r *memoryWellTestRepo) Create(_ context.Context, test *entity.WellTest) error {
+	test.ID = len(r.tests) + 1
+	r.tests[test.ID] = test
+	return nil
+}
+
+func (r *memoryWellTestRepo) GetByID(_ context.Context, id int) (*entity.WellTest, error) {
+	return r.tests[id], nil
+}
+
+func (r *memoryWellTestRepo) ListByWell(_ context.Context, wellID int) ([]*entity.WellTest, error) {
+	var res []*entity.WellTest
+	for _, t := range r.tests {
+		if t.WellID == wellID {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+func (r *memoryWellTestRepo) Delete(_ context.Context, id int) error {
+	delete(r.tests, id)
+	return nil
+}
+
+func TestDeleteWellTest(t *testing.T) {
+	ctx := context.Background()
+	tests := &memoryWellTestRepo{tests: map[int]*entity.WellTest{
+		1: {ID: 1, WellID: 10},
+	}}
+	s := service.NewWellTestService(tests, nil, logger.New("test"))
+
+	// Исследование другой скважины не удаляется
+	assert.ErrorIs(t, s.DeleteTest(ctx, 20, 1), service.ErrWellTestNotFound)
+	assert.Contains(t, tests.tests, 1)
+	assert.ErrorIs(t, s.DeleteTest(ctx, 10, 2), service.ErrWellTestNotFound)
+
+	require.NoError(t, s.DeleteTest(ctx, 10, 1))
+	assert.NotContains(t, tests.tests, 1)
+	assert.ErrorIs(t, s.DeleteTest(ctx, 10, 1), service.ErrWellTestNotFound)
+}
+
+func TestCreateWellTestValidation(t *testing.T) {
+	ctx := context.Background()
+	tests := &memoryWellTestRepo{tests: map[int]*entity.WellTest{}}
+	s := service.NewWellTestService(tests, newTestService(t), logger.New("test"))
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	points := []entity.WellTestPoint{{Q: 100e3, Pz: 18e6, Ppl: 20e6}, {Q: 200e3, Pz: 15e6, Ppl: 20e6}}
+
+	invalid := []*entity.WellTest{
+		{WellID: 1, Type: "flow-after-flow", TestDate: date, Points: points},
+		{WellID: 1, Type: entity.WellTestBackPressure, Points: points},
+		{WellID: 1, Type: entity.WellTestBackPressure, TestDate: date, Points: points[:1]},
+		{WellID: 1, Type: entity.WellTestBackPressure, TestDate: date,
+			Points: []entity.WellTestPoint{{Q: 100e3, Pz: 21e6, Ppl: 20e6}, points[1]}},
+	}
+	for _, test := range invalid {
+		_, err := s.CreateTest(ctx, test)
+		assert.ErrorIs(t, err, service.ErrInvalidWellTest)
+	}
+	assert.Empty(t, tests.tests)
+
+	_, err := s.CreateTest(ctx, &entity.WellTest{WellID: 2, Type: entity.WellTestBackPressure, TestDate: date, Points: points})
+	assert.ErrorIs(t, err, service.ErrWellNotFound)
+
+	_, err = s.CreateTest(ctx, &entity.WellTest{WellID: 1, Type: entity.WellTestBackPressure, TestDate: date, Points: points})
+	require.NoError(t, err)
+	list, err := s.ListTests(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	// Неизвестная скважина - ошибка, а не пустой список
+	_, err = s.ListTests(ctx, 2)
+	assert.ErrorIs(t, err, service.ErrWellNotFound)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"gas_wells/internal/pkg/logger"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
@@ -22,6 +25,396 @@ func NewMigrator(db *pgxpool.Pool, logger logger.Logger) *Migrator {
 	return &Migrator{db: db, logger: logger}
 }
 
+// migration - одна миграция схемы; name задает порядок применения
+type migration struct {
+	name string
+	up   string
+}
+
+var migrations = []migration{
+	{
+		name: "000001_init",
+		up: `-- +migrate Up
+CREATE TABLE IF NOT EXISTS wells (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	pressure FLOAT NOT NULL,
+	temperature FLOAT NOT NULL,
+	result FLOAT NOT NULL,
+	created_at TIMESTAMP DEFAULT NOW(),
+	updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS wells_name_idx ON wells(name);
+`,
+	},
+	{
+		name: "000002_well_params",
+		up: `-- +migrate Up
+-- Параметры скважины вместо столбцов pressure, temperature, result базовой схемы.
+-- Давление переносится в буферное давление, температура - в температуру
+-- на устье; result был расчетным значением и не переносится.
+ALTER TABLE wells
+	ADD COLUMN IF NOT EXISTS location TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS gammag DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS temp DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS tempust DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS depth DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS pbuf DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS ptb DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS ppl DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS pz DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS q DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS roughness DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS diametr DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS a DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS b DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS mu DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS wgf DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS rog DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS hw DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS qmin DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS pmax DOUBLE PRECISION NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT '';
+
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_name = 'wells' AND column_name = 'pressure') THEN
+		UPDATE wells SET pbuf = pressure, tempust = temperature;
+	END IF;
+END $$;
+
+ALTER TABLE wells
+	DROP COLUMN IF EXISTS pressure,
+	DROP COLUMN IF EXISTS temperature,
+	DROP COLUMN IF EXISTS result;
+`,
+	},
+	{
+		name: "000003_well_tests",
+		up: `-- +migrate Up
+CREATE TABLE IF NOT EXISTS well_tests (
+	id SERIAL PRIMARY KEY,
+	well_id INTEGER NOT NULL REFERENCES wells(id) ON DELETE CASCADE,
+	test_date DATE NOT NULL,
+	test_type TEXT NOT NULL,
+	created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS well_tests_well_id_idx ON well_tests(well_id);
+
+CREATE TABLE IF NOT EXISTS well_test_points (
+	id SERIAL PRIMARY KEY,
+	test_id INTEGER NOT NULL REFERENCES well_tests(id) ON DELETE CASCADE,
+	seq INTEGER NOT NULL,
+	q DOUBLE PRECISION NOT NULL,
+	pz DOUBLE PRECISION NOT NULL,
+	ppl DOUBLE PRECISION NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS well_test_points_test_id_idx ON well_test_points(test_id);
+`,
+	},
+	{
+		name: "000004_liquid_unloading",
+		up: `-- +migrate Up
+ALTER TABLE wells ADD COLUMN IF NOT EXISTS qmin_model TEXT NOT NULL DEFAULT '';
+ALTER TABLE wells ADD COLUMN IF NOT EXISTS liquid_loading BOOLEAN NOT NULL DEFAULT FALSE;
+`,
+	},
+	{
+		name: "000005_mu_manual",
+		up: `-- +migrate Up
+ALTER TABLE wells ADD COLUMN IF NOT EXISTS mu_manual BOOLEAN NOT NULL DEFAULT FALSE;
+`,
+	},
+	{
+		name: "000006_measurements",
+		up: `-- +migrate Up
+CREATE TABLE IF NOT EXISTS well_measurements (
+	id BIGSERIAL PRIMARY KEY,
+	well_id INTEGER NOT NULL REFERENCES wells(id) ON DELETE CASCADE,
+	measured_at TIMESTAMPTZ NOT NULL,
+	pbuf DOUBLE PRECISION,
+	ptb DOUBLE PRECISION,
+	q DOUBLE PRECISION,
+	tempust DOUBLE PRECISION,
+	created_at TIMESTAMP DEFAULT NOW(),
+	UNIQUE (well_id, measured_at)
+);
+`,
+	},
+	{
+		name: "000007_production_history",
+		up: `-- +migrate Up
+CREATE TABLE IF NOT EXISTS well_production (
+	id SERIAL PRIMARY KEY,
+	well_id INTEGER NOT NULL REFERENCES wells(id) ON DELETE CASCADE,
+	date DATE NOT NULL,
+	q DOUBLE PRECISION NOT NULL,
+	created_at TIMESTAMP DEFAULT NOW(),
+	UNIQUE (well_id, date)
+);
+`,
+	},
+	{
+		name: "000008_pressure_surveys",
+		up: `-- +migrate Up
+CREATE TABLE IF NOT EXISTS pressure_surveys (
+	id SERIAL PRIMARY KEY,
+	well_id INTEGER NOT NULL REFERENCES wells(id) ON DELETE CASCADE,
+	survey_date DATE NOT NULL,
+	ppl DOUBLE PRECISION NOT NULL,
+	gp DOUBLE PRECISION NOT NULL,
+	created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS pressure_surveys_well_id_idx ON pressure_surveys(well_id);
+`,
+	},
+	{
+		name: "000009_fields_pads",
+		up: `-- +migrate Up
+CREATE TABLE IF NOT EXISTS fields (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE,
+	description TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS pads (
+	id SERIAL PRIMARY KEY,
+	field_id INTEGER NOT NULL REFERENCES fields(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	created_at TIMESTAMP DEFAULT NOW(),
+	UNIQUE (field_id, name)
+);
+
+ALTER TABLE wells ADD COLUMN IF NOT EXISTS pad_id INTEGER REFERENCES pads(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS wells_pad_id_idx ON wells(pad_id);
+`,
+	},
+	{
+		name: "000010_gathering_network",
+		up: `-- +migrate Up
+CREATE TABLE IF NOT EXISTS network_nodes (
+	id SERIAL PRIMARY KEY,
+	pad_id INTEGER NOT NULL REFERENCES pads(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	well_id INTEGER UNIQUE REFERENCES wells(id) ON DELETE CASCADE,
+	created_at TIMESTAMP DEFAULT NOW(),
+	UNIQUE (pad_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS pipe_segments (
+	id SERIAL PRIMARY KEY,
+	pad_id INTEGER NOT NULL REFERENCES pads(id) ON DELETE CASCADE,
+	from_node_id INTEGER NOT NULL UNIQUE REFERENCES network_nodes(id) ON DELETE CASCADE,
+	to_node_id INTEGER NOT NULL REFERENCES network_nodes(id) ON DELETE CASCADE,
+	length DOUBLE PRECISION NOT NULL,
+	diameter DOUBLE PRECISION NOT NULL,
+	roughness DOUBLE PRECISION NOT NULL DEFAULT 0,
+	elevation DOUBLE PRECISION NOT NULL DEFAULT 0,
+	created_at TIMESTAMP DEFAULT NOW()
+);
+`,
+	},
+	{
+		name: "000011_choke",
+		up: `-- +migrate Up
+ALTER TABLE wells ADD COLUMN IF NOT EXISTS choke DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE wells ADD COLUMN IF NOT EXISTS pline DOUBLE PRECISION NOT NULL DEFAULT 0;
+`,
+	},
+	{
+		name: "000012_users",
+		up: `-- +migrate Up
+CREATE TABLE IF NOT EXISTS users (
+	id SERIAL PRIMARY KEY,
+	email TEXT NOT NULL UNIQUE,
+	username TEXT NOT NULL,
+	password_hash TEXT NOT NULL,
+	role TEXT NOT NULL DEFAULT 'user',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+	id TEXT PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	expires_at TIMESTAMPTZ NOT NULL,
+	revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(user_id);
+`,
+	},
+	{
+		// Роли viewer, engineer, admin: прежние пользователи с ролью user
+		// могли изменять данные и становятся инженерами
+		name: "000013_user_roles",
+		up: `-- +migrate Up
+UPDATE users SET role = 'engineer' WHERE role = 'user';
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'viewer';
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('viewer', 'engineer', 'admin'));
+`,
+	},
+	{
+		// Журнал аудита не ссылается на скважину: записи сохраняются после ее удаления
+		name: "000014_audit_log",
+		up: `-- +migrate Up
+CREATE TABLE IF NOT EXISTS audit_log (
+	id BIGSERIAL PRIMARY KEY,
+	entity_type TEXT NOT NULL,
+	entity_id INTEGER NOT NULL,
+	entity_name TEXT NOT NULL DEFAULT '',
+	action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+	actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	actor TEXT NOT NULL DEFAULT '',
+	changes JSONB NOT NULL DEFAULT '[]',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log(created_at);
+`,
+	},
+	{
+		name: "000015_well_revisions",
+		up: `-- +migrate Up
+CREATE TABLE IF NOT EXISTS well_revisions (
+	well_id INTEGER NOT NULL REFERENCES wells(id) ON DELETE CASCADE,
+	revision INTEGER NOT NULL,
+	data JSONB NOT NULL,
+	actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	actor TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (well_id, revision)
+);
+
+-- Версии не изменяются; удаляются только вместе со скважиной
+CREATE OR REPLACE FUNCTION well_revisions_immutable() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'well revisions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS well_revisions_no_update ON well_revisions;
+CREATE TRIGGER well_revisions_no_update BEFORE UPDATE ON well_revisions
+	FOR EACH ROW EXECUTE FUNCTION well_revisions_immutable();
+
+-- Текущее состояние существующих скважин - первая версия,
+-- действующая с последнего изменения
+INSERT INTO well_revisions (well_id, revision, data, created_at)
+SELECT id, 1, jsonb_build_object(
+		'id', id, 'name', name, 'location', location, 'gamma', gammag, 'temp', temp,
+		'tempust', tempust, 'depth', depth, 'pbuf', pbuf, 'ptb', ptb, 'ppl', ppl, 'pz', pz,
+		'q', q, 'roughness', roughness, 'diameter', diametr, 'a', a, 'b', b, 'mu', mu,
+		'wgf', wgf, 'rog', rog, 'hw', hw, 'qmin', qmin, 'pmax', pmax, 'status', status,
+		'created', created_at::timestamptz, 'updated', updated_at::timestamptz,
+		'qmin_model', qmin_model, 'liquid_loading', liquid_loading, 'mu_manual', mu_manual,
+		'pad_id', pad_id, 'choke', choke, 'pline', pline),
+	COALESCE(updated_at, created_at, now())
+FROM wells
+ON CONFLICT DO NOTHING;
+`,
+	},
+	{
+		name: "000016_well_lifecycle",
+		up: `-- +migrate Up
+CREATE TABLE IF NOT EXISTS well_status_history (
+	id SERIAL PRIMARY KEY,
+	well_id INTEGER NOT NULL REFERENCES wells(id) ON DELETE CASCADE,
+	from_status TEXT NOT NULL DEFAULT '',
+	to_status TEXT NOT NULL,
+	reason TEXT NOT NULL DEFAULT '',
+	changed_on DATE NOT NULL,
+	actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	actor TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_well_status_history_well ON well_status_history(well_id, changed_on, id);
+
+-- Прежний статус был произвольным текстом. Известные значения переводятся
+-- в состояния жизненного цикла (без учета регистра и пробелов по краям):
+--   work, working, active, producing, работает, в работе, в добыче -> producing
+--   idle, stopped, stop, shut-in, простой, остановлена, в простое  -> shut-in
+--   repair, workover, ремонт, в ремонте, крс                        -> workover
+--   conservation, suspended, консервация, в консервации             -> suspended
+--   abandoned, ликвидирована                                        -> abandoned
+--   drilling, бурение                                               -> drilling
+--   completed, освоение                                             -> completed
+-- Остальные и пустые статусы переводятся в shut-in: добыча по скважине
+-- не подтверждена. Прежнее значение, если оно не совпадает с новым
+-- состоянием, сохраняется в причине начальной записи истории.
+CREATE TEMPORARY TABLE legacy_well_status (legacy TEXT PRIMARY KEY, status TEXT NOT NULL);
+INSERT INTO legacy_well_status (legacy, status) VALUES
+	('work', 'producing'), ('working', 'producing'), ('active', 'producing'), ('producing', 'producing'),
+	('работает', 'producing'), ('в работе', 'producing'), ('в добыче', 'producing'),
+	('idle', 'shut-in'), ('stopped', 'shut-in'), ('stop', 'shut-in'), ('shut-in', 'shut-in'),
+	('простой', 'shut-in'), ('остановлена', 'shut-in'), ('в простое', 'shut-in'),
+	('repair', 'workover'), ('workover', 'workover'), ('ремонт', 'workover'), ('в ремонте', 'workover'), ('крс', 'workover'),
+	('conservation', 'suspended'), ('suspended', 'suspended'), ('консервация', 'suspended'), ('в консервации', 'suspended'),
+	('abandoned', 'abandoned'), ('ликвидирована', 'abandoned'),
+	('drilling', 'drilling'), ('бурение', 'drilling'),
+	('completed', 'completed'), ('освоение', 'completed');
+
+CREATE TEMPORARY TABLE well_status_migration AS
+SELECT w.id, w.status AS legacy, COALESCE(l.status, 'shut-in') AS status, COALESCE(w.created_at, now())::date AS changed_on
+FROM wells w
+LEFT JOIN legacy_well_status l ON l.legacy = lower(btrim(COALESCE(w.status, '')));
+
+INSERT INTO well_status_history (well_id, to_status, reason, changed_on)
+SELECT m.id, m.status,
+	CASE WHEN m.legacy IS DISTINCT FROM m.status
+		THEN 'статус до перехода на жизненный цикл: ' || COALESCE(NULLIF(m.legacy, ''), 'не задан') ELSE '' END,
+	m.changed_on
+FROM well_status_migration m
+WHERE NOT EXISTS (SELECT 1 FROM well_status_history h WHERE h.well_id = m.id);
+
+UPDATE wells SET status = m.status
+FROM well_status_migration m
+WHERE wells.id = m.id AND wells.status IS DISTINCT FROM m.status;
+
+DROP TABLE well_status_migration;
+DROP TABLE legacy_well_status;
+
+ALTER TABLE wells ALTER COLUMN status SET DEFAULT 'producing';
+ALTER TABLE wells DROP CONSTRAINT IF EXISTS wells_status_check;
+ALTER TABLE wells ADD CONSTRAINT wells_status_check
+	CHECK (status IN ('drilling', 'completed', 'producing', 'shut-in', 'workover', 'suspended', 'abandoned'));
+`,
+	},
+	{
+		name: "000017_well_list_indexes",
+		up: `-- +migrate Up
+-- Постраничная выборка списка скважин по ключу (значение сортировки, id)
+CREATE INDEX IF NOT EXISTS idx_wells_name_id ON wells(name, id);
+CREATE INDEX IF NOT EXISTS idx_wells_q_id ON wells(q, id);
+CREATE INDEX IF NOT EXISTS idx_wells_pbuf_id ON wells(pbuf, id);
+CREATE INDEX IF NOT EXISTS idx_wells_depth_id ON wells(depth, id);
+CREATE INDEX IF NOT EXISTS idx_wells_status ON wells(status);
+`,
+	},
+	{
+		name: "000018_pz_manual",
+		up: `-- +migrate Up
+ALTER TABLE wells ADD COLUMN IF NOT EXISTS pz_manual BOOLEAN NOT NULL DEFAULT FALSE;
+-- Существующие Pz считаются расчетными и пересчитываются при следующем
+-- сохранении скважины; замер отмечается флагом при вводе
+`,
+	},
+	{
+		name: "000019_refresh_token_rotation",
+		up: `-- +migrate Up
+-- Время обмена токена на новую пару; отличает обмен от выхода
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+`,
+	},
+}
+
 func (m *Migrator) Run(ctx context.Context) error {
 	// Создаем временную директорию для миграций
 	tmpDir, err := os.MkdirTemp("", "migrations")
@@ -30,19 +423,19 @@ func (m *Migrator) Run(ctx context.Context) error {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	// Записываем базовые миграции
-	if err := m.createInitialMigration(tmpDir); err != nil {
+	// Записываем миграции
+	if err := m.writeMigrations(tmpDir); err != nil {
 		return err
 	}
 
 	// Применяем миграции
-	connString := m.db.Config().ConnString()
 	mig, err := migrate.New(
 		"file://"+tmpDir,
-		connString)
+		m.databaseURL())
 	if err != nil {
 		return fmt.Errorf("failed to init migrator: %w", err)
 	}
+	defer mig.Close()
 
 	if err := mig.Up(); err != nil && err != migrate.ErrNoChange {
 		return fmt.Errorf("failed to apply migrations: %w", err)
@@ -52,26 +445,30 @@ func (m *Migrator) Run(ctx context.Context) error {
 	return nil
 }
 
-func (m *Migrator) createInitialMigration(dir string) error {
-	// Создаем файл начальной миграции
-	filePath := filepath.Join(dir, "000001_init.up.sql")
-	content := `-- +migrate Up
-CREATE TABLE IF NOT EXISTS wells (
-	id SERIAL PRIMARY KEY,
-	name TEXT NOT NULL,
-	pressure FLOAT NOT NULL,
-	temperature FLOAT NOT NULL,
-	result FLOAT NOT NULL,
-	created_at TIMESTAMP DEFAULT NOW(),
-	updated_at TIMESTAMP DEFAULT NOW()
-);
-
-CREATE INDEX IF NOT EXISTS wells_name_idx ON wells(name);
-`
-
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to create migration file: %w", err)
+// databaseURL строит URL подключения для golang-migrate, который
+// не принимает DSN в формате "host=... port=..."
+func (m *Migrator) databaseURL() string {
+	cc := m.db.Config().ConnConfig
+	sslMode := "disable"
+	if cc.TLSConfig != nil {
+		sslMode = "require"
+	}
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cc.User, cc.Password),
+		Host:     net.JoinHostPort(cc.Host, strconv.Itoa(int(cc.Port))),
+		Path:     "/" + cc.Database,
+		RawQuery: "sslmode=" + sslMode,
 	}
+	return u.String()
+}
 
+func (m *Migrator) writeMigrations(dir string) error {
+	for _, mg := range migrations {
+		filePath := filepath.Join(dir, mg.name+".up.sql")
+		if err := os.WriteFile(filePath, []byte(mg.up), 0644); err != nil {
+			return fmt.Errorf("failed to create migration file: %w", err)
+		}
+	}
 	return nil
 }